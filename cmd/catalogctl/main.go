@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/analysis"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/feed"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/importer"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/export"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/merchant"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/persistence"
+	"github.com/mytheresa/go-hiring-challenge/pkg/database"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const usage = `usage: catalogctl <command> [flags]
+
+commands:
+  import [-dry-run] FILE   import products and variants from a CSV file ("-" reads stdin)
+  export [-format csv|jsonl] [-category CODE] [-price-less-than N] [-o FILE]
+                           export products with variants and final prices (stdout by default)
+  feed [-config FILE] [-o FILE]
+                           write the Google Merchant feed (stdout by default) and report skipped items
+  analyze-rules            run the discount rules against the catalog and report unmatched and
+                           shadowed rules, overlaps and order-sensitive products`
+
+func main() {
+	_ = godotenv.Load(".env")
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	switch command := os.Args[1]; command {
+	case "import":
+		os.Exit(runImport(os.Args[2:]))
+	case "export":
+		runExport(os.Args[2:])
+	case "feed":
+		runFeed(os.Args[2:])
+	case "analyze-rules":
+		os.Exit(runAnalyzeRules(os.Args[2:]))
+	default:
+		log.Fatalf("Unknown command %q\n%s", command, usage)
+	}
+}
+
+func connect() (*gorm.DB, func() error) {
+	host := os.Getenv("POSTGRES_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+
+	return database.New(
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		host,
+		os.Getenv("POSTGRES_PORT"),
+	)
+}
+
+// runImport imports a CSV like POST /catalog/import and returns the exit code:
+// 1 when any row failed. Running servers pick the changes up when their caches expire.
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "validate and report without writing")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		log.Fatal(usage)
+	}
+
+	var input io.Reader = os.Stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("Opening %s failed: %s", path, err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	rows, err := importer.ParseCSV(input)
+	if err != nil {
+		log.Fatalf("Reading CSV failed: %s", err)
+	}
+
+	db, close := connect()
+	defer close()
+
+	service := importer.NewService(persistence.NewTransactor(db), persistence.NewProductRepository(db))
+	report, err := service.Import(context.Background(), rows, *dryRun)
+	if err != nil {
+		log.Fatalf("Importing failed: %s", err)
+	}
+
+	printReport(report)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printReport(report importer.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tPRODUCT\tVARIANT\tRESULT")
+	for _, row := range report.Rows {
+		for _, message := range row.Errors {
+			fmt.Fprintf(w, "%d\t%s\t%s\terror: %s\n", row.Line, row.ProductCode, row.VariantSKU, message)
+		}
+		if len(row.Errors) > 0 || report.Failed > 0 {
+			continue
+		}
+		result := "product " + row.Product.String()
+		if row.VariantSKU != "" {
+			result += ", variant " + row.Variant.String()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", row.Line, row.ProductCode, row.VariantSKU, result)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\nproducts: %d created, %d updated, %d unchanged\n",
+		report.Products.Created, report.Products.Updated, report.Products.Unchanged)
+	fmt.Printf("variants: %d created, %d updated, %d unchanged\n",
+		report.Variants.Created, report.Variants.Updated, report.Variants.Unchanged)
+
+	switch {
+	case report.Failed > 0:
+		fmt.Printf("%d rows failed, nothing was imported\n", report.Failed)
+	case report.DryRun:
+		fmt.Println("dry run, nothing was imported")
+	default:
+		fmt.Println("imported")
+	}
+}
+
+// catalogService prices products with the discount rules stored in the database.
+func catalogService(ctx context.Context, db *gorm.DB) catalog.Service {
+	rules, err := persistence.NewDiscountRuleRepository(db).GetAll(ctx)
+	if err != nil {
+		log.Fatalf("Loading discount rules failed: %s", err)
+	}
+	engine, err := discount.NewEngineFromRules(rules)
+	if err != nil {
+		log.Fatalf("Building discount engine failed: %s", err)
+	}
+	return catalog.NewService(persistence.NewProductRepository(db), engine)
+}
+
+// runExport writes the catalog like GET /catalog/export, priced with the
+// discount rules stored in the database. A failed export removes the output file.
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := flags.String("format", string(export.FormatCSV), "output format: csv or jsonl")
+	categoryCode := flags.String("category", "", "only export products of this category")
+	priceLessThan := flags.String("price-less-than", "", "only export products cheaper than this price")
+	output := flags.String("o", "-", `output file ("-" writes to stdout)`)
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
+		log.Fatal(usage)
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	filters := product.Filter{Category: *categoryCode}
+	if *priceLessThan != "" {
+		price, err := decimal.NewFromString(*priceLessThan)
+		if err != nil {
+			log.Fatalf("Invalid -price-less-than %q", *priceLessThan)
+		}
+		filters.PriceLessThan = &price
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Creating %s failed: %s", *output, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	db, close := connect()
+	defer close()
+
+	ctx := context.Background()
+	writer := export.NewWriter(out, format)
+	err = catalogService(ctx, db).ExportProducts(ctx, filters, writer.Write)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		if *output != "-" {
+			_ = os.Remove(*output)
+		}
+		log.Fatalf("Exporting failed: %s", err)
+	}
+}
+
+// runFeed writes the Google Merchant feed like GET /feeds/google-merchant.xml
+// and lists the skipped variants on stderr. A failed run removes the output file.
+func runFeed(args []string) {
+	flags := flag.NewFlagSet("feed", flag.ExitOnError)
+	configPath := flags.String("config", "config/merchant_feed.yaml", "feed config file")
+	output := flags.String("o", "-", `output file ("-" writes to stdout)`)
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
+		log.Fatal(usage)
+	}
+
+	config, err := merchant.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Creating %s failed: %s", *output, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	db, close := connect()
+	defer close()
+
+	ctx := context.Background()
+	writer := merchant.NewWriter(out, config)
+	report, err := feed.NewService(catalogService(ctx, db), config).Generate(ctx, writer.Write)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		if *output != "-" {
+			_ = os.Remove(*output)
+		}
+		log.Fatalf("Generating feed failed: %s", err)
+	}
+
+	if len(report.Skipped) > 0 {
+		w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PRODUCT\tVARIANT\tSKIPPED BECAUSE")
+		for _, skipped := range report.Skipped {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", skipped.ProductCode, skipped.SKU, strings.Join(skipped.Reasons, "; "))
+		}
+		_ = w.Flush()
+		fmt.Fprintln(os.Stderr)
+	}
+	fmt.Fprintf(os.Stderr, "%d items, %d skipped\n", report.Items, len(report.Skipped))
+}
+
+// runAnalyzeRules reports like GET /discount-rules/analysis and returns the
+// exit code: 1 when a rule matches no product or is shadowed.
+func runAnalyzeRules(args []string) int {
+	flags := flag.NewFlagSet("analyze-rules", flag.ExitOnError)
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
+		log.Fatal(usage)
+	}
+
+	db, close := connect()
+	defer close()
+
+	service := analysis.NewService(persistence.NewDiscountRuleRepository(db), persistence.NewProductRepository(db))
+	result, err := service.Analyze(context.Background())
+	if err != nil {
+		log.Fatalf("Analyzing discount rules failed: %s", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POSITION\tRULE\tMATCHES\tWINS")
+	for _, report := range result.Rules {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\n", report.Rule.Position, describeRule(report.Rule), report.Matches, report.Wins)
+	}
+	_ = w.Flush()
+	fmt.Printf("\n%d products, %d rules\n", result.Products, len(result.Rules))
+
+	if len(result.Unmatched) > 0 {
+		fmt.Println("\nmatching no product:")
+		for _, rule := range result.Unmatched {
+			fmt.Printf("  %s\n", describeRule(rule))
+		}
+	}
+	if len(result.Shadowed) > 0 {
+		fmt.Println("\nshadowed, never winning:")
+		for _, shadow := range result.Shadowed {
+			fmt.Printf("  %s\n    by %s\n", describeRule(shadow.Rule), describeRules(shadow.By))
+		}
+	}
+	if len(result.Overlaps) > 0 {
+		fmt.Println("\nproducts matched by several rules:")
+		for _, overlap := range result.Overlaps {
+			fmt.Printf("  %s: %s\n", overlap.ProductCode, describeRules(overlap.Rules))
+		}
+	}
+	if len(result.OrderSensitive) > 0 {
+		fmt.Println("\nproducts whose winning rule depends on the order:")
+		for _, sensitive := range result.OrderSensitive {
+			price := "same percentage"
+			if sensitive.PriceChanges {
+				price = "price changes"
+			}
+			fmt.Printf("  %s (%s): %s\n", sensitive.ProductCode, price, describeRules(sensitive.Rules))
+		}
+	}
+
+	if len(result.Unmatched) > 0 || len(result.Shadowed) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// describeRule names a rule with its percentage, condition and audience.
+func describeRule(rule discount.Rule) string {
+	description := fmt.Sprintf("%s %s %d%%", rule.Kind, rule.Target, rule.Percentage)
+	if rule.Expression != "" {
+		description += " where " + rule.Expression
+	}
+	for _, list := range []struct {
+		name   string
+		values []string
+	}{{"segments", rule.Audience.Segments}, {"markets", rule.Audience.Markets}, {"channels", rule.Audience.Channels}} {
+		if len(list.values) > 0 {
+			description += fmt.Sprintf(" %s=%s", list.name, strings.Join(list.values, ","))
+		}
+	}
+	return description
+}
+
+func describeRules(rules []discount.Rule) string {
+	descriptions := make([]string, len(rules))
+	for i, rule := range rules {
+		descriptions[i] = describeRule(rule)
+	}
+	return strings.Join(descriptions, "; ")
+}
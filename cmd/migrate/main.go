@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+	"github.com/mytheresa/go-hiring-challenge/pkg/database"
+	"github.com/mytheresa/go-hiring-challenge/pkg/migrate"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up        apply every pending migration
+  down      revert the latest applied migration
+  status    list migrations and whether they are applied
+  goto N    apply or revert migrations until exactly 1..N are applied (0 reverts all)`
+
+func main() {
+	_ = godotenv.Load(".env")
+
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	host := os.Getenv("POSTGRES_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+
+	db, close := database.New(
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		host,
+		os.Getenv("POSTGRES_PORT"),
+	)
+	defer close()
+
+	migrations, err := migrate.Load(os.DirFS(filepath.Join(os.Getenv("POSTGRES_SQL_DIR"), "migrations")))
+	if err != nil {
+		log.Fatalf("Loading migrations failed: %s", err)
+	}
+	migrator := migrate.New(db, migrations)
+	ctx := context.Background()
+
+	var steps []migrate.Step
+	switch command := os.Args[1]; command {
+	case "up":
+		steps, err = migrator.Up(ctx)
+	case "down":
+		steps, err = migrator.Down(ctx)
+	case "goto":
+		if len(os.Args) != 3 {
+			log.Fatal(usage)
+		}
+		version, parseErr := strconv.ParseInt(os.Args[2], 10, 64)
+		if parseErr != nil || version < 0 {
+			log.Fatalf("Invalid version %q", os.Args[2])
+		}
+		steps, err = migrator.Goto(ctx, version)
+	case "status":
+		if err := printStatus(ctx, migrator); err != nil {
+			log.Fatalf("Reading migration status failed: %s", err)
+		}
+		return
+	default:
+		log.Fatalf("Unknown command %q\n%s", command, usage)
+	}
+
+	for _, step := range steps {
+		log.Printf("Migrated %s %03d_%s", step.Direction, step.Version, step.Name)
+	}
+	if err != nil {
+		log.Fatalf("Migrating failed: %s", err)
+	}
+	if len(steps) == 0 {
+		log.Println("Nothing to migrate")
+	}
+}
+
+func printStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	states, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, state := range states {
+		status, appliedAt := "pending", ""
+		if state.Applied {
+			status, appliedAt = "applied", state.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if state.Modified {
+			status = "modified"
+		}
+		if state.Missing {
+			status = "missing"
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", state.Version, state.Name, status, appliedAt)
+	}
+	return w.Flush()
+}
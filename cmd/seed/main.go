@@ -1,20 +1,29 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
 	"path/filepath"
-	"sort"
-	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/seed"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/fixture"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/persistence"
 	"github.com/mytheresa/go-hiring-challenge/pkg/database"
+	"github.com/mytheresa/go-hiring-challenge/pkg/migrate"
 )
 
 func main() {
-	// Load environment variables from .env file
-	if err := godotenv.Load(".env"); err != nil {
-		log.Fatalf("Error loading .env file: %s", err)
+	_ = godotenv.Load(".env")
+
+	file := flag.String("file", "fixtures/catalog.yaml", "YAML or JSON fixture to load")
+	flag.Parse()
+
+	data, err := fixture.Load(*file)
+	if err != nil {
+		log.Fatalf("loading fixture failed: %v", err)
 	}
 
 	host := os.Getenv("POSTGRES_HOST")
@@ -32,37 +41,50 @@ func main() {
 	)
 	defer close()
 
-	dir := os.Getenv("POSTGRES_SQL_DIR")
-	files, err := os.ReadDir(dir)
+	ctx := context.Background()
+
+	// Seed data assumes the latest schema.
+	migrations, err := migrate.Load(os.DirFS(filepath.Join(os.Getenv("POSTGRES_SQL_DIR"), "migrations")))
 	if err != nil {
-		log.Fatalf("reading directory failed: %v", err)
+		log.Fatalf("loading migrations failed: %v", err)
 	}
-
-	// Filter and sort .sql files
-	var sqlFiles []os.DirEntry
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
-			sqlFiles = append(sqlFiles, file)
-		}
+	pending, err := migrate.New(db, migrations).Pending(ctx)
+	if err != nil {
+		log.Fatalf("checking migrations failed: %v", err)
+	}
+	if len(pending) > 0 {
+		log.Fatalf("%d migrations are pending, run the migrate command first", len(pending))
 	}
-	sort.Slice(sqlFiles, func(i, j int) bool {
-		return sqlFiles[i].Name() < sqlFiles[j].Name()
-	})
-
-	for _, file := range sqlFiles {
-		path := filepath.Join(dir, file.Name())
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			log.Printf("reading file %s failed: %v", file.Name(), err)
-		}
 
-		sql := string(content)
-		if err := db.Exec(sql).Error; err != nil {
-			log.Printf("executing %s failed: %v", file.Name(), err)
-			return
-		}
+	service := seed.NewService(
+		persistence.NewTransactor(db),
+		persistence.NewCategoryRepository(db),
+		persistence.NewProductRepository(db),
+		persistence.NewDiscountRuleRepository(db),
+		persistence.NewDiscountCapRepository(db),
+		persistence.NewBasketRuleRepository(db),
+		persistence.NewCouponRepository(db),
+		persistence.NewStockRepository(db),
+	)
+	report, err := service.Seed(ctx, data)
+	if err != nil {
+		log.Fatalf("seeding %s failed: %v", *file, err)
+	}
 
-		log.Printf("Executed %s successfully\n", file.Name())
+	log.Printf("Seeded %s", *file)
+	for _, line := range []struct {
+		kind   string
+		counts seed.Counts
+	}{
+		{"categories", report.Categories},
+		{"products", report.Products},
+		{"discount rules", report.Rules},
+		{"discount caps", report.Caps},
+		{"basket rules", report.BasketRules},
+		{"coupons", report.Coupons},
+		{"stock", report.Stock},
+	} {
+		log.Printf("  %-15s %d created, %d updated, %d unchanged",
+			line.kind, line.counts.Created, line.counts.Updated, line.counts.Unchanged)
 	}
 }
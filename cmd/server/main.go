@@ -4,28 +4,188 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/analysis"
 	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
 	"github.com/mytheresa/go-hiring-challenge/internal/application/category"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/coupon"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/feed"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/importer"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/quote"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/reservation"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/stock"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/suggest"
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/cache"
 	httpHandler "github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/merchant"
 	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/persistence"
 	"github.com/mytheresa/go-hiring-challenge/pkg/database"
+	"github.com/mytheresa/go-hiring-challenge/pkg/migrate"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
-// buildDiscountEngine constructs the discount engine with the required business rules.
-// 30% off boots category, 15% off SKU 000003.
-func buildDiscountEngine() *discount.Engine {
-	strategies := []discount.Strategy{
-		discount.NewCategoryDiscountStrategy("boots", 30),
-		discount.NewSKUDiscountStrategy("000003", 15),
+// suggestRefreshInterval bounds how stale the suggestion index can get
+// when products are written outside the API.
+const suggestRefreshInterval = 5 * time.Minute
+
+// Bounds of the query cache. The entry bound only applies to the in-process store.
+const (
+	queryCacheTTL     = 30 * time.Second
+	queryCacheEntries = 1000
+	redisCachePrefix  = "catalog"
+)
+
+// Per-route query timeouts. Requests that exceed them are answered with 504.
+const (
+	listTimeout     = 3 * time.Second
+	lookupTimeout   = time.Second
+	writeTimeout    = 5 * time.Second
+	importTimeout   = 30 * time.Second
+	analysisTimeout = 30 * time.Second
+)
+
+// shutdownGracePeriod is how long in-flight requests may run after a shutdown
+// signal before their queries are canceled.
+const shutdownGracePeriod = 10 * time.Second
+
+// Default Cache-Control values, overridable per route through the environment.
+const (
+	defaultCatalogCacheControl = "public, max-age=60"
+	defaultProductCacheControl = "public, max-age=300"
+)
+
+// Reservations hold stock for defaultReservationTTL unless RESERVATION_TTL
+// says otherwise; expired ones are released every reservationReapInterval.
+const (
+	defaultReservationTTL   = 15 * time.Minute
+	reservationReapInterval = 30 * time.Second
+)
+
+// defaultFeedConfig is the product feed config shipped with the binary.
+const defaultFeedConfig = "config/merchant_feed.yaml"
+
+// loadFeedConfig reads the product feed config from FEED_CONFIG.
+func loadFeedConfig() feed.Config {
+	config, err := merchant.LoadConfig(getEnv("FEED_CONFIG", defaultFeedConfig))
+	if err != nil {
+		log.Fatalf("Loading feed config failed: %s", err)
+	}
+	return config
+}
+
+// reservationTTL reads how long reservations hold stock from RESERVATION_TTL.
+func reservationTTL() time.Duration {
+	value := getEnv("RESERVATION_TTL", defaultReservationTTL.String())
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Fatalf("Invalid RESERVATION_TTL %q", value)
+	}
+	return ttl
+}
+
+// buildDiscountEngine constructs the discount engine from the stored discount
+// rules, bounded by the guardrails.
+func buildDiscountEngine(ctx context.Context, db *gorm.DB) *discount.Engine {
+	rules, err := persistence.NewDiscountRuleRepository(db).GetAll(ctx)
+	if err != nil {
+		log.Fatalf("Loading discount rules failed: %s", err)
+	}
+	engine, err := discount.NewEngineFromRules(rules)
+	if err != nil {
+		log.Fatalf("Building discount engine failed: %s", err)
+	}
+	return engine.WithGuardrails(loadGuardrails(ctx, db))
+}
+
+// loadGuardrails reads the stored category caps, the minimum sellable price
+// from DISCOUNT_MIN_PRICE and the minimum margin over cost, in percent, from
+// DISCOUNT_MIN_MARGIN.
+func loadGuardrails(ctx context.Context, db *gorm.DB) discount.Guardrails {
+	caps, err := persistence.NewDiscountCapRepository(db).GetAll(ctx)
+	if err != nil {
+		log.Fatalf("Loading discount caps failed: %s", err)
+	}
+
+	minPrice, err := decimal.NewFromString(getEnv("DISCOUNT_MIN_PRICE", "0"))
+	if err != nil {
+		log.Fatalf("Invalid DISCOUNT_MIN_PRICE %q", os.Getenv("DISCOUNT_MIN_PRICE"))
+	}
+	minMargin, err := strconv.Atoi(getEnv("DISCOUNT_MIN_MARGIN", "0"))
+	if err != nil {
+		log.Fatalf("Invalid DISCOUNT_MIN_MARGIN %q", os.Getenv("DISCOUNT_MIN_MARGIN"))
+	}
+
+	guardrails := discount.Guardrails{Caps: caps, MinPrice: minPrice, MinMargin: minMargin}
+	if err := guardrails.Validate(); err != nil {
+		log.Fatalf("Loading discount guardrails failed: %s", err)
+	}
+	log.Printf("Discount guardrails: %d category caps, minimum price %s, minimum margin %d%%",
+		len(caps), minPrice.StringFixed(2), minMargin)
+	return guardrails
+}
+
+// buildBasketEngine constructs the basket promotion engine from the stored basket rules.
+func buildBasketEngine(ctx context.Context, db *gorm.DB) *discount.BasketEngine {
+	rules, err := persistence.NewBasketRuleRepository(db).GetAll(ctx)
+	if err != nil {
+		log.Fatalf("Loading basket rules failed: %s", err)
+	}
+	engine, err := discount.NewBasketEngine(rules)
+	if err != nil {
+		log.Fatalf("Building basket engine failed: %s", err)
+	}
+	return engine
+}
+
+// buildCacheStore selects the query cache backend from CACHE_BACKEND: "memory" (default)
+// or "redis", which shares entries and invalidations between replicas through REDIS_ADDR.
+func buildCacheStore(ctx context.Context) cache.Store {
+	switch backend := getEnv("CACHE_BACKEND", "memory"); backend {
+	case "redis":
+		store := cache.NewRedisStore(getEnv("REDIS_ADDR", "localhost:6379"), redisCachePrefix)
+		if err := store.Sync(); err != nil {
+			log.Printf("Reading cache generation failed: %s", err)
+		}
+		go store.Run(ctx)
+		return store
+	case "memory":
+		return cache.NewMemoryStore(queryCacheEntries)
+	default:
+		log.Fatalf("Unknown CACHE_BACKEND %q", backend)
+		return nil
+	}
+}
+
+// checkMigrations refuses to start when REQUIRE_MIGRATIONS is set and the schema
+// is behind the migrations shipped with the binary or no longer matches them.
+func checkMigrations(ctx context.Context, db *gorm.DB) {
+	if getEnv("REQUIRE_MIGRATIONS", "false") != "true" {
+		return
+	}
+
+	migrations, err := migrate.Load(os.DirFS(filepath.Join(os.Getenv("POSTGRES_SQL_DIR"), "migrations")))
+	if err != nil {
+		log.Fatalf("Loading migrations failed: %s", err)
+	}
+	pending, err := migrate.New(db, migrations).Pending(ctx)
+	if err != nil {
+		log.Fatalf("Checking migrations failed: %s", err)
+	}
+	if len(pending) > 0 {
+		log.Fatalf("Refusing to start: %d migrations are pending, the first is %03d_%s",
+			len(pending), pending[0].Version, pending[0].Name)
 	}
-	return discount.NewEngine(strategies)
 }
 
 func main() {
@@ -49,25 +209,85 @@ func main() {
 	)
 	defer close()
 
+	checkMigrations(ctx, db)
+
 	productRepo := persistence.NewProductRepository(db)
 	categoryRepo := persistence.NewCategoryRepository(db)
+	queryCache := cache.New(buildCacheStore(ctx), queryCacheTTL)
+	productCache := cache.NewProductRepository(productRepo, queryCache)
+	categoryCache := cache.NewCategoryRepository(categoryRepo, queryCache)
 
-	discountEngine := buildDiscountEngine()
-	catalogService := catalog.NewService(productRepo, discountEngine)
-	categoryService := category.NewService(categoryRepo)
+	discountEngine := buildDiscountEngine(ctx, db)
+	rulesUpdatedAt := time.Now()
+	catalogService := catalog.NewService(productCache, discountEngine)
+	suggestService := suggest.NewService(productRepo, categoryRepo)
+	if err := suggestService.Refresh(ctx); err != nil {
+		log.Printf("Building suggestion index failed: %s", err)
+	}
+	go suggestService.Run(ctx, suggestRefreshInterval)
+
+	categoryService := category.NewService(categoryCache, discountEngine, suggestService, queryCache)
+	feedConfig := loadFeedConfig()
+	feedService := feed.NewService(catalogService, feedConfig)
+	importService := importer.NewService(persistence.NewTransactor(db), productRepo, suggestService, queryCache)
+	couponService := coupon.NewService(persistence.NewCouponRepository(db))
+	quoteService := quote.NewService(productRepo, discountEngine, buildBasketEngine(ctx, db), couponService)
+	stockService := stock.NewService(persistence.NewStockRepository(db), queryCache)
+	reservationService := reservation.NewService(persistence.NewReservationRepository(db), reservationTTL(), queryCache)
+	analysisService := analysis.NewService(persistence.NewDiscountRuleRepository(db), productRepo)
+	go reservationService.Run(ctx, reservationReapInterval)
 
 	catalogHandler := httpHandler.NewCatalogHandler(catalogService)
 	categoryHandler := httpHandler.NewCategoryHandler(categoryService)
+	suggestHandler := httpHandler.NewSuggestHandler(suggestService)
+	variantHandler := httpHandler.NewVariantHandler(catalogService)
+	cacheHandler := httpHandler.NewCacheHandler(queryCache)
+	importHandler := httpHandler.NewImportHandler(importService)
+	feedHandler := httpHandler.NewFeedHandler(feedService, feedConfig)
+	stockHandler := httpHandler.NewStockHandler(stockService)
+	quoteHandler := httpHandler.NewQuoteHandler(quoteService)
+	reservationHandler := httpHandler.NewReservationHandler(reservationService)
+	couponHandler := httpHandler.NewCouponHandler(couponService)
+	analysisHandler := httpHandler.NewAnalysisHandler(analysisService)
+	caching := httpHandler.NewCaching(rulesUpdatedAt)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /catalog", catalogHandler.HandleGet)
-	mux.HandleFunc("GET /catalog/{code}", catalogHandler.HandleGetByCode)
-	mux.HandleFunc("GET /categories", categoryHandler.HandleGet)
-	mux.HandleFunc("POST /categories", categoryHandler.HandlePost)
+	mux.HandleFunc("GET /catalog", caching.Wrap(
+		getEnv("CATALOG_CACHE_CONTROL", defaultCatalogCacheControl),
+		httpHandler.WithPricingContext(httpHandler.WithTimeout(listTimeout, catalogHandler.HandleGet))))
+	mux.HandleFunc("POST /catalog/batch", httpHandler.WithPricingContext(httpHandler.WithTimeout(listTimeout, catalogHandler.HandlePostBatch)))
+	mux.HandleFunc("GET /catalog/export", httpHandler.WithPricingContext(catalogHandler.HandleExport))
+	mux.HandleFunc("POST /catalog/import", httpHandler.WithTimeout(importTimeout, importHandler.HandlePost))
+	mux.HandleFunc("GET /catalog/suggest", suggestHandler.HandleGet)
+	mux.HandleFunc("GET /catalog/{code}", caching.Wrap(
+		getEnv("PRODUCT_CACHE_CONTROL", defaultProductCacheControl),
+		httpHandler.WithPricingContext(httpHandler.WithTimeout(lookupTimeout, catalogHandler.HandleGetByCode))))
+	mux.HandleFunc("GET /variants/{sku}", httpHandler.WithPricingContext(httpHandler.WithTimeout(lookupTimeout, variantHandler.HandleGetBySKU)))
+	mux.HandleFunc("GET /variants/{sku}/stock", httpHandler.WithTimeout(lookupTimeout, stockHandler.HandleGet))
+	mux.HandleFunc("PUT /variants/{sku}/stock", httpHandler.WithTimeout(writeTimeout, stockHandler.HandlePut))
+	mux.HandleFunc("POST /quote", httpHandler.WithPricingContext(httpHandler.WithTimeout(listTimeout, quoteHandler.HandlePost)))
+	mux.HandleFunc("GET /coupons/{code}", httpHandler.WithTimeout(lookupTimeout, couponHandler.HandleGet))
+	mux.HandleFunc("POST /coupons/{code}/redemptions", httpHandler.WithTimeout(writeTimeout, couponHandler.HandleRedeem))
+	mux.HandleFunc("POST /reservations", httpHandler.WithTimeout(writeTimeout, reservationHandler.HandlePost))
+	mux.HandleFunc("GET /reservations/{id}", httpHandler.WithTimeout(lookupTimeout, reservationHandler.HandleGet))
+	mux.HandleFunc("POST /reservations/{id}/confirm", httpHandler.WithTimeout(writeTimeout, reservationHandler.HandleConfirm))
+	mux.HandleFunc("POST /reservations/{id}/cancel", httpHandler.WithTimeout(writeTimeout, reservationHandler.HandleCancel))
+	mux.HandleFunc("GET /categories", httpHandler.WithPricingContext(httpHandler.WithTimeout(listTimeout, categoryHandler.HandleGet)))
+	mux.HandleFunc("POST /categories", httpHandler.WithTimeout(writeTimeout, categoryHandler.HandlePost))
+	mux.HandleFunc("GET /discount-rules/analysis", httpHandler.WithTimeout(analysisTimeout, analysisHandler.HandleGet))
+	mux.HandleFunc("GET /cache/stats", cacheHandler.HandleGetStats)
+	mux.HandleFunc("GET /feeds/google-merchant.xml", feedHandler.HandleGetGoogleMerchant)
+	mux.HandleFunc("GET /feeds/google-merchant/report", feedHandler.HandleGetGoogleMerchantReport)
+
+	// Request contexts outlive the shutdown signal so that in-flight requests can
+	// finish; they are canceled once the grace period is over.
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 
 	srv := &http.Server{
-		Addr:    fmt.Sprintf("0.0.0.0:%s", os.Getenv("HTTP_PORT")),
-		Handler: mux,
+		Addr:        fmt.Sprintf("0.0.0.0:%s", os.Getenv("HTTP_PORT")),
+		Handler:     mux,
+		BaseContext: func(net.Listener) context.Context { return requestCtx },
 	}
 
 	go func() {
@@ -80,7 +300,18 @@ func main() {
 
 	<-ctx.Done()
 	log.Println("Shutting down server...")
-	if err := srv.Shutdown(context.Background()); err != nil {
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Server shutdown error: %s", err)
+		cancelRequests()
+	}
+}
+
+// getEnv returns the environment variable or the fallback when it is unset.
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
 	}
+	return fallback
 }
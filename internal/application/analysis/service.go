@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
+
+// batchSize is the number of products read per batch while analyzing.
+const batchSize = 500
+
+// RuleRepository reads the stored discount rules.
+type RuleRepository interface {
+	GetAll(ctx context.Context) ([]discount.Rule, error)
+}
+
+// ProductRepository streams the catalog with all relations.
+type ProductRepository interface {
+	Stream(ctx context.Context, filters product.Filter, batchSize int, fn func([]product.Product) error) error
+}
+
+// Service defines ops for discount rule analysis.
+type Service interface {
+	Analyze(ctx context.Context) (discount.Analysis, error)
+}
+
+type service struct {
+	rules    RuleRepository
+	products ProductRepository
+}
+
+// NewService creates a new discount rule analysis service.
+func NewService(rules RuleRepository, products ProductRepository) Service {
+	return &service{rules: rules, products: products}
+}
+
+// Analyze runs the stored discount rules against every product of the
+// catalog. The rules are read on each call, so the analysis reflects rules
+// seeded after the server built its discount engine.
+func (s *service) Analyze(ctx context.Context) (discount.Analysis, error) {
+	rules, err := s.rules.GetAll(ctx)
+	if err != nil {
+		return discount.Analysis{}, err
+	}
+	analyzer, err := discount.NewAnalyzer(rules)
+	if err != nil {
+		return discount.Analysis{}, err
+	}
+
+	err = s.products.Stream(ctx, product.Filter{}, batchSize, func(products []product.Product) error {
+		for _, p := range products {
+			analyzer.Add(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return discount.Analysis{}, err
+	}
+	return analyzer.Analysis(), nil
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRuleRepository struct {
+	rules []discount.Rule
+	err   error
+}
+
+func (m *mockRuleRepository) GetAll(ctx context.Context) ([]discount.Rule, error) {
+	return m.rules, m.err
+}
+
+type mockProductRepository struct {
+	batches [][]product.Product
+	err     error
+}
+
+func (m *mockProductRepository) Stream(ctx context.Context, filters product.Filter, batchSize int, fn func([]product.Product) error) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, batch := range m.batches {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestService_Analyze(t *testing.T) {
+	boots := &product.Category{Code: "boots"}
+	rules := []discount.Rule{
+		{Kind: discount.RuleCategory, Target: "boots", Percentage: 30, Position: 1},
+		{Kind: discount.RuleExpression, Target: "premium", Expression: "price > 100", Percentage: 15, Position: 2},
+		{Kind: discount.RuleCategory, Target: "shoes", Percentage: 10, Position: 3},
+	}
+	products := &mockProductRepository{batches: [][]product.Product{
+		{{Code: "PROD009", Price: decimal.NewFromInt(90), Category: boots, Variants: []product.Variant{{SKU: "000003"}}}},
+		{{Code: "PROD010", Price: decimal.NewFromInt(120), Category: boots}},
+	}}
+
+	t.Run("analyzes the stored rules over every batch of products", func(t *testing.T) {
+		service := NewService(&mockRuleRepository{rules: rules}, products)
+
+		analysis, err := service.Analyze(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, analysis.Products)
+		assert.Equal(t, []discount.Rule{rules[2]}, analysis.Unmatched)
+		require.Len(t, analysis.Shadowed, 1)
+		assert.Equal(t, rules[1], analysis.Shadowed[0].Rule)
+		require.Len(t, analysis.OrderSensitive, 1)
+		assert.Equal(t, "PROD010", analysis.OrderSensitive[0].ProductCode)
+		assert.True(t, analysis.OrderSensitive[0].PriceChanges)
+	})
+
+	t.Run("fails on invalid stored rules", func(t *testing.T) {
+		service := NewService(&mockRuleRepository{rules: []discount.Rule{{Kind: "brand", Target: "acme"}}}, products)
+
+		_, err := service.Analyze(context.Background())
+
+		assert.ErrorIs(t, err, discount.ErrInvalidRule)
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		_, err := NewService(&mockRuleRepository{err: boom}, products).Analyze(context.Background())
+		assert.ErrorIs(t, err, boom)
+
+		_, err = NewService(&mockRuleRepository{rules: rules}, &mockProductRepository{err: boom}).Analyze(context.Background())
+		assert.ErrorIs(t, err, boom)
+	})
+}
@@ -1,34 +1,87 @@
 package catalog
 
 import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
-	"github.com/shopspring/decimal"
 )
 
 // ProductRepository defines operations for product persistence.
 type ProductRepository interface {
-	GetAll() ([]product.Product, error)
-	GetFiltered(offset, limit int, filters product.Filter) ([]product.Product, int64, error)
-	GetByCode(code string) (*product.Product, error)
+	GetAll(ctx context.Context) ([]product.Product, error)
+	GetFiltered(ctx context.Context, offset, limit int, filters product.Filter, relations product.Relations) ([]product.Product, int64, error)
+	GetByCode(ctx context.Context, code string, relations product.Relations) (*product.Product, error)
+	GetByCodes(ctx context.Context, codes []string) ([]product.Product, error)
+	GetByVariantSKU(ctx context.Context, sku string) (*product.Product, error)
+	GetFacets(ctx context.Context, filters product.Filter, req product.FacetRequest, sale product.SaleCriteria) (product.Facets, error)
+	Stream(ctx context.Context, filters product.Filter, batchSize int, fn func([]product.Product) error) error
 }
 
-// DiscountEngine defines operations for discount calculation.
+// exportBatchSize is the number of products read per batch when exporting.
+const exportBatchSize = 500
+
+// DiscountEngine defines operations for discount calculation. Discounts
+// depend on the pricing context of the customer asking and are bounded by the
+// guardrails of the engine.
 type DiscountEngine interface {
-	ApplyDiscount(p product.Product) decimal.Decimal
-	GetDiscountPercentage(p product.Product) int
-	GetVariantDiscountPercentage(sku string, p product.Product) int
+	PriceProduct(pc discount.PricingContext, p product.Product) discount.Price
+	PriceVariant(pc discount.PricingContext, v product.Variant, p product.Product) discount.Price
+	SaleCriteria(pc discount.PricingContext) product.SaleCriteria
+	RequiredRelations() product.Relations
 }
 
-// VariantDiscount holds discount information for a variant.
+// VariantDiscount holds discount information for a variant. Limit explains
+// how a guardrail changed the discount, if one did.
 type VariantDiscount struct {
 	DiscountedPrice float64
 	Percentage      int
+	Limit           *discount.Limit
+}
+
+// ProductDetail holds a product together with its discount information.
+type ProductDetail struct {
+	Product          product.Product
+	DiscountedPrice  float64
+	Percentage       int
+	Limit            *discount.Limit
+	VariantDiscounts map[string]VariantDiscount
+}
+
+// VariantDetail holds a variant together with its parent product and discount.
+type VariantDetail struct {
+	Variant         product.Variant
+	DiscountedPrice float64
+	Percentage      int
+	Limit           *discount.Limit
+	Parent          ProductDetail
+}
+
+// Projection describes which parts of a product the caller needs.
+// Relations that are not needed are not loaded from the repository.
+type Projection struct {
+	Relations product.Relations
+	// Pricing requests discount information. Discount strategies may need
+	// relations of their own, which are then loaded as well.
+	Pricing bool
 }
 
-// Service defines operations for the catalog business logic.
+// FullProjection loads every relation and calculates discounts.
+func FullProjection() Projection {
+	return Projection{Relations: product.AllRelations(), Pricing: true}
+}
+
+// Service defines operations for the catalog business logic. Prices are
+// computed for the discount.PricingContext carried by ctx.
 type Service interface {
-	GetProducts(offset, limit int, filters product.Filter) ([]product.Product, []float64, []int, int64, error)
-	GetProductByCode(code string) (*product.Product, float64, int, map[string]VariantDiscount, error)
+	GetProducts(ctx context.Context, offset, limit int, filters product.Filter, projection Projection) ([]ProductDetail, int64, error)
+	GetProductByCode(ctx context.Context, code string, projection Projection) (*ProductDetail, error)
+	GetProductsByCodes(ctx context.Context, codes []string) ([]ProductDetail, []string, error)
+	GetVariantBySKU(ctx context.Context, sku string) (*VariantDetail, error)
+	GetFacets(ctx context.Context, filters product.Filter, req product.FacetRequest) (product.Facets, error)
+	ExportProducts(ctx context.Context, filters product.Filter, fn func(ProductDetail) error) error
 }
 
 type service struct {
@@ -45,49 +98,170 @@ func NewService(repo ProductRepository, discountEngine DiscountEngine) Service {
 }
 
 // GetProducts retrieves filtered and paginated products with discounts.
-// Returns products, discounted prices, discount percentages, and total count.
-func (s *service) GetProducts(offset, limit int, filters product.Filter) ([]product.Product, []float64, []int, int64, error) {
-	products, total, err := s.repo.GetFiltered(offset, limit, filters)
+// Returns the products with their discount information and the total count.
+func (s *service) GetProducts(ctx context.Context, offset, limit int, filters product.Filter, projection Projection) ([]ProductDetail, int64, error) {
+	products, total, err := s.repo.GetFiltered(ctx, offset, limit, filters, s.relationsFor(projection))
 	if err != nil {
-		return nil, nil, nil, 0, err
+		return nil, 0, err
 	}
 
-	discountedPrices := make([]float64, len(products))
-	discountPercentages := make([]int, len(products))
-
+	pc := discount.PricingContextFrom(ctx)
+	details := make([]ProductDetail, len(products))
 	for i, p := range products {
-		discountedPrices[i] = s.discountEngine.ApplyDiscount(p).InexactFloat64()
-		discountPercentages[i] = s.discountEngine.GetDiscountPercentage(p)
+		details[i] = s.detail(pc, p, projection)
 	}
 
-	return products, discountedPrices, discountPercentages, total, nil
+	return details, total, nil
 }
 
 // GetProductByCode retrieves a product by its code with discount applied.
-// Returns product, discounted price, discount percentage, and variant discounts.
-func (s *service) GetProductByCode(code string) (*product.Product, float64, int, map[string]VariantDiscount, error) {
-	p, err := s.repo.GetByCode(code)
+// Returns the product with its discount and the discount of each variant.
+func (s *service) GetProductByCode(ctx context.Context, code string, projection Projection) (*ProductDetail, error) {
+	p, err := s.repo.GetByCode(ctx, code, s.relationsFor(projection))
+	if err != nil {
+		return nil, err
+	}
+
+	detail := s.detail(discount.PricingContextFrom(ctx), *p, projection)
+	return &detail, nil
+}
+
+// GetProductsByCodes retrieves several products by code in a single repository call.
+// Products are returned in request order, without duplicates, and codes that do not
+// exist are reported separately instead of failing the whole batch.
+func (s *service) GetProductsByCodes(ctx context.Context, codes []string) ([]ProductDetail, []string, error) {
+	products, err := s.repo.GetByCodes(ctx, codes)
 	if err != nil {
-		return nil, 0, 0, nil, err
+		return nil, nil, err
 	}
 
-	discountedPrice := s.discountEngine.ApplyDiscount(*p).InexactFloat64()
-	discountPercentage := s.discountEngine.GetDiscountPercentage(*p)
+	byCode := make(map[string]product.Product, len(products))
+	for _, p := range products {
+		byCode[p.Code] = p
+	}
 
-	// Calculate discounts for each variant
-	variantDiscounts := make(map[string]VariantDiscount)
+	pc := discount.PricingContextFrom(ctx)
+	details := make([]ProductDetail, 0, len(products))
+	missing := make([]string, 0)
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		p, ok := byCode[code]
+		if !ok {
+			missing = append(missing, code)
+			continue
+		}
+
+		details = append(details, s.detail(pc, p, FullProjection()))
+	}
+
+	return details, missing, nil
+}
+
+// GetVariantBySKU retrieves a variant by SKU with its parent product, category and discount.
+func (s *service) GetVariantBySKU(ctx context.Context, sku string) (*VariantDetail, error) {
+	p, err := s.repo.GetByVariantSKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := discount.PricingContextFrom(ctx)
 	for _, v := range p.Variants {
-		percentage := s.discountEngine.GetVariantDiscountPercentage(v.SKU, *p)
-		discounted := v.Price.InexactFloat64()
-		if percentage > 0 {
-			discount := v.Price.Mul(decimal.NewFromInt(int64(percentage))).Div(decimal.NewFromInt(100))
-			discounted = v.Price.Sub(discount).InexactFloat64()
+		if v.SKU == sku {
+			variantDiscount := s.variantDiscount(pc, v, *p)
+			return &VariantDetail{
+				Variant:         v,
+				DiscountedPrice: variantDiscount.DiscountedPrice,
+				Percentage:      variantDiscount.Percentage,
+				Limit:           variantDiscount.Limit,
+				Parent:          s.detail(pc, *p, FullProjection()),
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("variant %s in product %s: %w", sku, p.Code, product.ErrNotFound)
+}
+
+// ExportProducts calls fn for every product matching the filters, with all
+// relations and discounts, reading them in batches so that the catalog is
+// never held in memory. It stops at the first error returned by fn.
+func (s *service) ExportProducts(ctx context.Context, filters product.Filter, fn func(ProductDetail) error) error {
+	pc := discount.PricingContextFrom(ctx)
+	return s.repo.Stream(ctx, filters, exportBatchSize, func(products []product.Product) error {
+		for _, p := range products {
+			if err := fn(s.detail(pc, p, FullProjection())); err != nil {
+				return err
+			}
 		}
-		variantDiscounts[v.SKU] = VariantDiscount{
-			DiscountedPrice: discounted,
-			Percentage:      percentage,
+		return nil
+	})
+}
+
+// relationsFor returns the relations to load for a projection, including
+// the ones the discount strategies depend on when pricing is requested.
+func (s *service) relationsFor(projection Projection) product.Relations {
+	if projection.Pricing {
+		return projection.Relations.Union(s.discountEngine.RequiredRelations())
+	}
+	return projection.Relations
+}
+
+// detail calculates the discount information of a product. Without pricing,
+// the product is reported at its original price.
+func (s *service) detail(pc discount.PricingContext, p product.Product, projection Projection) ProductDetail {
+	if !projection.Pricing {
+		return ProductDetail{
+			Product:          p,
+			DiscountedPrice:  p.Price.InexactFloat64(),
+			VariantDiscounts: map[string]VariantDiscount{},
 		}
 	}
 
-	return p, discountedPrice, discountPercentage, variantDiscounts, nil
+	price := s.discountEngine.PriceProduct(pc, p)
+	logLimit(p.Code, price.Limit)
+	return ProductDetail{
+		Product:          p,
+		DiscountedPrice:  price.Final.InexactFloat64(),
+		Percentage:       price.Percentage,
+		Limit:            price.Limit,
+		VariantDiscounts: s.variantDiscounts(pc, p),
+	}
+}
+
+// variantDiscounts calculates the discount for each variant of a product.
+func (s *service) variantDiscounts(pc discount.PricingContext, p product.Product) map[string]VariantDiscount {
+	variantDiscounts := make(map[string]VariantDiscount)
+	for _, v := range p.Variants {
+		variantDiscounts[v.SKU] = s.variantDiscount(pc, v, p)
+	}
+	return variantDiscounts
+}
+
+// variantDiscount calculates the discount for a single variant of a product.
+func (s *service) variantDiscount(pc discount.PricingContext, v product.Variant, p product.Product) VariantDiscount {
+	price := s.discountEngine.PriceVariant(pc, v, p)
+	logLimit(v.SKU, price.Limit)
+	return VariantDiscount{
+		DiscountedPrice: price.Final.InexactFloat64(),
+		Percentage:      price.Percentage,
+		Limit:           price.Limit,
+	}
+}
+
+// logLimit logs the guardrail that changed the discount of a product or variant.
+func logLimit(code string, limit *discount.Limit) {
+	if limit != nil {
+		log.Printf("pricing: discount of %s limited by %s", code, limit)
+	}
+}
+
+// GetFacets computes facet counts for the products matching the filters.
+// On-sale counts are based on the strategies the discount engine applies to
+// the pricing context.
+func (s *service) GetFacets(ctx context.Context, filters product.Filter, req product.FacetRequest) (product.Facets, error) {
+	return s.repo.GetFacets(ctx, filters, req, s.discountEngine.SaleCriteria(discount.PricingContextFrom(ctx)))
 }
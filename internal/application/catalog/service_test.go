@@ -1,9 +1,11 @@
 package catalog
 
 import (
+	"context"
 	"errors"
 	"testing"
 
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -11,23 +13,29 @@ import (
 )
 
 type mockRepository struct {
-	products []product.Product
-	total    int64
-	err      error
+	products  []product.Product
+	total     int64
+	err       error
+	facets    product.Facets
+	saleInput product.SaleCriteria
+	relations product.Relations
+	batchSize int
 }
 
-func (m *mockRepository) GetAll() ([]product.Product, error) {
+func (m *mockRepository) GetAll(ctx context.Context) ([]product.Product, error) {
 	return m.products, m.err
 }
 
-func (m *mockRepository) GetFiltered(offset, limit int, filters product.Filter) ([]product.Product, int64, error) {
+func (m *mockRepository) GetFiltered(ctx context.Context, offset, limit int, filters product.Filter, relations product.Relations) ([]product.Product, int64, error) {
+	m.relations = relations
 	if m.err != nil {
 		return nil, 0, m.err
 	}
 	return m.products, m.total, nil
 }
 
-func (m *mockRepository) GetByCode(code string) (*product.Product, error) {
+func (m *mockRepository) GetByCode(ctx context.Context, code string, relations product.Relations) (*product.Product, error) {
+	m.relations = relations
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -39,22 +47,81 @@ func (m *mockRepository) GetByCode(code string) (*product.Product, error) {
 	return nil, errors.New("product not found")
 }
 
+func (m *mockRepository) GetByCodes(ctx context.Context, codes []string) ([]product.Product, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make([]product.Product, 0)
+	for _, p := range m.products {
+		for _, code := range codes {
+			if p.Code == code {
+				result = append(result, p)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRepository) GetByVariantSKU(ctx context.Context, sku string) (*product.Product, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, p := range m.products {
+		for _, v := range p.Variants {
+			if v.SKU == sku {
+				return &p, nil
+			}
+		}
+	}
+	return nil, errors.New("product not found")
+}
+
+func (m *mockRepository) GetFacets(ctx context.Context, filters product.Filter, req product.FacetRequest, sale product.SaleCriteria) (product.Facets, error) {
+	m.saleInput = sale
+	if m.err != nil {
+		return product.Facets{}, m.err
+	}
+	return m.facets, nil
+}
+
+func (m *mockRepository) Stream(ctx context.Context, filters product.Filter, batchSize int, fn func([]product.Product) error) error {
+	m.batchSize = batchSize
+	if m.err != nil {
+		return m.err
+	}
+	for start := 0; start < len(m.products); start += batchSize {
+		end := min(start+batchSize, len(m.products))
+		if err := fn(m.products[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type mockDiscountEngine struct {
 	discountPercentage        int
 	discountedPrice           decimal.Decimal
 	variantDiscountPercentage int
+	saleCriteria              product.SaleCriteria
+	requiredRelations         product.Relations
 }
 
-func (m *mockDiscountEngine) ApplyDiscount(p product.Product) decimal.Decimal {
-	return m.discountedPrice
+func (m *mockDiscountEngine) PriceProduct(pc discount.PricingContext, p product.Product) discount.Price {
+	return discount.Price{Original: p.Price, Final: m.discountedPrice, Percentage: m.discountPercentage}
 }
 
-func (m *mockDiscountEngine) GetDiscountPercentage(p product.Product) int {
-	return m.discountPercentage
+func (m *mockDiscountEngine) PriceVariant(pc discount.PricingContext, v product.Variant, p product.Product) discount.Price {
+	off := v.Price.Mul(decimal.NewFromInt(int64(m.variantDiscountPercentage))).Div(decimal.NewFromInt(100))
+	return discount.Price{Original: v.Price, Final: v.Price.Sub(off), Percentage: m.variantDiscountPercentage}
 }
 
-func (m *mockDiscountEngine) GetVariantDiscountPercentage(sku string, p product.Product) int {
-	return m.variantDiscountPercentage
+func (m *mockDiscountEngine) SaleCriteria(pc discount.PricingContext) product.SaleCriteria {
+	return m.saleCriteria
+}
+
+func (m *mockDiscountEngine) RequiredRelations() product.Relations {
+	return m.requiredRelations
 }
 
 func TestService_GetProducts(t *testing.T) {
@@ -69,15 +136,86 @@ func TestService_GetProducts(t *testing.T) {
 		}
 		service := NewService(repo, discountEngine)
 
-		products, discountedPrices, discountPercentages, total, err := service.GetProducts(0, 10, product.Filter{})
+		details, total, err := service.GetProducts(context.Background(), 0, 10, product.Filter{}, FullProjection())
 
 		require.NoError(t, err)
-		assert.Len(t, products, 1)
-		assert.Len(t, discountedPrices, 1)
-		assert.Len(t, discountPercentages, 1)
+		assert.Len(t, details, 1)
 		assert.Equal(t, int64(1), total)
-		assert.Equal(t, 7.69, discountedPrices[0])
-		assert.Equal(t, 30, discountPercentages[0])
+		assert.Equal(t, 7.69, details[0].DiscountedPrice)
+		assert.Equal(t, 30, details[0].Percentage)
+	})
+
+	t.Run("loads relations required by the discount engine when pricing", func(t *testing.T) {
+		repo := &mockRepository{}
+		discountEngine := &mockDiscountEngine{requiredRelations: product.Relations{Variants: true}}
+		service := NewService(repo, discountEngine)
+
+		_, _, err := service.GetProducts(context.Background(), 0, 10, product.Filter{}, Projection{Relations: product.Relations{Category: true}, Pricing: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.AllRelations(), repo.relations)
+	})
+
+	t.Run("skips relations and discounts when pricing is not requested", func(t *testing.T) {
+		repo := &mockRepository{products: []product.Product{
+			{ID: 1, Code: "PROD001", Price: decimal.NewFromFloat(10.99)},
+		}, total: 1}
+		discountEngine := &mockDiscountEngine{
+			discountPercentage: 30,
+			discountedPrice:    decimal.NewFromFloat(7.69),
+			requiredRelations:  product.AllRelations(),
+		}
+		service := NewService(repo, discountEngine)
+
+		details, _, err := service.GetProducts(context.Background(), 0, 10, product.Filter{}, Projection{})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Relations{}, repo.relations)
+		assert.Equal(t, 10.99, details[0].DiscountedPrice)
+		assert.Equal(t, 0, details[0].Percentage)
+	})
+
+	t.Run("prices for the pricing context carried by the request", func(t *testing.T) {
+		repo := &mockRepository{products: []product.Product{
+			{ID: 1, Code: "PROD009", Price: decimal.NewFromInt(100), Category: &product.Category{Code: "boots"}},
+		}, total: 1}
+		engine, err := discount.NewEngineFromRules([]discount.Rule{
+			{Kind: discount.RuleCategory, Target: "boots", Percentage: 40, Audience: discount.Audience{Segments: []string{"vip"}}},
+			{Kind: discount.RuleCategory, Target: "boots", Percentage: 30},
+		})
+		require.NoError(t, err)
+		service := NewService(repo, engine)
+
+		vip := discount.WithPricingContext(context.Background(), discount.PricingContext{Segment: "vip"})
+		details, _, err := service.GetProducts(vip, 0, 10, product.Filter{}, FullProjection())
+		require.NoError(t, err)
+		assert.Equal(t, 40, details[0].Percentage)
+		assert.Equal(t, 60.0, details[0].DiscountedPrice)
+
+		details, _, err = service.GetProducts(context.Background(), 0, 10, product.Filter{}, FullProjection())
+		require.NoError(t, err)
+		assert.Equal(t, 30, details[0].Percentage)
+		assert.Equal(t, 70.0, details[0].DiscountedPrice)
+	})
+
+	t.Run("reports the guardrail that limited a discount", func(t *testing.T) {
+		repo := &mockRepository{products: []product.Product{
+			{ID: 1, Code: "PROD009", Price: decimal.NewFromInt(100), Category: &product.Category{Code: "boots"}, Variants: []product.Variant{
+				{SKU: "000003", Price: decimal.NewFromInt(100)},
+			}},
+		}, total: 1}
+		engine, err := discount.NewEngineFromRules([]discount.Rule{{Kind: discount.RuleCategory, Target: "boots", Percentage: 30}})
+		require.NoError(t, err)
+		service := NewService(repo, engine.WithGuardrails(discount.Guardrails{Caps: []discount.Cap{{Category: "boots", Percentage: 20}}}))
+
+		details, _, err := service.GetProducts(context.Background(), 0, 10, product.Filter{}, FullProjection())
+
+		require.NoError(t, err)
+		assert.Equal(t, 20, details[0].Percentage)
+		assert.Equal(t, 80.0, details[0].DiscountedPrice)
+		require.NotNil(t, details[0].Limit)
+		assert.Equal(t, discount.LimitCap, details[0].Limit.Kind)
+		assert.Equal(t, details[0].Limit, details[0].VariantDiscounts["000003"].Limit)
 	})
 
 	t.Run("returns error when repository fails", func(t *testing.T) {
@@ -85,7 +223,156 @@ func TestService_GetProducts(t *testing.T) {
 		discountEngine := &mockDiscountEngine{}
 		service := NewService(repo, discountEngine)
 
-		_, _, _, _, err := service.GetProducts(0, 10, product.Filter{})
+		_, _, err := service.GetProducts(context.Background(), 0, 10, product.Filter{}, FullProjection())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_GetFacets(t *testing.T) {
+	t.Run("passes the engine sale criteria to the repository", func(t *testing.T) {
+		expected := product.Facets{OnSale: &product.OnSaleCount{OnSale: 2, NotOnSale: 7}}
+		repo := &mockRepository{facets: expected}
+		criteria := product.SaleCriteria{CategoryCodes: []string{"boots"}, SKUs: []string{"000003"}}
+		discountEngine := &mockDiscountEngine{saleCriteria: criteria}
+		service := NewService(repo, discountEngine)
+
+		facets, err := service.GetFacets(context.Background(), product.Filter{}, product.FacetRequest{OnSale: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, facets)
+		assert.Equal(t, criteria, repo.saleInput)
+	})
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		repo := &mockRepository{err: errors.New("db error")}
+		service := NewService(repo, &mockDiscountEngine{})
+
+		_, err := service.GetFacets(context.Background(), product.Filter{}, product.FacetRequest{Category: true})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_GetProductsByCodes(t *testing.T) {
+	t.Run("returns products in request order and reports missing codes", func(t *testing.T) {
+		repo := &mockRepository{products: []product.Product{
+			{Code: "PROD001", Price: decimal.NewFromInt(100)},
+			{Code: "PROD002", Price: decimal.NewFromInt(50), Variants: []product.Variant{
+				{SKU: "SKU002A", Price: decimal.NewFromInt(50)},
+			}},
+		}}
+		discountEngine := &mockDiscountEngine{
+			discountPercentage:        10,
+			discountedPrice:           decimal.NewFromInt(45),
+			variantDiscountPercentage: 10,
+		}
+		service := NewService(repo, discountEngine)
+
+		details, missing, err := service.GetProductsByCodes(context.Background(), []string{"PROD002", "NOPE", "PROD001", "PROD002"})
+
+		require.NoError(t, err)
+		require.Len(t, details, 2)
+		assert.Equal(t, "PROD002", details[0].Product.Code)
+		assert.Equal(t, "PROD001", details[1].Product.Code)
+		assert.Equal(t, 10, details[0].Percentage)
+		assert.Equal(t, 45.0, details[0].VariantDiscounts["SKU002A"].DiscountedPrice)
+		assert.Equal(t, []string{"NOPE"}, missing)
+	})
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		repo := &mockRepository{err: errors.New("db error")}
+		service := NewService(repo, &mockDiscountEngine{})
+
+		_, _, err := service.GetProductsByCodes(context.Background(), []string{"PROD001"})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_GetVariantBySKU(t *testing.T) {
+	t.Run("returns variant with its discount and parent product", func(t *testing.T) {
+		repo := &mockRepository{products: []product.Product{
+			{Code: "PROD009", Price: decimal.NewFromInt(100), Variants: []product.Variant{
+				{SKU: "000003", Name: "Standard", Price: decimal.NewFromInt(80)},
+				{SKU: "SKU009B", Name: "Premium", Price: decimal.NewFromInt(120)},
+			}},
+		}}
+		discountEngine := &mockDiscountEngine{
+			discountPercentage:        30,
+			discountedPrice:           decimal.NewFromInt(70),
+			variantDiscountPercentage: 15,
+		}
+		service := NewService(repo, discountEngine)
+
+		detail, err := service.GetVariantBySKU(context.Background(), "000003")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Standard", detail.Variant.Name)
+		assert.Equal(t, 15, detail.Percentage)
+		assert.Equal(t, 68.0, detail.DiscountedPrice)
+		assert.Equal(t, "PROD009", detail.Parent.Product.Code)
+		assert.Equal(t, 70.0, detail.Parent.DiscountedPrice)
+		assert.Equal(t, 30, detail.Parent.Percentage)
+	})
+
+	t.Run("returns error when variant does not exist", func(t *testing.T) {
+		service := NewService(&mockRepository{}, &mockDiscountEngine{})
+
+		_, err := service.GetVariantBySKU(context.Background(), "NOPE")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_ExportProducts(t *testing.T) {
+	t.Run("streams every product with its discounts in batches", func(t *testing.T) {
+		repo := &mockRepository{products: []product.Product{
+			{Code: "PROD001", Price: decimal.NewFromInt(100)},
+			{Code: "PROD002", Price: decimal.NewFromInt(50), Variants: []product.Variant{
+				{SKU: "SKU002A", Price: decimal.NewFromInt(50)},
+			}},
+		}}
+		discountEngine := &mockDiscountEngine{
+			discountPercentage:        10,
+			discountedPrice:           decimal.NewFromInt(45),
+			variantDiscountPercentage: 10,
+		}
+		service := NewService(repo, discountEngine)
+
+		var exported []ProductDetail
+		err := service.ExportProducts(context.Background(), product.Filter{}, func(d ProductDetail) error {
+			exported = append(exported, d)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, exportBatchSize, repo.batchSize)
+		require.Len(t, exported, 2)
+		assert.Equal(t, "PROD001", exported[0].Product.Code)
+		assert.Equal(t, 10, exported[0].Percentage)
+		assert.Equal(t, 45.0, exported[1].VariantDiscounts["SKU002A"].DiscountedPrice)
+	})
+
+	t.Run("stops at the first callback error", func(t *testing.T) {
+		repo := &mockRepository{products: []product.Product{{Code: "PROD001"}, {Code: "PROD002"}}}
+		service := NewService(repo, &mockDiscountEngine{})
+		errWrite := errors.New("write failed")
+
+		calls := 0
+		err := service.ExportProducts(context.Background(), product.Filter{}, func(ProductDetail) error {
+			calls++
+			return errWrite
+		})
+
+		assert.ErrorIs(t, err, errWrite)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		service := NewService(&mockRepository{err: errors.New("db error")}, &mockDiscountEngine{})
+
+		err := service.ExportProducts(context.Background(), product.Filter{}, func(ProductDetail) error { return nil })
 
 		assert.Error(t, err)
 	})
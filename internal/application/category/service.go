@@ -1,38 +1,74 @@
 package category
 
-import "github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
 
 // Repository defines ops for category persistence.
 type Repository interface {
-	GetAll() ([]product.Category, error)
-	Create(cat product.Category) (*product.Category, error)
+	GetAll(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error)
+	Create(ctx context.Context, cat product.Category) (*product.Category, error)
+}
+
+// DiscountEngine describes which products are on sale for a pricing context,
+// for the on-sale counts.
+type DiscountEngine interface {
+	SaleCriteria(pc discount.PricingContext) product.SaleCriteria
+}
+
+// Invalidator is notified after categories are written, so that derived
+// data such as search indexes can be rebuilt.
+type Invalidator interface {
+	Invalidate()
 }
 
 // Service defines ops for category business logic.
 type Service interface {
-	GetCategories() ([]product.Category, error)
-	CreateCategory(code, name string) (*product.Category, error)
+	GetCategories(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error)
+	CreateCategory(ctx context.Context, code, name string) (*product.Category, error)
 }
 
 type service struct {
-	repo Repository
+	repo         Repository
+	discounts    DiscountEngine
+	invalidators []Invalidator
 }
 
 // NewService creates a new category service.
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+// Invalidators are called after every successful write.
+func NewService(repo Repository, discounts DiscountEngine, invalidators ...Invalidator) Service {
+	return &service{repo: repo, discounts: discounts, invalidators: invalidators}
 }
 
-// GetCategories retrieves all categories.
-func (s *service) GetCategories() ([]product.Category, error) {
-	return s.repo.GetAll()
+// GetCategories retrieves a page of categories with their product counts.
+// On-sale counts follow the discount engine's current rules for the pricing
+// context carried by ctx.
+func (s *service) GetCategories(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error) {
+	query.Sale = s.discounts.SaleCriteria(discount.PricingContextFrom(ctx))
+	return s.repo.GetAll(ctx, query)
 }
 
 // CreateCategory creates a new category.
-func (s *service) CreateCategory(code, name string) (*product.Category, error) {
+func (s *service) CreateCategory(ctx context.Context, code, name string) (*product.Category, error) {
 	cat := product.Category{
 		Code: code,
 		Name: name,
 	}
-	return s.repo.Create(cat)
+
+	created, err := s.repo.Create(ctx, cat)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return created, nil
+}
+
+func (s *service) invalidate() {
+	for _, inv := range s.invalidators {
+		inv.Invalidate()
+	}
 }
@@ -0,0 +1,110 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	categories []product.Category
+	summaries  []product.CategorySummary
+	query      product.CategoryQuery
+	err        error
+}
+
+func (m *mockRepository) GetAll(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error) {
+	m.query = query
+	return m.summaries, int64(len(m.summaries)), m.err
+}
+
+func (m *mockRepository) Create(ctx context.Context, cat product.Category) (*product.Category, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	cat.ID = uint(len(m.categories) + 1)
+	m.categories = append(m.categories, cat)
+	return &cat, nil
+}
+
+type mockDiscountEngine struct {
+	saleCriteria product.SaleCriteria
+}
+
+func (m *mockDiscountEngine) SaleCriteria(pc discount.PricingContext) product.SaleCriteria {
+	return m.saleCriteria
+}
+
+type mockInvalidator struct {
+	calls int
+}
+
+func (m *mockInvalidator) Invalidate() {
+	m.calls++
+}
+
+func TestService_GetCategories(t *testing.T) {
+	t.Run("passes the query with the discount sale criteria", func(t *testing.T) {
+		repo := &mockRepository{
+			summaries: []product.CategorySummary{
+				{Category: product.Category{Code: "boots", Name: "Boots"}, ProductCount: 4, OnSaleCount: 4},
+			},
+		}
+		engine := &mockDiscountEngine{saleCriteria: product.SaleCriteria{CategoryCodes: []string{"boots"}}}
+		service := NewService(repo, engine)
+
+		summaries, total, err := service.GetCategories(context.Background(), product.CategoryQuery{
+			Offset: 10,
+			Limit:  5,
+			Sort:   product.CategorySortProducts,
+			Desc:   true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, repo.summaries, summaries)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, product.CategoryQuery{
+			Offset: 10,
+			Limit:  5,
+			Sort:   product.CategorySortProducts,
+			Desc:   true,
+			Sale:   engine.saleCriteria,
+		}, repo.query)
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		service := NewService(&mockRepository{err: errors.New("database error")}, &mockDiscountEngine{})
+
+		_, _, err := service.GetCategories(context.Background(), product.CategoryQuery{})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestService_CreateCategory(t *testing.T) {
+	t.Run("notifies invalidators after a successful write", func(t *testing.T) {
+		invalidator := &mockInvalidator{}
+		service := NewService(&mockRepository{}, &mockDiscountEngine{}, invalidator)
+
+		cat, err := service.CreateCategory(context.Background(), "boots", "Boots")
+
+		require.NoError(t, err)
+		assert.Equal(t, "boots", cat.Code)
+		assert.Equal(t, 1, invalidator.calls)
+	})
+
+	t.Run("does not notify invalidators when the write fails", func(t *testing.T) {
+		invalidator := &mockInvalidator{}
+		service := NewService(&mockRepository{err: errors.New("duplicate code")}, &mockDiscountEngine{}, invalidator)
+
+		_, err := service.CreateCategory(context.Background(), "boots", "Boots")
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, invalidator.calls)
+	})
+}
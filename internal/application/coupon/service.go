@@ -0,0 +1,62 @@
+// Package coupon checks and redeems coupon codes.
+package coupon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+)
+
+// Repository defines ops for coupon persistence. Both methods return
+// discount.ErrCouponNotFound for unknown codes. Redeem checks the coupon and
+// records the redemption atomically, so concurrent redemptions cannot exceed
+// its limits.
+type Repository interface {
+	Get(ctx context.Context, code, customerID string) (discount.Coupon, discount.Usage, error)
+	Redeem(ctx context.Context, code, customerID string, now time.Time) (discount.Redemption, error)
+}
+
+// Service defines ops for coupons.
+type Service interface {
+	Get(ctx context.Context, code string) (discount.Coupon, discount.Usage, error)
+	Check(ctx context.Context, code, customerID string) (discount.Coupon, error)
+	Redeem(ctx context.Context, code, customerID string) (discount.Redemption, error)
+}
+
+type service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a new coupon service.
+func NewService(repo Repository) Service {
+	return &service{repo: repo, now: time.Now}
+}
+
+// Get returns a coupon with its total usage.
+func (s *service) Get(ctx context.Context, code string) (discount.Coupon, discount.Usage, error) {
+	return s.repo.Get(ctx, discount.NormalizeCode(code), "")
+}
+
+// Check returns the coupon when it could be redeemed now by the customer. An
+// empty customerID only checks the total limit.
+func (s *service) Check(ctx context.Context, code, customerID string) (discount.Coupon, error) {
+	coupon, usage, err := s.repo.Get(ctx, discount.NormalizeCode(code), customerID)
+	if err != nil {
+		return discount.Coupon{}, err
+	}
+	if err := coupon.Check(s.now(), usage); err != nil {
+		return discount.Coupon{}, err
+	}
+	return coupon, nil
+}
+
+// Redeem records one use of the coupon by the customer.
+func (s *service) Redeem(ctx context.Context, code, customerID string) (discount.Redemption, error) {
+	if customerID == "" {
+		return discount.Redemption{}, fmt.Errorf("%w: customerId is required", discount.ErrInvalidRedemption)
+	}
+	return s.repo.Redeem(ctx, discount.NormalizeCode(code), customerID, s.now())
+}
@@ -0,0 +1,120 @@
+package coupon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+type mockRepository struct {
+	coupons     map[string]discount.Coupon
+	redemptions []discount.Redemption
+}
+
+func newMockRepository(coupons ...discount.Coupon) *mockRepository {
+	m := &mockRepository{coupons: make(map[string]discount.Coupon)}
+	for _, c := range coupons {
+		m.coupons[c.Code] = c
+	}
+	return m
+}
+
+func (m *mockRepository) Get(ctx context.Context, code, customerID string) (discount.Coupon, discount.Usage, error) {
+	c, ok := m.coupons[code]
+	if !ok {
+		return discount.Coupon{}, discount.Usage{}, fmt.Errorf("coupon %s: %w", code, discount.ErrCouponNotFound)
+	}
+	var usage discount.Usage
+	for _, r := range m.redemptions {
+		if r.Code == code {
+			usage.Total++
+			if customerID != "" && r.CustomerID == customerID {
+				usage.Customer++
+			}
+		}
+	}
+	return c, usage, nil
+}
+
+func (m *mockRepository) Redeem(ctx context.Context, code, customerID string, now time.Time) (discount.Redemption, error) {
+	c, usage, err := m.Get(ctx, code, customerID)
+	if err != nil {
+		return discount.Redemption{}, err
+	}
+	if err := c.Check(now, usage); err != nil {
+		return discount.Redemption{}, err
+	}
+	r := discount.Redemption{Code: code, CustomerID: customerID, RedeemedAt: now}
+	m.redemptions = append(m.redemptions, r)
+	return r, nil
+}
+
+func newTestService(repo Repository) *service {
+	return &service{repo: repo, now: func() time.Time { return now }}
+}
+
+func TestService_Check(t *testing.T) {
+	spring := discount.Coupon{Code: "SPRING", Rule: "spring", EndsAt: now.Add(time.Hour), MaxPerCustomer: 1}
+
+	t.Run("returns a redeemable coupon whatever the case of the code", func(t *testing.T) {
+		svc := newTestService(newMockRepository(spring))
+
+		coupon, err := svc.Check(context.Background(), " spring ", "c-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "spring", coupon.Rule)
+	})
+
+	t.Run("checks the per-customer limit of the customer", func(t *testing.T) {
+		repo := newMockRepository(spring)
+		repo.redemptions = []discount.Redemption{{Code: "SPRING", CustomerID: "c-1"}}
+		svc := newTestService(repo)
+
+		_, err := svc.Check(context.Background(), "SPRING", "c-1")
+		assert.ErrorIs(t, err, discount.ErrCouponExhausted)
+
+		_, err = svc.Check(context.Background(), "SPRING", "c-2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects expired and unknown coupons", func(t *testing.T) {
+		expired := discount.Coupon{Code: "WINTER", Rule: "winter", EndsAt: now}
+		svc := newTestService(newMockRepository(expired))
+
+		_, err := svc.Check(context.Background(), "WINTER", "")
+		assert.ErrorIs(t, err, discount.ErrCouponInactive)
+
+		_, err = svc.Check(context.Background(), "NOPE", "")
+		assert.ErrorIs(t, err, discount.ErrCouponNotFound)
+	})
+}
+
+func TestService_Redeem(t *testing.T) {
+	t.Run("records the redemption", func(t *testing.T) {
+		repo := newMockRepository(discount.Coupon{Code: "SPRING", Rule: "spring", MaxRedemptions: 1})
+		svc := newTestService(repo)
+
+		redemption, err := svc.Redeem(context.Background(), "spring", "c-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, discount.Redemption{Code: "SPRING", CustomerID: "c-1", RedeemedAt: now}, redemption)
+
+		_, err = svc.Redeem(context.Background(), "SPRING", "c-2")
+		assert.ErrorIs(t, err, discount.ErrCouponExhausted)
+	})
+
+	t.Run("requires a customer", func(t *testing.T) {
+		svc := newTestService(newMockRepository())
+
+		_, err := svc.Redeem(context.Background(), "SPRING", "")
+
+		assert.ErrorIs(t, err, discount.ErrInvalidRedemption)
+	})
+}
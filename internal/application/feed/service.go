@@ -0,0 +1,200 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+)
+
+// Limits of the Google Merchant attributes.
+const (
+	maxIDLength    = 50
+	maxTitleLength = 150
+)
+
+// Availability values of an item, derived from the stock of its variant.
+const (
+	AvailabilityInStock    = "in stock"
+	AvailabilityOutOfStock = "out of stock"
+)
+
+// ErrInvalidConfig is returned when the feed configuration misses required values.
+var ErrInvalidConfig = errors.New("invalid feed config")
+
+// Catalog streams products priced by the discount engine.
+type Catalog interface {
+	ExportProducts(ctx context.Context, filters product.Filter, fn func(catalog.ProductDetail) error) error
+}
+
+// Config describes the shop the feed advertises. ProductURL and ImageURL are
+// templates where {code} and {sku} are replaced with the product code and
+// variant SKU. Categories maps category codes to Google product categories.
+type Config struct {
+	Title       string
+	Link        string
+	Description string
+	Currency    string
+	ProductURL  string
+	ImageURL    string
+	Categories  map[string]string
+}
+
+// Validate checks that every value needed to render items is set.
+func (c Config) Validate() error {
+	required := []struct{ name, value string }{
+		{"title", c.Title}, {"link", c.Link}, {"productUrl", c.ProductURL}, {"imageUrl", c.ImageURL},
+	}
+	var missing []string
+	for _, field := range required {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalidConfig, strings.Join(missing, ", "))
+	}
+	if len(c.Currency) != 3 || strings.ToUpper(c.Currency) != c.Currency {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code such as EUR", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Item is one variant in the feed. SalePrice is nil when the variant is not discounted.
+type Item struct {
+	ID                    string
+	Title                 string
+	Description           string
+	Link                  string
+	ImageLink             string
+	Availability          string
+	Price                 decimal.Decimal
+	SalePrice             *decimal.Decimal
+	Currency              string
+	ItemGroupID           string
+	GoogleProductCategory string
+	ProductType           string
+}
+
+// SkippedItem is a variant left out of the feed, or a product when it has no variants.
+type SkippedItem struct {
+	ProductCode string
+	SKU         string
+	Reasons     []string
+}
+
+// Report tells how many items a feed has and which were skipped.
+type Report struct {
+	Items   int
+	Skipped []SkippedItem
+}
+
+// Service defines ops for product feed generation.
+type Service interface {
+	Generate(ctx context.Context, fn func(Item) error) (Report, error)
+}
+
+type service struct {
+	catalog Catalog
+	config  Config
+}
+
+// NewService creates a feed service for a validated config.
+func NewService(catalog Catalog, config Config) Service {
+	return &service{catalog: catalog, config: config}
+}
+
+// Generate calls fn for every valid variant of the catalog, in product order,
+// and reports the variants that miss required values.
+func (s *service) Generate(ctx context.Context, fn func(Item) error) (Report, error) {
+	report := Report{Skipped: []SkippedItem{}}
+	err := s.catalog.ExportProducts(ctx, product.Filter{}, func(detail catalog.ProductDetail) error {
+		p := detail.Product
+		if len(p.Variants) == 0 {
+			report.Skipped = append(report.Skipped, SkippedItem{ProductCode: p.Code, Reasons: []string{"product has no variants"}})
+			return nil
+		}
+
+		for _, v := range p.Variants {
+			item, reasons := s.item(p, v, detail.VariantDiscounts[v.SKU])
+			if len(reasons) > 0 {
+				report.Skipped = append(report.Skipped, SkippedItem{ProductCode: p.Code, SKU: v.SKU, Reasons: reasons})
+				continue
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+			report.Items++
+		}
+		return nil
+	})
+	return report, err
+}
+
+// item builds the feed item of a variant, or the reasons why it cannot be listed.
+func (s *service) item(p product.Product, v product.Variant, discount catalog.VariantDiscount) (Item, []string) {
+	var reasons []string
+
+	if len(v.SKU) > maxIDLength {
+		reasons = append(reasons, fmt.Sprintf("sku is longer than %d characters", maxIDLength))
+	}
+	if !v.Price.IsPositive() {
+		reasons = append(reasons, "price must be greater than 0")
+	}
+
+	var googleCategory, productType string
+	if p.Category == nil {
+		reasons = append(reasons, "product has no category")
+	} else {
+		productType = p.Category.Name
+		googleCategory = s.config.Categories[p.Category.Code]
+		if googleCategory == "" {
+			reasons = append(reasons, fmt.Sprintf("category %s has no Google product category", p.Category.Code))
+		}
+	}
+
+	if len(reasons) > 0 {
+		return Item{}, reasons
+	}
+
+	// The catalog has no product names, so titles are built from what identifies a variant.
+	title := strings.TrimSpace(strings.Join([]string{productType, p.Code, v.Name}, " "))
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = string(runes[:maxTitleLength])
+	}
+
+	availability := AvailabilityOutOfStock
+	if v.Available() {
+		availability = AvailabilityInStock
+	}
+
+	item := Item{
+		ID:                    v.SKU,
+		Title:                 title,
+		Description:           title,
+		Link:                  s.itemURL(s.config.ProductURL, p, v),
+		ImageLink:             s.itemURL(s.config.ImageURL, p, v),
+		Availability:          availability,
+		Price:                 v.Price,
+		Currency:              s.config.Currency,
+		ItemGroupID:           p.Code,
+		GoogleProductCategory: googleCategory,
+		ProductType:           productType,
+	}
+	if discount.Percentage > 0 {
+		sale := decimal.NewFromFloat(discount.DiscountedPrice).Round(2)
+		if sale.LessThan(v.Price) {
+			item.SalePrice = &sale
+		}
+	}
+	return item, nil
+}
+
+func (s *service) itemURL(template string, p product.Product, v product.Variant) string {
+	return strings.NewReplacer("{code}", url.PathEscape(p.Code), "{sku}", url.PathEscape(v.SKU)).Replace(template)
+}
@@ -0,0 +1,171 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCatalog struct {
+	details []catalog.ProductDetail
+	err     error
+}
+
+func (m *mockCatalog) ExportProducts(ctx context.Context, filters product.Filter, fn func(catalog.ProductDetail) error) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, d := range m.details {
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func testConfig() Config {
+	return Config{
+		Title:      "Shop",
+		Link:       "https://shop.example.com",
+		Currency:   "EUR",
+		ProductURL: "https://shop.example.com/p/{code}?variant={sku}",
+		ImageURL:   "https://img.example.com/{code}/{sku}.jpg",
+		Categories: map[string]string{"boots": "Apparel & Accessories > Shoes"},
+	}
+}
+
+var boots = &product.Category{Code: "boots", Name: "Boots"}
+
+func generate(t *testing.T, details ...catalog.ProductDetail) ([]Item, Report) {
+	t.Helper()
+	service := NewService(&mockCatalog{details: details}, testConfig())
+
+	var items []Item
+	report, err := service.Generate(context.Background(), func(item Item) error {
+		items = append(items, item)
+		return nil
+	})
+	require.NoError(t, err)
+	return items, report
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("accepts a complete config", func(t *testing.T) {
+		assert.NoError(t, testConfig().Validate())
+	})
+
+	t.Run("lists missing values", func(t *testing.T) {
+		config := testConfig()
+		config.Link = ""
+		config.ImageURL = ""
+
+		err := config.Validate()
+
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "missing link, imageUrl")
+	})
+
+	t.Run("rejects invalid currencies", func(t *testing.T) {
+		config := testConfig()
+		config.Currency = "euro"
+
+		assert.ErrorIs(t, config.Validate(), ErrInvalidConfig)
+	})
+}
+
+func TestService_Generate(t *testing.T) {
+	t.Run("lists one item per variant grouped by product", func(t *testing.T) {
+		items, report := generate(t, catalog.ProductDetail{
+			Product: product.Product{Code: "PROD009", Price: decimal.NewFromInt(100), Category: boots, Variants: []product.Variant{
+				{SKU: "000003", Name: "Size 40", Price: decimal.NewFromInt(100), Quantity: 2},
+				{SKU: "000004", Name: "Size 41", Price: decimal.NewFromInt(120)},
+			}},
+			VariantDiscounts: map[string]catalog.VariantDiscount{
+				"000003": {DiscountedPrice: 59.5, Percentage: 40},
+				"000004": {DiscountedPrice: 120},
+			},
+		})
+
+		require.Len(t, items, 2)
+		assert.Equal(t, 2, report.Items)
+		assert.Empty(t, report.Skipped)
+		assert.Equal(t, Item{
+			ID:                    "000003",
+			Title:                 "Boots PROD009 Size 40",
+			Description:           "Boots PROD009 Size 40",
+			Link:                  "https://shop.example.com/p/PROD009?variant=000003",
+			ImageLink:             "https://img.example.com/PROD009/000003.jpg",
+			Availability:          AvailabilityInStock,
+			Price:                 decimal.NewFromInt(100),
+			SalePrice:             items[0].SalePrice,
+			Currency:              "EUR",
+			ItemGroupID:           "PROD009",
+			GoogleProductCategory: "Apparel & Accessories > Shoes",
+			ProductType:           "Boots",
+		}, items[0])
+		require.NotNil(t, items[0].SalePrice)
+		assert.Equal(t, "59.5", items[0].SalePrice.String())
+		assert.Nil(t, items[1].SalePrice)
+		assert.Equal(t, AvailabilityOutOfStock, items[1].Availability)
+	})
+
+	t.Run("skips and reports items missing required values", func(t *testing.T) {
+		items, report := generate(t,
+			catalog.ProductDetail{Product: product.Product{Code: "PROD001", Category: boots}},
+			catalog.ProductDetail{Product: product.Product{Code: "PROD002", Variants: []product.Variant{
+				{SKU: "SKU002A", Price: decimal.Zero},
+			}}},
+			catalog.ProductDetail{Product: product.Product{Code: "PROD003", Category: &product.Category{Code: "hats"}, Variants: []product.Variant{
+				{SKU: strings.Repeat("X", 51), Price: decimal.NewFromInt(10)},
+			}}},
+			catalog.ProductDetail{Product: product.Product{Code: "PROD004", Category: boots, Variants: []product.Variant{
+				{SKU: "SKU004A", Price: decimal.NewFromInt(10)},
+			}}},
+		)
+
+		require.Len(t, items, 1)
+		assert.Equal(t, "SKU004A", items[0].ID)
+		assert.Equal(t, "Boots PROD004", items[0].Title)
+		assert.Equal(t, []SkippedItem{
+			{ProductCode: "PROD001", Reasons: []string{"product has no variants"}},
+			{ProductCode: "PROD002", SKU: "SKU002A", Reasons: []string{"price must be greater than 0", "product has no category"}},
+			{ProductCode: "PROD003", SKU: strings.Repeat("X", 51), Reasons: []string{
+				"sku is longer than 50 characters", "category hats has no Google product category",
+			}},
+		}, report.Skipped)
+	})
+
+	t.Run("returns the catalog error", func(t *testing.T) {
+		service := NewService(&mockCatalog{err: errors.New("db error")}, testConfig())
+
+		_, err := service.Generate(context.Background(), func(Item) error { return nil })
+
+		assert.Error(t, err)
+	})
+
+	t.Run("stops at the first callback error", func(t *testing.T) {
+		errWrite := errors.New("write failed")
+		service := NewService(&mockCatalog{details: []catalog.ProductDetail{{
+			Product: product.Product{Code: "PROD004", Category: boots, Variants: []product.Variant{
+				{SKU: "SKU004A", Price: decimal.NewFromInt(10)},
+				{SKU: "SKU004B", Price: decimal.NewFromInt(10)},
+			}},
+		}}}, testConfig())
+
+		calls := 0
+		_, err := service.Generate(context.Background(), func(Item) error {
+			calls++
+			return errWrite
+		})
+
+		assert.ErrorIs(t, err, errWrite)
+		assert.Equal(t, 1, calls)
+	})
+}
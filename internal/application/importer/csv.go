@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSV columns, matched by header name in any order.
+const (
+	ColumnProductCode  = "product_code"
+	ColumnPrice        = "price"
+	ColumnCategoryCode = "category_code"
+	ColumnVariantSKU   = "variant_sku"
+	ColumnVariantName  = "variant_name"
+	ColumnVariantPrice = "variant_price"
+)
+
+var columns = []string{ColumnProductCode, ColumnPrice, ColumnCategoryCode, ColumnVariantSKU, ColumnVariantName, ColumnVariantPrice}
+
+// ErrInvalidCSV is returned when the CSV cannot be read as rows.
+var ErrInvalidCSV = errors.New("invalid CSV")
+
+// Row is one CSV record, not validated yet. Line is its line in the file.
+type Row struct {
+	Line         int
+	ProductCode  string
+	Price        string
+	CategoryCode string
+	VariantSKU   string
+	VariantName  string
+	VariantPrice string
+}
+
+// ParseCSV reads rows from a CSV with a header line naming every column.
+func ParseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidCSV)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, column := range columns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("%w: missing column %s", ErrInvalidCSV, column)
+		}
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(column string) string {
+			return strings.TrimSpace(record[index[column]])
+		}
+		rows = append(rows, Row{
+			Line:         line,
+			ProductCode:  field(ColumnProductCode),
+			Price:        field(ColumnPrice),
+			CategoryCode: field(ColumnCategoryCode),
+			VariantSKU:   field(ColumnVariantSKU),
+			VariantName:  field(ColumnVariantName),
+			VariantPrice: field(ColumnVariantPrice),
+		})
+	}
+}
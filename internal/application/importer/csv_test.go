@@ -0,0 +1,49 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	t.Run("maps columns by header name", func(t *testing.T) {
+		input := "variant_sku,variant_name,variant_price,product_code,price,category_code\n" +
+			"SKU001A, Variant A,11.99,PROD001,10.99,clothing\n" +
+			",,,PROD006,5.50,\n"
+
+		rows, err := ParseCSV(strings.NewReader(input))
+
+		require.NoError(t, err)
+		assert.Equal(t, []Row{
+			{Line: 2, ProductCode: "PROD001", Price: "10.99", CategoryCode: "clothing", VariantSKU: "SKU001A", VariantName: "Variant A", VariantPrice: "11.99"},
+			{Line: 3, ProductCode: "PROD006", Price: "5.50"},
+		}, rows)
+	})
+
+	t.Run("reports the line of quoted multi-line records", func(t *testing.T) {
+		input := "product_code,price,category_code,variant_sku,variant_name,variant_price\n" +
+			"PROD001,10.99,,SKU001A,\"Variant\nA\",\n" +
+			"PROD002,12.49,,,,\n"
+
+		rows, err := ParseCSV(strings.NewReader(input))
+
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, 4, rows[1].Line)
+	})
+
+	t.Run("rejects invalid files", func(t *testing.T) {
+		tests := map[string]string{
+			"empty":          "",
+			"missing column": "product_code,price,category_code,variant_sku,variant_name\n",
+			"ragged record":  "product_code,price,category_code,variant_sku,variant_name,variant_price\nPROD001,10.99\n",
+		}
+		for name, input := range tests {
+			_, err := ParseCSV(strings.NewReader(input))
+			assert.ErrorIs(t, err, ErrInvalidCSV, name)
+		}
+	})
+}
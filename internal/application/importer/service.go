@@ -0,0 +1,286 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+)
+
+// Column limits of the catalog tables.
+const (
+	maxCodeLength = 32
+	maxNameLength = 256
+)
+
+// maxPrice is the first price that does not fit in DECIMAL(10, 2).
+var maxPrice = decimal.New(1, 8)
+
+// errRollback discards the transaction of dry runs and failed imports.
+var errRollback = errors.New("rollback")
+
+// ProductRepository upserts products by code and variants by SKU.
+type ProductRepository interface {
+	Upsert(ctx context.Context, p product.Product) (product.Change, error)
+	UpsertVariant(ctx context.Context, productCode string, v product.Variant) (product.Change, error)
+}
+
+// Transactor runs fn in a transaction shared by the repositories called with its context.
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Invalidator is notified after an import is committed, so that caches and
+// indexes derived from the catalog are rebuilt.
+type Invalidator interface {
+	Invalidate()
+}
+
+// RowResult is the outcome of one CSV row. Product and Variant tell what the
+// import did to the row's product and variant; Variant is only meaningful when
+// the row has a SKU.
+type RowResult struct {
+	Line        int
+	ProductCode string
+	VariantSKU  string
+	Product     product.Change
+	Variant     product.Change
+	Errors      []string
+}
+
+// Counts tells how many records an import created, updated or left as they were.
+type Counts struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+func (c *Counts) add(change product.Change) {
+	switch change {
+	case product.Created:
+		c.Created++
+	case product.Updated:
+		c.Updated++
+	default:
+		c.Unchanged++
+	}
+}
+
+// Report describes an import. Nothing is written unless Committed is set,
+// which requires every row to succeed and the import not to be a dry run.
+type Report struct {
+	DryRun    bool
+	Committed bool
+	Failed    int
+	Products  Counts
+	Variants  Counts
+	Rows      []RowResult
+}
+
+// Service imports products and variants in bulk.
+type Service interface {
+	Import(ctx context.Context, rows []Row, dryRun bool) (Report, error)
+}
+
+type service struct {
+	transactor   Transactor
+	products     ProductRepository
+	invalidators []Invalidator
+}
+
+// NewService creates a new import service.
+// Invalidators are called after every committed import.
+func NewService(transactor Transactor, products ProductRepository, invalidators ...Invalidator) Service {
+	return &service{transactor: transactor, products: products, invalidators: invalidators}
+}
+
+// Import validates every row, then upserts them in one transaction. Rows of the
+// same product must agree on its price and category. The transaction is rolled
+// back on dry runs and when any row fails, and the report then tells what the
+// import would have done. Errors are only returned when the import could not run.
+func (s *service) Import(ctx context.Context, rows []Row, dryRun bool) (Report, error) {
+	report := Report{DryRun: dryRun, Rows: make([]RowResult, len(rows))}
+	products := validate(rows, report.Rows)
+	if report.countFailures() > 0 {
+		return report, nil
+	}
+
+	err := s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		changes := make(map[string]product.Change)
+		for i, row := range rows {
+			result := &report.Rows[i]
+			p := products[row.ProductCode]
+
+			change, seen := changes[p.Code]
+			if !seen {
+				var err error
+				change, err = s.products.Upsert(ctx, product.Product{Code: p.Code, Price: p.Price, Category: p.Category})
+				if errors.Is(err, product.ErrNotFound) {
+					result.Errors = append(result.Errors, err.Error())
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("line %d: %w", row.Line, err)
+				}
+				changes[p.Code] = change
+				report.Products.add(change)
+			}
+			result.Product = change
+
+			if row.VariantSKU == "" {
+				continue
+			}
+			variant := variantOf(row)
+			change, err := s.products.UpsertVariant(ctx, p.Code, variant)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", row.Line, err)
+			}
+			result.Variant = change
+			report.Variants.add(change)
+		}
+
+		if dryRun || report.countFailures() > 0 {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return Report{}, err
+	}
+
+	report.Committed = err == nil
+	if report.Committed {
+		for _, invalidator := range s.invalidators {
+			invalidator.Invalidate()
+		}
+	}
+	return report, nil
+}
+
+// countFailures updates Failed with the number of rows with errors and returns it.
+func (r *Report) countFailures() int {
+	r.Failed = 0
+	for _, row := range r.Rows {
+		if len(row.Errors) > 0 {
+			r.Failed++
+		}
+	}
+	return r.Failed
+}
+
+// validate checks every row, recording errors in results, and returns the
+// products described by the rows, keyed by code.
+func validate(rows []Row, results []RowResult) map[string]product.Product {
+	products := make(map[string]product.Product)
+	productLines := make(map[string]int)
+	skuLines := make(map[string]int)
+
+	for i, row := range rows {
+		result := &results[i]
+		result.Line = row.Line
+		result.ProductCode = row.ProductCode
+		result.VariantSKU = row.VariantSKU
+		fail := func(format string, args ...any) {
+			result.Errors = append(result.Errors, fmt.Sprintf(format, args...))
+		}
+
+		if row.ProductCode == "" {
+			fail("%s is required", ColumnProductCode)
+		} else if len(row.ProductCode) > maxCodeLength {
+			fail("%s is longer than %d characters", ColumnProductCode, maxCodeLength)
+		}
+		price, err := parsePrice(row.Price)
+		if err != nil {
+			fail("%s %s", ColumnPrice, err)
+		}
+		if len(row.CategoryCode) > maxCodeLength {
+			fail("%s is longer than %d characters", ColumnCategoryCode, maxCodeLength)
+		}
+
+		if row.VariantSKU == "" {
+			if row.VariantName != "" || row.VariantPrice != "" {
+				fail("%s is required when %s or %s is set", ColumnVariantSKU, ColumnVariantName, ColumnVariantPrice)
+			}
+		} else {
+			if len(row.VariantSKU) > maxCodeLength {
+				fail("%s is longer than %d characters", ColumnVariantSKU, maxCodeLength)
+			}
+			if row.VariantName == "" {
+				fail("%s is required when %s is set", ColumnVariantName, ColumnVariantSKU)
+			} else if len(row.VariantName) > maxNameLength {
+				fail("%s is longer than %d characters", ColumnVariantName, maxNameLength)
+			}
+			if row.VariantPrice != "" {
+				if _, err := parsePrice(row.VariantPrice); err != nil {
+					fail("%s %s", ColumnVariantPrice, err)
+				}
+			}
+			if line, ok := skuLines[row.VariantSKU]; ok {
+				fail("%s %s is already imported on line %d", ColumnVariantSKU, row.VariantSKU, line)
+			} else {
+				skuLines[row.VariantSKU] = row.Line
+			}
+		}
+
+		if len(result.Errors) > 0 {
+			continue
+		}
+
+		p := product.Product{Code: row.ProductCode, Price: price}
+		if row.CategoryCode != "" {
+			p.Category = &product.Category{Code: row.CategoryCode}
+		}
+		existing, ok := products[p.Code]
+		if !ok {
+			products[p.Code] = p
+			productLines[p.Code] = row.Line
+			continue
+		}
+		if !existing.Price.Equal(p.Price) {
+			fail("%s %s conflicts with %s on line %d", ColumnPrice, row.Price, existing.Price.StringFixed(2), productLines[p.Code])
+		}
+		if categoryCode(existing) != row.CategoryCode {
+			fail("%s %q conflicts with %q on line %d", ColumnCategoryCode, row.CategoryCode, categoryCode(existing), productLines[p.Code])
+		}
+	}
+
+	return products
+}
+
+// parsePrice parses a positive price with at most two decimals.
+func parsePrice(value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, errors.New("is required")
+	}
+	price, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%q is not a number", value)
+	}
+	if !price.IsPositive() {
+		return decimal.Zero, errors.New("must be positive")
+	}
+	if !price.Equal(price.Truncate(2)) {
+		return decimal.Zero, errors.New("has more than two decimals")
+	}
+	if !price.LessThan(maxPrice) {
+		return decimal.Zero, fmt.Errorf("must be less than %s", maxPrice)
+	}
+	return price, nil
+}
+
+func variantOf(row Row) product.Variant {
+	v := product.Variant{SKU: row.VariantSKU, Name: row.VariantName}
+	if row.VariantPrice != "" {
+		v.Price, _ = decimal.NewFromString(row.VariantPrice)
+	}
+	return v
+}
+
+func categoryCode(p product.Product) string {
+	if p.Category == nil {
+		return ""
+	}
+	return p.Category.Code
+}
@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository keeps products and variants in maps. Writes made through a
+// transaction are staged and only applied when it commits.
+type mockRepository struct {
+	categories map[string]bool
+	products   map[string]string
+	variants   map[string]string
+	staged     []func()
+	err        error
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{
+		categories: map[string]bool{"clothing": true, "shoes": true},
+		products:   map[string]string{"PROD001": "10.99"},
+		variants:   map[string]string{"SKU001A": "Variant A"},
+	}
+}
+
+func (m *mockRepository) Upsert(ctx context.Context, p product.Product) (product.Change, error) {
+	if m.err != nil {
+		return product.Unchanged, m.err
+	}
+	if p.Category != nil && !m.categories[p.Category.Code] {
+		return product.Unchanged, fmt.Errorf("category %s: %w", p.Category.Code, product.ErrNotFound)
+	}
+
+	price, ok := m.products[p.Code]
+	m.staged = append(m.staged, func() { m.products[p.Code] = p.Price.StringFixed(2) })
+	switch {
+	case !ok:
+		return product.Created, nil
+	case price != p.Price.StringFixed(2):
+		return product.Updated, nil
+	default:
+		return product.Unchanged, nil
+	}
+}
+
+func (m *mockRepository) UpsertVariant(ctx context.Context, productCode string, v product.Variant) (product.Change, error) {
+	name, ok := m.variants[v.SKU]
+	m.staged = append(m.staged, func() { m.variants[v.SKU] = v.Name })
+	switch {
+	case !ok:
+		return product.Created, nil
+	case name != v.Name:
+		return product.Updated, nil
+	default:
+		return product.Unchanged, nil
+	}
+}
+
+func (m *mockRepository) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.staged = nil
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	for _, write := range m.staged {
+		write()
+	}
+	return nil
+}
+
+type mockInvalidator struct {
+	calls int
+}
+
+func (m *mockInvalidator) Invalidate() {
+	m.calls++
+}
+
+func validRows() []Row {
+	return []Row{
+		{Line: 2, ProductCode: "PROD001", Price: "10.99", CategoryCode: "clothing", VariantSKU: "SKU001A", VariantName: "Variant A"},
+		{Line: 3, ProductCode: "PROD001", Price: "10.99", CategoryCode: "clothing", VariantSKU: "SKU001B", VariantName: "Variant B", VariantPrice: "11.50"},
+		{Line: 4, ProductCode: "PROD010", Price: "25.00", CategoryCode: "shoes"},
+	}
+}
+
+func TestService_Import(t *testing.T) {
+	t.Run("commits every row and reports the changes", func(t *testing.T) {
+		repo := newMockRepository()
+		invalidator := &mockInvalidator{}
+		service := NewService(repo, repo, invalidator)
+
+		report, err := service.Import(context.Background(), validRows(), false)
+
+		require.NoError(t, err)
+		assert.True(t, report.Committed)
+		assert.Zero(t, report.Failed)
+		assert.Equal(t, Counts{Created: 1, Unchanged: 1}, report.Products)
+		assert.Equal(t, Counts{Created: 1, Unchanged: 1}, report.Variants)
+		require.Len(t, report.Rows, 3)
+		assert.Equal(t, RowResult{Line: 2, ProductCode: "PROD001", VariantSKU: "SKU001A", Product: product.Unchanged, Variant: product.Unchanged}, report.Rows[0])
+		assert.Equal(t, product.Created, report.Rows[1].Variant)
+		assert.Equal(t, product.Created, report.Rows[2].Product)
+		assert.Equal(t, "25.00", repo.products["PROD010"])
+		assert.Equal(t, 1, invalidator.calls)
+	})
+
+	t.Run("rolls back dry runs", func(t *testing.T) {
+		repo := newMockRepository()
+		invalidator := &mockInvalidator{}
+		service := NewService(repo, repo, invalidator)
+
+		report, err := service.Import(context.Background(), validRows(), true)
+
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.False(t, report.Committed)
+		assert.Equal(t, Counts{Created: 1, Unchanged: 1}, report.Products)
+		assert.NotContains(t, repo.products, "PROD010")
+		assert.Zero(t, invalidator.calls)
+	})
+
+	t.Run("reports invalid rows without writing", func(t *testing.T) {
+		repo := newMockRepository()
+		service := NewService(repo, repo)
+		rows := append(validRows(),
+			Row{Line: 5, Price: "abc"},
+			Row{Line: 6, ProductCode: "PROD011", Price: "1.999", VariantName: "Orphan"},
+			Row{Line: 7, ProductCode: "PROD010", Price: "26.00", CategoryCode: "shoes"},
+			Row{Line: 8, ProductCode: "PROD012", Price: "5", VariantSKU: "SKU001B", VariantName: "Copy", VariantPrice: "-1"},
+		)
+
+		report, err := service.Import(context.Background(), rows, false)
+
+		require.NoError(t, err)
+		assert.False(t, report.Committed)
+		assert.Equal(t, 4, report.Failed)
+		assert.Empty(t, report.Rows[0].Errors)
+		assert.Equal(t, []string{"product_code is required", `price "abc" is not a number`}, report.Rows[3].Errors)
+		assert.Equal(t, []string{"price has more than two decimals", "variant_sku is required when variant_name or variant_price is set"}, report.Rows[4].Errors)
+		assert.Equal(t, []string{"price 26.00 conflicts with 25.00 on line 4"}, report.Rows[5].Errors)
+		assert.Equal(t, []string{"variant_price must be positive", "variant_sku SKU001B is already imported on line 3"}, report.Rows[6].Errors)
+		assert.NotContains(t, repo.products, "PROD010")
+	})
+
+	t.Run("rolls back when a category does not exist", func(t *testing.T) {
+		repo := newMockRepository()
+		service := NewService(repo, repo)
+		rows := append(validRows(), Row{Line: 5, ProductCode: "PROD011", Price: "5.00", CategoryCode: "hats"})
+
+		report, err := service.Import(context.Background(), rows, false)
+
+		require.NoError(t, err)
+		assert.False(t, report.Committed)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, []string{"category hats: not found"}, report.Rows[3].Errors)
+		assert.NotContains(t, repo.products, "PROD010")
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		repo := newMockRepository()
+		repo.err = errors.New("connection refused")
+		service := NewService(repo, repo)
+
+		_, err := service.Import(context.Background(), validRows(), false)
+
+		assert.ErrorContains(t, err, "line 2: connection refused")
+	})
+}
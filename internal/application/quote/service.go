@@ -0,0 +1,342 @@
+// Package quote prices carts with the catalog discount rules, basket
+// promotions and coupons.
+package quote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidQuote is returned when the requested items cannot be priced as given.
+var ErrInvalidQuote = errors.New("invalid quote")
+
+// UnknownSKUsError lists the requested SKUs that match no variant.
+// It matches product.ErrNotFound.
+type UnknownSKUsError struct {
+	SKUs []string
+}
+
+func (e *UnknownSKUsError) Error() string {
+	return fmt.Sprintf("unknown skus: %s", strings.Join(e.SKUs, ", "))
+}
+
+func (e *UnknownSKUsError) Is(target error) bool {
+	return target == product.ErrNotFound
+}
+
+// ProductRepository resolves the products owning a set of variants, with
+// their category and variants.
+type ProductRepository interface {
+	GetByVariantSKUs(ctx context.Context, skus []string) ([]product.Product, error)
+}
+
+// DiscountEngine defines the discount calculation used for quote lines,
+// bounded by the guardrails of the engine. Floor is the lowest unit price
+// basket promotions may take a product to as well.
+type DiscountEngine interface {
+	PriceVariant(pc discount.PricingContext, v product.Variant, p product.Product) discount.Price
+	Floor(p product.Product) (decimal.Decimal, discount.Limit)
+}
+
+// Promotions evaluates basket-level promotions over the lines of a quote,
+// after their item discounts. Coupon-only rules apply when named in unlocked.
+type Promotions interface {
+	Apply(lines []discount.BasketLine, unlocked []string) []discount.AppliedPromotion
+}
+
+// Coupons checks that a coupon could be redeemed by a customer, returning
+// discount.ErrCouponNotFound, ErrCouponInactive or ErrCouponExhausted when not.
+type Coupons interface {
+	Check(ctx context.Context, code, customerID string) (discount.Coupon, error)
+}
+
+// Item is a quantity of one variant to price.
+type Item struct {
+	SKU      string
+	Quantity int
+}
+
+// Cart is what to price: items, the coupon codes supplied with them and,
+// optionally, the customer the per-customer coupon limits are checked for.
+type Cart struct {
+	Items      []Item
+	Coupons    []string
+	CustomerID string
+}
+
+// Line is the price of one item. Amounts are in the catalog currency and
+// rounded to cents. Discount is the item discount, PromotionDiscount the share
+// of basket promotions taken off the line, and Total what is left of Subtotal.
+// Limit explains how a guardrail changed the item discount or cut the basket
+// promotions of the line, if one did; for promotions its Requested is the
+// percentage of Subtotal the item discount and promotions asked for.
+type Line struct {
+	SKU               string
+	ProductCode       string
+	Name              string
+	Quantity          int
+	UnitPrice         decimal.Decimal
+	Percentage        int
+	Subtotal          decimal.Decimal
+	Discount          decimal.Decimal
+	PromotionDiscount decimal.Decimal
+	Total             decimal.Decimal
+	Limit             *discount.Limit
+}
+
+// Promotion is a basket promotion applied to a quote. Coupon is the code
+// that unlocked it, if any.
+type Promotion struct {
+	Name     string
+	Kind     discount.BasketRuleKind
+	Coupon   string
+	Discount decimal.Decimal
+}
+
+// Quote is the price of a cart. Its amounts are the sums of its lines.
+type Quote struct {
+	Lines             []Line
+	Promotions        []Promotion
+	Subtotal          decimal.Decimal
+	Discount          decimal.Decimal
+	PromotionDiscount decimal.Decimal
+	Total             decimal.Decimal
+}
+
+// Service defines ops for pricing carts.
+type Service interface {
+	Quote(ctx context.Context, cart Cart) (Quote, error)
+}
+
+type service struct {
+	repo           ProductRepository
+	discountEngine DiscountEngine
+	promotions     Promotions
+	coupons        Coupons
+}
+
+// NewService creates a new quote service.
+func NewService(repo ProductRepository, discountEngine DiscountEngine, promotions Promotions, coupons Coupons) Service {
+	return &service{repo: repo, discountEngine: discountEngine, promotions: promotions, coupons: coupons}
+}
+
+// Quote prices every item with the discount of its variant for the pricing
+// context carried by ctx, in request order, then applies the basket
+// promotions, including those unlocked by the cart coupons, over the
+// discounted lines. Promotions never take a unit below the guardrail floor of
+// its product. Quoting does not redeem coupons.
+// All SKUs that match no variant are reported together in an UnknownSKUsError.
+func (s *service) Quote(ctx context.Context, cart Cart) (Quote, error) {
+	if err := validate(cart); err != nil {
+		return Quote{}, err
+	}
+
+	unlocked := make(map[string]string, len(cart.Coupons))
+	for _, code := range cart.Coupons {
+		coupon, err := s.coupons.Check(ctx, code, cart.CustomerID)
+		if err != nil {
+			return Quote{}, err
+		}
+		unlocked[coupon.Rule] = coupon.Code
+	}
+
+	items := cart.Items
+	skus := make([]string, len(items))
+	for i, item := range items {
+		skus[i] = item.SKU
+	}
+	products, err := s.repo.GetByVariantSKUs(ctx, skus)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	variants := make(map[string]owned)
+	for _, p := range products {
+		for _, v := range p.Variants {
+			variants[v.SKU] = owned{variant: v, product: p}
+		}
+	}
+
+	pc := discount.PricingContextFrom(ctx)
+	quote := Quote{Lines: make([]Line, 0, len(items))}
+	floors := make([]floor, 0, len(items))
+	var unknown []string
+	for _, item := range items {
+		o, ok := variants[item.SKU]
+		if !ok {
+			unknown = append(unknown, item.SKU)
+			continue
+		}
+		quote.Lines = append(quote.Lines, s.line(pc, item, o.variant, o.product))
+		floors = append(floors, s.floor(item, o.variant, o.product))
+	}
+	if len(unknown) > 0 {
+		return Quote{}, &UnknownSKUsError{SKUs: unknown}
+	}
+
+	s.applyPromotions(&quote, categories(quote.Lines, variants), floors, unlocked)
+	quote.total()
+	return quote, nil
+}
+
+// line prices an item at the variant price less its variant discount.
+func (s *service) line(pc discount.PricingContext, item Item, v product.Variant, p product.Product) Line {
+	quantity := decimal.NewFromInt(int64(item.Quantity))
+	price := s.discountEngine.PriceVariant(pc, v, p)
+	if price.Limit != nil {
+		log.Printf("quote: discount of %s limited by %s", v.SKU, price.Limit)
+	}
+	subtotal := v.Price.Mul(quantity)
+	itemDiscount := price.Original.Sub(price.Final).Mul(quantity).Round(2)
+
+	return Line{
+		SKU:               v.SKU,
+		ProductCode:       p.Code,
+		Name:              v.Name,
+		Quantity:          item.Quantity,
+		UnitPrice:         v.Price,
+		Percentage:        price.Percentage,
+		Subtotal:          subtotal,
+		Discount:          itemDiscount,
+		PromotionDiscount: decimal.Zero,
+		Total:             subtotal.Sub(itemDiscount),
+		Limit:             price.Limit,
+	}
+}
+
+// floor is the lowest total basket promotions may take a line to, with the
+// guardrail setting it.
+type floor struct {
+	total decimal.Decimal
+	limit discount.Limit
+}
+
+// floor returns the guardrail floor of the units of an item. A floor above
+// the variant price leaves the line at its price.
+func (s *service) floor(item Item, v product.Variant, p product.Product) floor {
+	unit, limit := s.discountEngine.Floor(p)
+	return floor{total: decimal.Min(unit, v.Price).Mul(decimal.NewFromInt(int64(item.Quantity))), limit: limit}
+}
+
+// applyPromotions takes the basket promotions off the lines, down to their
+// floors. unlocked maps the rules unlocked by coupons to their codes.
+func (s *service) applyPromotions(q *Quote, categories []string, floors []floor, unlocked map[string]string) {
+	lines := make([]discount.BasketLine, len(q.Lines))
+	for i, l := range q.Lines {
+		lines[i] = discount.BasketLine{
+			SKU:            l.SKU,
+			Category:       categories[i],
+			Quantity:       l.Quantity,
+			Total:          l.Total,
+			ItemDiscounted: l.Discount.IsPositive(),
+			Floor:          floors[i].total,
+		}
+	}
+
+	rules := make([]string, 0, len(unlocked))
+	for rule := range unlocked {
+		rules = append(rules, rule)
+	}
+
+	requested := make([]decimal.Decimal, len(q.Lines))
+	limited := make([]bool, len(q.Lines))
+	for i, l := range q.Lines {
+		requested[i] = l.Discount
+	}
+	for _, applied := range s.promotions.Apply(lines, rules) {
+		for i, amount := range applied.Amounts {
+			q.Lines[i].PromotionDiscount = q.Lines[i].PromotionDiscount.Add(amount)
+			q.Lines[i].Total = q.Lines[i].Total.Sub(amount)
+			requested[i] = requested[i].Add(applied.Requested[i])
+			if applied.Requested[i].GreaterThan(amount) && floors[i].total.IsPositive() {
+				limited[i] = true
+			}
+		}
+		q.Promotions = append(q.Promotions, Promotion{
+			Name:     applied.Name,
+			Kind:     applied.Kind,
+			Coupon:   unlocked[applied.Name],
+			Discount: applied.Amount(),
+		})
+	}
+
+	for i := range q.Lines {
+		line := &q.Lines[i]
+		if !limited[i] || !line.Subtotal.IsPositive() {
+			continue
+		}
+		limit := floors[i].limit
+		limit.Requested = int(requested[i].Mul(decimal.NewFromInt(100)).Div(line.Subtotal).IntPart())
+		line.Limit = &limit
+		log.Printf("quote: promotions on %s limited by %s", line.SKU, limit)
+	}
+}
+
+// owned is a variant together with the product it belongs to.
+type owned struct {
+	variant product.Variant
+	product product.Product
+}
+
+// categories returns the category code of the product of each line.
+func categories(lines []Line, variants map[string]owned) []string {
+	codes := make([]string, len(lines))
+	for i, l := range lines {
+		if category := variants[l.SKU].product.Category; category != nil {
+			codes[i] = category.Code
+		}
+	}
+	return codes
+}
+
+// total sums the amounts of the lines.
+func (q *Quote) total() {
+	q.Subtotal, q.Discount, q.PromotionDiscount, q.Total = decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero
+	for _, l := range q.Lines {
+		q.Subtotal = q.Subtotal.Add(l.Subtotal)
+		q.Discount = q.Discount.Add(l.Discount)
+		q.PromotionDiscount = q.PromotionDiscount.Add(l.PromotionDiscount)
+		q.Total = q.Total.Add(l.Total)
+	}
+}
+
+func validate(cart Cart) error {
+	items := cart.Items
+	if len(items) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidQuote)
+	}
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.SKU == "" {
+			return fmt.Errorf("%w: sku is required", ErrInvalidQuote)
+		}
+		if seen[item.SKU] {
+			return fmt.Errorf("%w: sku %s is listed twice", ErrInvalidQuote, item.SKU)
+		}
+		seen[item.SKU] = true
+		if item.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity of %s must be greater than 0", ErrInvalidQuote, item.SKU)
+		}
+	}
+
+	codes := make(map[string]bool, len(cart.Coupons))
+	for _, code := range cart.Coupons {
+		code = discount.NormalizeCode(code)
+		if code == "" {
+			return fmt.Errorf("%w: coupon code is empty", ErrInvalidQuote)
+		}
+		if codes[code] {
+			return fmt.Errorf("%w: coupon %s is listed twice", ErrInvalidQuote, code)
+		}
+		codes[code] = true
+	}
+	return nil
+}
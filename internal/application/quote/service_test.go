@@ -0,0 +1,287 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	products []product.Product
+	err      error
+	skus     []string
+}
+
+func (m *mockRepository) GetByVariantSKUs(ctx context.Context, skus []string) ([]product.Product, error) {
+	m.skus = skus
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []product.Product
+	for _, p := range m.products {
+		for _, v := range p.Variants {
+			if contains(skus, v.SKU) {
+				result = append(result, p)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func testProducts() []product.Product {
+	return []product.Product{
+		{
+			Code:     "PROD009",
+			Price:    decimal.RequireFromString("99.99"),
+			Category: &product.Category{Code: "boots"},
+			Variants: []product.Variant{
+				{SKU: "000003", Name: "Size 40", Price: decimal.RequireFromString("99.99")},
+				{SKU: "SKU009B", Name: "Size 41", Price: decimal.RequireFromString("109.99")},
+			},
+		},
+		{
+			Code:     "PROD002",
+			Price:    decimal.RequireFromString("12.34"),
+			Category: &product.Category{Code: "accessories"},
+			Variants: []product.Variant{{SKU: "SKU002A", Name: "Standard", Price: decimal.RequireFromString("12.34")}},
+		},
+	}
+}
+
+func testEngine() *discount.Engine {
+	return discount.NewEngine([]discount.Strategy{
+		discount.NewCategoryDiscountStrategy("boots", 30),
+		discount.NewSKUDiscountStrategy("000003", 15),
+	})
+}
+
+type mockCoupons struct {
+	coupons map[string]discount.Coupon
+	checked []string
+}
+
+func (m *mockCoupons) Check(ctx context.Context, code, customerID string) (discount.Coupon, error) {
+	m.checked = append(m.checked, code+"/"+customerID)
+	c, ok := m.coupons[discount.NormalizeCode(code)]
+	if !ok {
+		return discount.Coupon{}, discount.ErrCouponNotFound
+	}
+	return c, nil
+}
+
+func noCoupons() *mockCoupons {
+	return &mockCoupons{}
+}
+
+func noPromotions(t *testing.T) *discount.BasketEngine {
+	engine, err := discount.NewBasketEngine(nil)
+	require.NoError(t, err)
+	return engine
+}
+
+func TestService_Quote(t *testing.T) {
+	t.Run("prices every line with its variant discount", func(t *testing.T) {
+		service := NewService(&mockRepository{products: testProducts()}, testEngine(), noPromotions(t), noCoupons())
+
+		quote, err := service.Quote(context.Background(), Cart{Items: []Item{
+			{SKU: "SKU002A", Quantity: 3},
+			{SKU: "000003", Quantity: 2},
+			{SKU: "SKU009B", Quantity: 1},
+		}})
+
+		require.NoError(t, err)
+		require.Len(t, quote.Lines, 3)
+
+		assert.Equal(t, "SKU002A", quote.Lines[0].SKU)
+		assert.Equal(t, "PROD002", quote.Lines[0].ProductCode)
+		assert.Equal(t, 0, quote.Lines[0].Percentage)
+		assert.Equal(t, "37.02", quote.Lines[0].Total.StringFixed(2))
+
+		sku := quote.Lines[1]
+		assert.Equal(t, 15, sku.Percentage)
+		assert.Equal(t, "199.98", sku.Subtotal.StringFixed(2))
+		assert.Equal(t, "30.00", sku.Discount.StringFixed(2))
+		assert.Equal(t, "169.98", sku.Total.StringFixed(2))
+
+		category := quote.Lines[2]
+		assert.Equal(t, 30, category.Percentage)
+		assert.Equal(t, "33.00", category.Discount.StringFixed(2))
+		assert.Equal(t, "76.99", category.Total.StringFixed(2))
+
+		assert.Equal(t, "346.99", quote.Subtotal.StringFixed(2))
+		assert.Equal(t, "63.00", quote.Discount.StringFixed(2))
+		assert.Equal(t, "283.99", quote.Total.StringFixed(2))
+		assert.True(t, quote.Subtotal.Sub(quote.Discount).Equal(quote.Total))
+		assert.Empty(t, quote.Promotions)
+		assert.True(t, quote.PromotionDiscount.IsZero())
+	})
+
+	t.Run("keeps item discounts above the cost price", func(t *testing.T) {
+		products := testProducts()
+		products[0].CostPrice = decimal.RequireFromString("80.00")
+		engine := testEngine().WithGuardrails(discount.Guardrails{MinMargin: 5})
+		service := NewService(&mockRepository{products: products}, engine, noPromotions(t), noCoupons())
+
+		quote, err := service.Quote(context.Background(), Cart{Items: []Item{{SKU: "SKU009B", Quantity: 2}}})
+
+		require.NoError(t, err)
+		line := quote.Lines[0]
+		assert.Equal(t, 23, line.Percentage)
+		assert.Equal(t, "51.98", line.Discount.StringFixed(2))
+		assert.Equal(t, "168.00", line.Total.StringFixed(2))
+		require.NotNil(t, line.Limit)
+		assert.Equal(t, discount.LimitMargin, line.Limit.Kind)
+		assert.Equal(t, 30, line.Limit.Requested)
+	})
+
+	t.Run("applies basket promotions after item discounts", func(t *testing.T) {
+		promotions, err := discount.NewBasketEngine([]discount.BasketRule{
+			{Name: "accessories-b2g1", Kind: discount.BasketBundle, Categories: []string{"accessories"}, Quantity: 2, Free: 1, Precedence: discount.PrecedenceAfterItems},
+			{Name: "full-price-10", Kind: discount.BasketThreshold, MinSpend: decimal.NewFromInt(10), Percentage: 10, Precedence: discount.PrecedenceExcludeDiscounted},
+		})
+		require.NoError(t, err)
+		service := NewService(&mockRepository{products: testProducts()}, testEngine(), promotions, noCoupons())
+
+		quote, err := service.Quote(context.Background(), Cart{Items: []Item{
+			{SKU: "SKU002A", Quantity: 3},
+			{SKU: "000003", Quantity: 1},
+		}})
+
+		require.NoError(t, err)
+		require.Len(t, quote.Promotions, 2)
+		assert.Equal(t, "accessories-b2g1", quote.Promotions[0].Name)
+		assert.Equal(t, "12.34", quote.Promotions[0].Discount.StringFixed(2))
+		assert.Equal(t, "full-price-10", quote.Promotions[1].Name)
+		assert.Equal(t, "2.47", quote.Promotions[1].Discount.StringFixed(2))
+
+		accessories := quote.Lines[0]
+		assert.Equal(t, "14.81", accessories.PromotionDiscount.StringFixed(2))
+		assert.Equal(t, "22.21", accessories.Total.StringFixed(2))
+		assert.True(t, quote.Lines[1].PromotionDiscount.IsZero())
+
+		assert.Equal(t, "14.81", quote.PromotionDiscount.StringFixed(2))
+		assert.True(t, quote.Subtotal.Sub(quote.Discount).Sub(quote.PromotionDiscount).Equal(quote.Total))
+	})
+
+	t.Run("applies the promotions unlocked by coupons", func(t *testing.T) {
+		promotions, err := discount.NewBasketEngine([]discount.BasketRule{
+			{Name: "welcome", Kind: discount.BasketThreshold, MinSpend: decimal.NewFromInt(1), Percentage: 10, Precedence: discount.PrecedenceAfterItems, CouponOnly: true},
+		})
+		require.NoError(t, err)
+		coupons := &mockCoupons{coupons: map[string]discount.Coupon{"WELCOME": {Code: "WELCOME", Rule: "welcome"}}}
+		service := NewService(&mockRepository{products: testProducts()}, testEngine(), promotions, coupons)
+		items := []Item{{SKU: "SKU002A", Quantity: 1}}
+
+		quote, err := service.Quote(context.Background(), Cart{Items: items})
+		require.NoError(t, err)
+		assert.Empty(t, quote.Promotions, "coupon-only rules need their coupon")
+
+		quote, err = service.Quote(context.Background(), Cart{Items: items, Coupons: []string{"welcome"}, CustomerID: "c-1"})
+
+		require.NoError(t, err)
+		require.Len(t, quote.Promotions, 1)
+		assert.Equal(t, "WELCOME", quote.Promotions[0].Coupon)
+		assert.Equal(t, "1.23", quote.Promotions[0].Discount.StringFixed(2))
+		assert.Equal(t, []string{"welcome/c-1"}, coupons.checked)
+	})
+
+	t.Run("keeps basket promotions above the guardrail floors", func(t *testing.T) {
+		products := testProducts()
+		products[0].CostPrice = decimal.RequireFromString("80.00")
+		engine := testEngine().WithGuardrails(discount.Guardrails{MinMargin: 5})
+		promotions, err := discount.NewBasketEngine([]discount.BasketRule{
+			{Name: "welcome", Kind: discount.BasketThreshold, MinSpend: decimal.NewFromInt(1), Percentage: 10, Precedence: discount.PrecedenceAfterItems, CouponOnly: true},
+		})
+		require.NoError(t, err)
+		coupons := &mockCoupons{coupons: map[string]discount.Coupon{"WELCOME": {Code: "WELCOME", Rule: "welcome"}}}
+		service := NewService(&mockRepository{products: products}, engine, promotions, coupons)
+
+		quote, err := service.Quote(context.Background(), Cart{
+			Items:   []Item{{SKU: "000003", Quantity: 1}, {SKU: "SKU002A", Quantity: 1}},
+			Coupons: []string{"WELCOME"},
+		})
+
+		require.NoError(t, err)
+		costed := quote.Lines[0]
+		assert.Equal(t, "15.00", costed.Discount.StringFixed(2))
+		assert.Equal(t, "0.99", costed.PromotionDiscount.StringFixed(2))
+		assert.Equal(t, "84.00", costed.Total.StringFixed(2))
+		require.NotNil(t, costed.Limit)
+		assert.Equal(t, discount.LimitMargin, costed.Limit.Kind)
+		assert.Equal(t, 23, costed.Limit.Requested)
+
+		assert.Equal(t, "1.23", quote.Lines[1].PromotionDiscount.StringFixed(2))
+		assert.Nil(t, quote.Lines[1].Limit)
+		require.Len(t, quote.Promotions, 1)
+		assert.Equal(t, "2.22", quote.Promotions[0].Discount.StringFixed(2))
+	})
+
+	t.Run("returns coupon errors", func(t *testing.T) {
+		service := NewService(&mockRepository{products: testProducts()}, testEngine(), noPromotions(t), noCoupons())
+
+		_, err := service.Quote(context.Background(), Cart{Items: []Item{{SKU: "000003", Quantity: 1}}, Coupons: []string{"NOPE"}})
+
+		assert.ErrorIs(t, err, discount.ErrCouponNotFound)
+	})
+
+	t.Run("reports every unknown sku", func(t *testing.T) {
+		service := NewService(&mockRepository{products: testProducts()}, testEngine(), noPromotions(t), noCoupons())
+
+		_, err := service.Quote(context.Background(), Cart{Items: []Item{
+			{SKU: "NOPE", Quantity: 1},
+			{SKU: "000003", Quantity: 1},
+			{SKU: "GONE", Quantity: 1},
+		}})
+
+		var unknown *UnknownSKUsError
+		require.ErrorAs(t, err, &unknown)
+		assert.Equal(t, []string{"NOPE", "GONE"}, unknown.SKUs)
+		assert.ErrorIs(t, err, product.ErrNotFound)
+		assert.EqualError(t, err, "unknown skus: NOPE, GONE")
+	})
+
+	t.Run("rejects invalid items before loading products", func(t *testing.T) {
+		item := []Item{{SKU: "000003", Quantity: 1}}
+		tests := map[string]Cart{
+			"no items":        {},
+			"missing sku":     {Items: []Item{{Quantity: 1}}},
+			"zero quantity":   {Items: []Item{{SKU: "000003"}}},
+			"repeated sku":    {Items: []Item{{SKU: "000003", Quantity: 1}, {SKU: "000003", Quantity: 2}}},
+			"empty coupon":    {Items: item, Coupons: []string{" "}},
+			"repeated coupon": {Items: item, Coupons: []string{"SPRING", "spring"}},
+		}
+		for name, cart := range tests {
+			repo := &mockRepository{products: testProducts()}
+
+			_, err := NewService(repo, testEngine(), noPromotions(t), noCoupons()).Quote(context.Background(), cart)
+
+			assert.ErrorIs(t, err, ErrInvalidQuote, name)
+			assert.Nil(t, repo.skus, name)
+		}
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		repoErr := errors.New("connection refused")
+		service := NewService(&mockRepository{err: repoErr}, testEngine(), noPromotions(t), noCoupons())
+
+		_, err := service.Quote(context.Background(), Cart{Items: []Item{{SKU: "000003", Quantity: 1}}})
+
+		assert.ErrorIs(t, err, repoErr)
+	})
+}
@@ -0,0 +1,125 @@
+package reservation
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+)
+
+// Repository defines ops for reservation persistence. Create returns
+// product.ErrNotFound for unknown SKUs and inventory.ErrInsufficientStock when
+// a line cannot be reserved; the other methods return
+// inventory.ErrReservationNotFound for unknown ids.
+type Repository interface {
+	Create(ctx context.Context, lines []inventory.Line, expiresAt time.Time) (inventory.Reservation, error)
+	Get(ctx context.Context, id uint) (inventory.Reservation, error)
+	Confirm(ctx context.Context, id uint, now time.Time) (inventory.Reservation, error)
+	Cancel(ctx context.Context, id uint, now time.Time) (inventory.Reservation, error)
+	ReleaseExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// Invalidator is notified after reservations take or release units, so that
+// cached catalog responses stop reporting stale availability.
+type Invalidator interface {
+	Invalidate()
+}
+
+// Service defines ops for stock reservations.
+type Service interface {
+	Reserve(ctx context.Context, lines []inventory.Line) (inventory.Reservation, error)
+	Get(ctx context.Context, id uint) (inventory.Reservation, error)
+	Confirm(ctx context.Context, id uint) (inventory.Reservation, error)
+	Cancel(ctx context.Context, id uint) (inventory.Reservation, error)
+	ReleaseExpired(ctx context.Context) (int, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type service struct {
+	repo         Repository
+	ttl          time.Duration
+	invalidators []Invalidator
+	now          func() time.Time
+}
+
+// NewService creates a new reservation service. Reservations expire ttl after
+// they are made. Invalidators are called after every change to stock.
+func NewService(repo Repository, ttl time.Duration, invalidators ...Invalidator) Service {
+	return &service{repo: repo, ttl: ttl, invalidators: invalidators, now: time.Now}
+}
+
+// Reserve holds the quantities of every line until the reservation expires,
+// or holds nothing when any line cannot be reserved.
+func (s *service) Reserve(ctx context.Context, lines []inventory.Line) (inventory.Reservation, error) {
+	if err := inventory.ValidateLines(lines); err != nil {
+		return inventory.Reservation{}, err
+	}
+
+	reservation, err := s.repo.Create(ctx, lines, s.now().Add(s.ttl))
+	if err != nil {
+		return inventory.Reservation{}, err
+	}
+	s.invalidate()
+	return reservation, nil
+}
+
+// Get returns a reservation by id.
+func (s *service) Get(ctx context.Context, id uint) (inventory.Reservation, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Confirm turns a pending reservation into a sale.
+func (s *service) Confirm(ctx context.Context, id uint) (inventory.Reservation, error) {
+	return s.repo.Confirm(ctx, id, s.now())
+}
+
+// Cancel releases a pending reservation.
+func (s *service) Cancel(ctx context.Context, id uint) (inventory.Reservation, error) {
+	reservation, err := s.repo.Cancel(ctx, id, s.now())
+	if err != nil {
+		return inventory.Reservation{}, err
+	}
+	s.invalidate()
+	return reservation, nil
+}
+
+// ReleaseExpired releases every pending reservation past its expiry.
+func (s *service) ReleaseExpired(ctx context.Context) (int, error) {
+	released, err := s.repo.ReleaseExpired(ctx, s.now())
+	if err != nil {
+		return 0, err
+	}
+	if released > 0 {
+		s.invalidate()
+	}
+	return released, nil
+}
+
+// Run releases expired reservations every interval. It blocks until ctx is done.
+func (s *service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		released, err := s.ReleaseExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("reservation: releasing expired reservations failed: %s", err)
+		}
+		if released > 0 {
+			log.Printf("reservation: released %d expired reservations", released)
+		}
+	}
+}
+
+func (s *service) invalidate() {
+	for _, inv := range s.invalidators {
+		inv.Invalidate()
+	}
+}
@@ -0,0 +1,201 @@
+package reservation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type mockRepository struct {
+	reservations map[uint]inventory.Reservation
+	createErr    error
+	expiresAt    time.Time
+	releasedAt   time.Time
+	released     int
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{reservations: make(map[uint]inventory.Reservation)}
+}
+
+func (m *mockRepository) Create(ctx context.Context, lines []inventory.Line, expiresAt time.Time) (inventory.Reservation, error) {
+	if m.createErr != nil {
+		return inventory.Reservation{}, m.createErr
+	}
+	m.expiresAt = expiresAt
+	r := inventory.Reservation{ID: uint(len(m.reservations) + 1), Status: inventory.StatusPending, Lines: lines, ExpiresAt: expiresAt}
+	m.reservations[r.ID] = r
+	return r, nil
+}
+
+func (m *mockRepository) Get(ctx context.Context, id uint) (inventory.Reservation, error) {
+	r, ok := m.reservations[id]
+	if !ok {
+		return inventory.Reservation{}, fmt.Errorf("reservation %d: %w", id, inventory.ErrReservationNotFound)
+	}
+	return r, nil
+}
+
+func (m *mockRepository) Confirm(ctx context.Context, id uint, now time.Time) (inventory.Reservation, error) {
+	return m.close(id, now, inventory.StatusConfirmed)
+}
+
+func (m *mockRepository) Cancel(ctx context.Context, id uint, now time.Time) (inventory.Reservation, error) {
+	return m.close(id, now, inventory.StatusCanceled)
+}
+
+func (m *mockRepository) close(id uint, now time.Time, status inventory.Status) (inventory.Reservation, error) {
+	r, err := m.Get(context.Background(), id)
+	if err != nil {
+		return r, err
+	}
+	if r.Status != inventory.StatusPending || r.Expired(now) {
+		return inventory.Reservation{}, inventory.ErrReservationClosed
+	}
+	r.Status = status
+	m.reservations[id] = r
+	return r, nil
+}
+
+func (m *mockRepository) ReleaseExpired(ctx context.Context, now time.Time) (int, error) {
+	m.releasedAt = now
+	return m.released, nil
+}
+
+type mockInvalidator struct {
+	calls int
+}
+
+func (m *mockInvalidator) Invalidate() {
+	m.calls++
+}
+
+func newTestService(repo Repository, invalidators ...Invalidator) *service {
+	s := NewService(repo, 15*time.Minute, invalidators...).(*service)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestService_Reserve(t *testing.T) {
+	t.Run("reserves the lines until the ttl is over", func(t *testing.T) {
+		repo := newMockRepository()
+		invalidator := &mockInvalidator{}
+		service := newTestService(repo, invalidator)
+
+		reservation, err := service.Reserve(context.Background(), []inventory.Line{{SKU: "000003", Quantity: 2}})
+
+		require.NoError(t, err)
+		assert.Equal(t, inventory.StatusPending, reservation.Status)
+		assert.Equal(t, now.Add(15*time.Minute), repo.expiresAt)
+		assert.Equal(t, 1, invalidator.calls)
+	})
+
+	t.Run("rejects invalid lines before reserving", func(t *testing.T) {
+		repo := newMockRepository()
+		service := newTestService(repo)
+
+		_, err := service.Reserve(context.Background(), []inventory.Line{{SKU: "000003", Quantity: 0}})
+
+		assert.ErrorIs(t, err, inventory.ErrInvalidReservation)
+		assert.Empty(t, repo.reservations)
+	})
+
+	t.Run("returns repository errors without invalidating", func(t *testing.T) {
+		repo := newMockRepository()
+		repo.createErr = fmt.Errorf("%w: 000003 has 1 available, 2 requested", inventory.ErrInsufficientStock)
+		invalidator := &mockInvalidator{}
+		service := newTestService(repo, invalidator)
+
+		_, err := service.Reserve(context.Background(), []inventory.Line{{SKU: "000003", Quantity: 2}})
+
+		assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+		assert.Zero(t, invalidator.calls)
+	})
+}
+
+func TestService_ConfirmAndCancel(t *testing.T) {
+	lines := []inventory.Line{{SKU: "000003", Quantity: 1}}
+
+	t.Run("confirms a pending reservation", func(t *testing.T) {
+		service := newTestService(newMockRepository())
+		r, err := service.Reserve(context.Background(), lines)
+		require.NoError(t, err)
+
+		confirmed, err := service.Confirm(context.Background(), r.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, inventory.StatusConfirmed, confirmed.Status)
+	})
+
+	t.Run("cancels a pending reservation and invalidates", func(t *testing.T) {
+		invalidator := &mockInvalidator{}
+		service := newTestService(newMockRepository(), invalidator)
+		r, err := service.Reserve(context.Background(), lines)
+		require.NoError(t, err)
+
+		canceled, err := service.Cancel(context.Background(), r.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, inventory.StatusCanceled, canceled.Status)
+		assert.Equal(t, 2, invalidator.calls)
+	})
+
+	t.Run("refuses expired and closed reservations", func(t *testing.T) {
+		service := newTestService(newMockRepository())
+		r, err := service.Reserve(context.Background(), lines)
+		require.NoError(t, err)
+		_, err = service.Confirm(context.Background(), r.ID)
+		require.NoError(t, err)
+
+		_, err = service.Cancel(context.Background(), r.ID)
+		assert.ErrorIs(t, err, inventory.ErrReservationClosed)
+
+		expiring, err := service.Reserve(context.Background(), lines)
+		require.NoError(t, err)
+		service.now = func() time.Time { return now.Add(15 * time.Minute) }
+		_, err = service.Confirm(context.Background(), expiring.ID)
+		assert.ErrorIs(t, err, inventory.ErrReservationClosed)
+	})
+
+	t.Run("returns not found for unknown reservations", func(t *testing.T) {
+		service := newTestService(newMockRepository())
+
+		_, err := service.Confirm(context.Background(), 42)
+
+		assert.ErrorIs(t, err, inventory.ErrReservationNotFound)
+	})
+}
+
+func TestService_ReleaseExpired(t *testing.T) {
+	t.Run("releases at the current time and invalidates when any was released", func(t *testing.T) {
+		repo := newMockRepository()
+		repo.released = 2
+		invalidator := &mockInvalidator{}
+		service := newTestService(repo, invalidator)
+
+		released, err := service.ReleaseExpired(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, released)
+		assert.Equal(t, now, repo.releasedAt)
+		assert.Equal(t, 1, invalidator.calls)
+	})
+
+	t.Run("does not invalidate when nothing expired", func(t *testing.T) {
+		invalidator := &mockInvalidator{}
+		service := newTestService(newMockRepository(), invalidator)
+
+		released, err := service.ReleaseExpired(context.Background())
+
+		require.NoError(t, err)
+		assert.Zero(t, released)
+		assert.Zero(t, invalidator.calls)
+	})
+}
@@ -0,0 +1,284 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
+
+// ErrInvalidFixture is returned when a fixture entry is incomplete.
+var ErrInvalidFixture = errors.New("invalid fixture")
+
+// Transactor runs fn in a transaction shared by the repositories called with its context.
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// CategoryRepository upserts categories by code.
+type CategoryRepository interface {
+	Upsert(ctx context.Context, cat product.Category) (product.Change, error)
+}
+
+// ProductRepository upserts products by code and their variants by SKU.
+type ProductRepository interface {
+	Upsert(ctx context.Context, p product.Product) (product.Change, error)
+}
+
+// RuleRepository upserts discount rules by kind, target and audience.
+type RuleRepository interface {
+	Upsert(ctx context.Context, rule discount.Rule) (product.Change, error)
+}
+
+// CapRepository upserts category discount caps by category.
+type CapRepository interface {
+	Upsert(ctx context.Context, c discount.Cap) (product.Change, error)
+}
+
+// BasketRuleRepository upserts basket rules by name.
+type BasketRuleRepository interface {
+	Upsert(ctx context.Context, rule discount.BasketRule) (product.Change, error)
+}
+
+// CouponRepository upserts coupons by code, resolving their basket rule by name.
+type CouponRepository interface {
+	Upsert(ctx context.Context, coupon discount.Coupon) (product.Change, error)
+}
+
+// StockRepository replaces the stock levels of variants by SKU.
+type StockRepository interface {
+	Replace(ctx context.Context, stock inventory.Stock) (product.Change, error)
+}
+
+// Fixture is a data set to load. Products reference their category by code and
+// variants with a zero price inherit the product price; a zero cost price keeps
+// the stored one. Rules and basket rules are evaluated in the order they are
+// listed. Coupons reference a coupon-only basket rule of the fixture by name.
+// Stock references variants of the fixture by SKU; variants without an entry
+// keep their stored stock.
+type Fixture struct {
+	Categories  []product.Category
+	Products    []product.Product
+	Rules       []discount.Rule
+	Caps        []discount.Cap
+	BasketRules []discount.BasketRule
+	Coupons     []discount.Coupon
+	Stock       []inventory.Stock
+}
+
+// Counts tells how many records of one kind a seed created, updated or left as they were.
+type Counts struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+func (c *Counts) add(change product.Change) {
+	switch change {
+	case product.Created:
+		c.Created++
+	case product.Updated:
+		c.Updated++
+	default:
+		c.Unchanged++
+	}
+}
+
+// Report summarizes a seed.
+type Report struct {
+	Categories  Counts
+	Products    Counts
+	Rules       Counts
+	Caps        Counts
+	BasketRules Counts
+	Coupons     Counts
+	Stock       Counts
+}
+
+// Service loads fixtures through the repositories.
+type Service interface {
+	Seed(ctx context.Context, fixture Fixture) (Report, error)
+}
+
+type service struct {
+	transactor  Transactor
+	categories  CategoryRepository
+	products    ProductRepository
+	rules       RuleRepository
+	caps        CapRepository
+	basketRules BasketRuleRepository
+	coupons     CouponRepository
+	stock       StockRepository
+}
+
+// NewService creates a new seed service writing through the repositories in
+// transactions of the transactor.
+func NewService(transactor Transactor, categories CategoryRepository, products ProductRepository, rules RuleRepository, caps CapRepository, basketRules BasketRuleRepository, coupons CouponRepository, stock StockRepository) Service {
+	return &service{transactor: transactor, categories: categories, products: products, rules: rules, caps: caps, basketRules: basketRules, coupons: coupons, stock: stock}
+}
+
+// Seed validates the whole fixture, then upserts categories, products, rules,
+// caps, basket rules, coupons and stock in that order, in one transaction, so
+// seeding the same fixture twice changes nothing and a failing record leaves
+// nothing behind. Coupon redemptions are kept.
+func (s *service) Seed(ctx context.Context, fixture Fixture) (Report, error) {
+	if err := validate(fixture); err != nil {
+		return Report{}, err
+	}
+
+	var report Report
+	err := s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		report = Report{}
+		return s.upsert(ctx, fixture, &report)
+	})
+	if err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+// upsert writes the records of a valid fixture, counting the changes in report.
+func (s *service) upsert(ctx context.Context, fixture Fixture, report *Report) error {
+	for _, cat := range fixture.Categories {
+		change, err := s.categories.Upsert(ctx, cat)
+		if err != nil {
+			return fmt.Errorf("category %s: %w", cat.Code, err)
+		}
+		report.Categories.add(change)
+	}
+
+	for _, p := range fixture.Products {
+		change, err := s.products.Upsert(ctx, p)
+		if err != nil {
+			return fmt.Errorf("product %s: %w", p.Code, err)
+		}
+		report.Products.add(change)
+	}
+
+	for i, rule := range fixture.Rules {
+		rule.Position = i + 1
+		change, err := s.rules.Upsert(ctx, rule)
+		if err != nil {
+			return fmt.Errorf("discount rule %s %s: %w", rule.Kind, rule.Target, err)
+		}
+		report.Rules.add(change)
+	}
+
+	for _, c := range fixture.Caps {
+		change, err := s.caps.Upsert(ctx, c)
+		if err != nil {
+			return fmt.Errorf("discount cap of %s: %w", c.Category, err)
+		}
+		report.Caps.add(change)
+	}
+
+	for i, rule := range fixture.BasketRules {
+		rule.Position = i + 1
+		change, err := s.basketRules.Upsert(ctx, rule)
+		if err != nil {
+			return fmt.Errorf("basket rule %s: %w", rule.Name, err)
+		}
+		report.BasketRules.add(change)
+	}
+
+	for _, coupon := range fixture.Coupons {
+		coupon.Code = discount.NormalizeCode(coupon.Code)
+		change, err := s.coupons.Upsert(ctx, coupon)
+		if err != nil {
+			return fmt.Errorf("coupon %s: %w", coupon.Code, err)
+		}
+		report.Coupons.add(change)
+	}
+
+	for _, stock := range fixture.Stock {
+		change, err := s.stock.Replace(ctx, stock)
+		if err != nil {
+			return fmt.Errorf("stock of %s: %w", stock.SKU, err)
+		}
+		report.Stock.add(change)
+	}
+
+	return nil
+}
+
+func validate(fixture Fixture) error {
+	for _, cat := range fixture.Categories {
+		if cat.Code == "" || cat.Name == "" {
+			return fmt.Errorf("%w: category %q needs a code and a name", ErrInvalidFixture, cat.Code)
+		}
+	}
+
+	skus := make(map[string]string)
+	for _, p := range fixture.Products {
+		if p.Code == "" {
+			return fmt.Errorf("%w: product without code", ErrInvalidFixture)
+		}
+		if !p.Price.IsPositive() {
+			return fmt.Errorf("%w: product %s needs a positive price", ErrInvalidFixture, p.Code)
+		}
+		if p.CostPrice.IsNegative() {
+			return fmt.Errorf("%w: product %s has a negative cost price", ErrInvalidFixture, p.Code)
+		}
+		for _, v := range p.Variants {
+			if v.SKU == "" || v.Name == "" {
+				return fmt.Errorf("%w: variant of product %s needs a SKU and a name", ErrInvalidFixture, p.Code)
+			}
+			if v.Price.IsNegative() {
+				return fmt.Errorf("%w: variant %s has a negative price", ErrInvalidFixture, v.SKU)
+			}
+			if owner, ok := skus[v.SKU]; ok {
+				return fmt.Errorf("%w: SKU %s is listed under %s and %s", ErrInvalidFixture, v.SKU, owner, p.Code)
+			}
+			skus[v.SKU] = p.Code
+		}
+	}
+
+	for _, rule := range fixture.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFixture, err)
+		}
+	}
+
+	if err := (discount.Guardrails{Caps: fixture.Caps}).Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFixture, err)
+	}
+
+	couponOnly := make(map[string]bool, len(fixture.BasketRules))
+	for _, rule := range fixture.BasketRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFixture, err)
+		}
+		if _, ok := couponOnly[rule.Name]; ok {
+			return fmt.Errorf("%w: basket rule %s is listed twice", ErrInvalidFixture, rule.Name)
+		}
+		couponOnly[rule.Name] = rule.CouponOnly
+	}
+
+	codes := make(map[string]bool, len(fixture.Coupons))
+	for _, coupon := range fixture.Coupons {
+		if err := coupon.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFixture, err)
+		}
+		code := discount.NormalizeCode(coupon.Code)
+		if codes[code] {
+			return fmt.Errorf("%w: coupon %s is listed twice", ErrInvalidFixture, code)
+		}
+		codes[code] = true
+		if !couponOnly[coupon.Rule] {
+			return fmt.Errorf("%w: coupon %s needs a coupon-only basket rule of the fixture, not %q", ErrInvalidFixture, code, coupon.Rule)
+		}
+	}
+
+	for _, stock := range fixture.Stock {
+		if _, ok := skus[stock.SKU]; !ok {
+			return fmt.Errorf("%w: stock of unknown SKU %s", ErrInvalidFixture, stock.SKU)
+		}
+		if err := stock.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFixture, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockStore upserts into maps, reporting Unchanged for identical records.
+type mockStore struct {
+	categories  map[string]product.Category
+	products    map[string]string
+	rules       []discount.Rule
+	caps        []discount.Cap
+	basketRules []discount.BasketRule
+	coupons     []discount.Coupon
+	stock       map[string]int
+	err         error
+	stockErr    error
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{categories: map[string]product.Category{}, products: map[string]string{}, stock: map[string]int{}}
+}
+
+// InTransaction runs fn and restores the maps when it fails.
+func (m *mockStore) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	categories, products, stock := maps.Clone(m.categories), maps.Clone(m.products), maps.Clone(m.stock)
+	rules, caps, basketRules, coupons := len(m.rules), len(m.caps), len(m.basketRules), len(m.coupons)
+	if err := fn(ctx); err != nil {
+		m.categories, m.products, m.stock = categories, products, stock
+		m.rules, m.caps, m.basketRules, m.coupons = m.rules[:rules], m.caps[:caps], m.basketRules[:basketRules], m.coupons[:coupons]
+		return err
+	}
+	return nil
+}
+
+type categoryUpserter struct{ *mockStore }
+
+func (m categoryUpserter) Upsert(ctx context.Context, cat product.Category) (product.Change, error) {
+	if m.err != nil {
+		return product.Unchanged, m.err
+	}
+	existing, ok := m.categories[cat.Code]
+	m.categories[cat.Code] = cat
+	switch {
+	case !ok:
+		return product.Created, nil
+	case existing.Name != cat.Name:
+		return product.Updated, nil
+	default:
+		return product.Unchanged, nil
+	}
+}
+
+type productUpserter struct{ *mockStore }
+
+func (m productUpserter) Upsert(ctx context.Context, p product.Product) (product.Change, error) {
+	existing, ok := m.products[p.Code]
+	m.products[p.Code] = p.Price.String()
+	switch {
+	case !ok:
+		return product.Created, nil
+	case existing != p.Price.String():
+		return product.Updated, nil
+	default:
+		return product.Unchanged, nil
+	}
+}
+
+type ruleUpserter struct{ *mockStore }
+
+func (m ruleUpserter) Upsert(ctx context.Context, rule discount.Rule) (product.Change, error) {
+	m.rules = append(m.rules, rule)
+	return product.Created, nil
+}
+
+type capUpserter struct{ *mockStore }
+
+func (m capUpserter) Upsert(ctx context.Context, c discount.Cap) (product.Change, error) {
+	m.caps = append(m.caps, c)
+	return product.Created, nil
+}
+
+type basketRuleUpserter struct{ *mockStore }
+
+func (m basketRuleUpserter) Upsert(ctx context.Context, rule discount.BasketRule) (product.Change, error) {
+	m.basketRules = append(m.basketRules, rule)
+	return product.Created, nil
+}
+
+type couponUpserter struct{ *mockStore }
+
+func (m couponUpserter) Upsert(ctx context.Context, coupon discount.Coupon) (product.Change, error) {
+	m.coupons = append(m.coupons, coupon)
+	return product.Created, nil
+}
+
+type stockReplacer struct{ *mockStore }
+
+func (m stockReplacer) Replace(ctx context.Context, stock inventory.Stock) (product.Change, error) {
+	if m.stockErr != nil {
+		return product.Unchanged, m.stockErr
+	}
+	existing, ok := m.stock[stock.SKU]
+	m.stock[stock.SKU] = stock.Quantity()
+	switch {
+	case !ok:
+		return product.Created, nil
+	case existing != stock.Quantity():
+		return product.Updated, nil
+	default:
+		return product.Unchanged, nil
+	}
+}
+
+func newTestService(store *mockStore) Service {
+	return NewService(store, categoryUpserter{store}, productUpserter{store}, ruleUpserter{store}, capUpserter{store}, basketRuleUpserter{store}, couponUpserter{store}, stockReplacer{store})
+}
+
+func testFixture() Fixture {
+	return Fixture{
+		Categories: []product.Category{{Code: "boots", Name: "Boots"}},
+		Products: []product.Product{
+			{
+				Code:     "PROD009",
+				Price:    decimal.NewFromFloat(89.99),
+				Category: &product.Category{Code: "boots"},
+				Variants: []product.Variant{{SKU: "000003", Name: "Standard"}},
+			},
+		},
+		Rules: []discount.Rule{
+			{Kind: discount.RuleCategory, Target: "boots", Percentage: 30},
+			{Kind: discount.RuleSKU, Target: "000003", Percentage: 15},
+		},
+		Caps: []discount.Cap{{Category: "boots", Percentage: 25}},
+		BasketRules: []discount.BasketRule{
+			{Name: "boots-b2g1", Kind: discount.BasketBundle, Categories: []string{"boots"}, Quantity: 2, Free: 1, Precedence: discount.PrecedenceAfterItems},
+			{Name: "welcome", Kind: discount.BasketThreshold, MinSpend: decimal.NewFromInt(1), Percentage: 10, Precedence: discount.PrecedenceAfterItems, CouponOnly: true},
+		},
+		Coupons: []discount.Coupon{{Code: "welcome10", Rule: "welcome", MaxPerCustomer: 1}},
+		Stock: []inventory.Stock{
+			{SKU: "000003", Levels: []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: 5}}},
+		},
+	}
+}
+
+func TestService_Seed(t *testing.T) {
+	t.Run("upserts every record and reports the changes", func(t *testing.T) {
+		store := newMockStore()
+
+		report, err := newTestService(store).Seed(context.Background(), testFixture())
+
+		require.NoError(t, err)
+		assert.Equal(t, Counts{Created: 1}, report.Categories)
+		assert.Equal(t, Counts{Created: 1}, report.Products)
+		assert.Equal(t, Counts{Created: 2}, report.Rules)
+		assert.Equal(t, Counts{Created: 1}, report.Caps)
+		assert.Equal(t, Counts{Created: 2}, report.BasketRules)
+		assert.Equal(t, Counts{Created: 1}, report.Coupons)
+		assert.Equal(t, Counts{Created: 1}, report.Stock)
+		assert.Contains(t, store.products, "PROD009")
+		assert.Equal(t, 5, store.stock["000003"])
+	})
+
+	t.Run("is idempotent", func(t *testing.T) {
+		store := newMockStore()
+		service := newTestService(store)
+		_, err := service.Seed(context.Background(), testFixture())
+		require.NoError(t, err)
+
+		report, err := service.Seed(context.Background(), testFixture())
+
+		require.NoError(t, err)
+		assert.Equal(t, Counts{Unchanged: 1}, report.Categories)
+		assert.Equal(t, Counts{Unchanged: 1}, report.Products)
+		assert.Equal(t, Counts{Unchanged: 1}, report.Stock)
+	})
+
+	t.Run("reports updates", func(t *testing.T) {
+		store := newMockStore()
+		service := newTestService(store)
+		_, err := service.Seed(context.Background(), testFixture())
+		require.NoError(t, err)
+
+		fixture := testFixture()
+		fixture.Products[0].Price = decimal.NewFromFloat(79.99)
+		report, err := service.Seed(context.Background(), fixture)
+
+		require.NoError(t, err)
+		assert.Equal(t, Counts{Updated: 1}, report.Products)
+	})
+
+	t.Run("positions rules in fixture order", func(t *testing.T) {
+		store := newMockStore()
+
+		_, err := newTestService(store).Seed(context.Background(), testFixture())
+
+		require.NoError(t, err)
+		require.Len(t, store.rules, 2)
+		assert.Equal(t, 1, store.rules[0].Position)
+		assert.Equal(t, 2, store.rules[1].Position)
+		require.Len(t, store.basketRules, 2)
+		assert.Equal(t, 1, store.basketRules[0].Position)
+		assert.Equal(t, 2, store.basketRules[1].Position)
+		require.Len(t, store.coupons, 1)
+		assert.Equal(t, "WELCOME10", store.coupons[0].Code, "codes are stored upper case")
+	})
+
+	t.Run("rejects invalid fixtures before writing", func(t *testing.T) {
+		tests := map[string]func(*Fixture){
+			"category without name": func(f *Fixture) { f.Categories[0].Name = "" },
+			"product without code":  func(f *Fixture) { f.Products[0].Code = "" },
+			"product without price": func(f *Fixture) { f.Products[0].Price = decimal.Zero },
+			"variant without sku":   func(f *Fixture) { f.Products[0].Variants[0].SKU = "" },
+			"duplicate sku": func(f *Fixture) {
+				f.Products = append(f.Products, product.Product{
+					Code:     "PROD010",
+					Price:    decimal.NewFromInt(10),
+					Variants: []product.Variant{{SKU: "000003", Name: "Copy"}},
+				})
+			},
+			"negative cost price": func(f *Fixture) { f.Products[0].CostPrice = decimal.NewFromInt(-1) },
+			"invalid rule":        func(f *Fixture) { f.Rules[0].Percentage = 150 },
+			"invalid expression": func(f *Fixture) {
+				f.Rules = append(f.Rules, discount.Rule{Kind: discount.RuleExpression, Target: "premium", Expression: "price >", Percentage: 10})
+			},
+			"invalid cap":          func(f *Fixture) { f.Caps[0].Percentage = 120 },
+			"repeated cap":         func(f *Fixture) { f.Caps = append(f.Caps, f.Caps[0]) },
+			"invalid basket rule":  func(f *Fixture) { f.BasketRules[0].Free = 0 },
+			"repeated basket rule": func(f *Fixture) { f.BasketRules = append(f.BasketRules, f.BasketRules[0]) },
+			"invalid coupon":       func(f *Fixture) { f.Coupons[0].MaxRedemptions = -1 },
+			"repeated coupon":      func(f *Fixture) { f.Coupons = append(f.Coupons, discount.Coupon{Code: "WELCOME10", Rule: "welcome"}) },
+			"coupon of auto rule":  func(f *Fixture) { f.Coupons[0].Rule = "boots-b2g1" },
+			"coupon of no rule":    func(f *Fixture) { f.Coupons[0].Rule = "nope" },
+			"stock of unknown sku": func(f *Fixture) { f.Stock[0].SKU = "NOPE" },
+			"negative stock":       func(f *Fixture) { f.Stock[0].Levels[0].Quantity = -1 },
+		}
+		for name, mutate := range tests {
+			store := newMockStore()
+			fixture := testFixture()
+			mutate(&fixture)
+
+			_, err := newTestService(store).Seed(context.Background(), fixture)
+
+			assert.ErrorIs(t, err, ErrInvalidFixture, name)
+			assert.Empty(t, store.categories, name)
+		}
+	})
+
+	t.Run("stops at the first repository error", func(t *testing.T) {
+		store := newMockStore()
+		store.err = errors.New("connection refused")
+
+		_, err := newTestService(store).Seed(context.Background(), testFixture())
+
+		assert.ErrorContains(t, err, "category boots: connection refused")
+		assert.Empty(t, store.products)
+	})
+
+	t.Run("rolls back every record when a later one fails", func(t *testing.T) {
+		store := newMockStore()
+		store.stockErr = errors.New("connection refused")
+
+		report, err := newTestService(store).Seed(context.Background(), testFixture())
+
+		assert.ErrorContains(t, err, "stock of 000003: connection refused")
+		assert.Equal(t, Report{}, report)
+		assert.Empty(t, store.categories)
+		assert.Empty(t, store.products)
+		assert.Empty(t, store.rules)
+		assert.Empty(t, store.coupons)
+	})
+}
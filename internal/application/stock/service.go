@@ -0,0 +1,64 @@
+package stock
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
+
+// Repository defines ops for stock persistence. Both methods return
+// product.ErrNotFound for unknown SKUs. Replace takes on-hand levels and
+// returns inventory.ErrReservedStock when they are below the units held by
+// pending reservations; GetBySKU returns the units that can still be sold.
+type Repository interface {
+	GetBySKU(ctx context.Context, sku string) (inventory.Stock, error)
+	Replace(ctx context.Context, stock inventory.Stock) (product.Change, error)
+}
+
+// Invalidator is notified after stock changes, so that cached catalog
+// responses stop reporting stale availability.
+type Invalidator interface {
+	Invalidate()
+}
+
+// Service defines ops for variant stock.
+type Service interface {
+	GetStock(ctx context.Context, sku string) (inventory.Stock, error)
+	SetStock(ctx context.Context, stock inventory.Stock) (inventory.Stock, error)
+}
+
+type service struct {
+	repo         Repository
+	invalidators []Invalidator
+}
+
+// NewService creates a new stock service.
+// Invalidators are called after every write that changed stock.
+func NewService(repo Repository, invalidators ...Invalidator) Service {
+	return &service{repo: repo, invalidators: invalidators}
+}
+
+// GetStock returns the stock of a variant per warehouse.
+func (s *service) GetStock(ctx context.Context, sku string) (inventory.Stock, error) {
+	return s.repo.GetBySKU(ctx, sku)
+}
+
+// SetStock replaces every on-hand stock level of a variant and returns the
+// stored stock, which leaves out units held by pending reservations.
+func (s *service) SetStock(ctx context.Context, stock inventory.Stock) (inventory.Stock, error) {
+	if err := stock.Validate(); err != nil {
+		return inventory.Stock{}, err
+	}
+
+	change, err := s.repo.Replace(ctx, stock)
+	if err != nil {
+		return inventory.Stock{}, err
+	}
+	if change != product.Unchanged {
+		for _, inv := range s.invalidators {
+			inv.Invalidate()
+		}
+	}
+	return s.repo.GetBySKU(ctx, stock.SKU)
+}
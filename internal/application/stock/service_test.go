@@ -0,0 +1,115 @@
+package stock
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	stock    map[string]inventory.Stock
+	replaced []inventory.Stock
+	change   product.Change
+}
+
+func (m *mockRepository) GetBySKU(ctx context.Context, sku string) (inventory.Stock, error) {
+	stock, ok := m.stock[sku]
+	if !ok {
+		return inventory.Stock{}, fmt.Errorf("variant %s: %w", sku, product.ErrNotFound)
+	}
+	return stock, nil
+}
+
+func (m *mockRepository) Replace(ctx context.Context, stock inventory.Stock) (product.Change, error) {
+	if _, ok := m.stock[stock.SKU]; !ok {
+		return product.Unchanged, fmt.Errorf("variant %s: %w", stock.SKU, product.ErrNotFound)
+	}
+	m.replaced = append(m.replaced, stock)
+	m.stock[stock.SKU] = stock.Sorted()
+	return m.change, nil
+}
+
+type mockInvalidator struct {
+	calls int
+}
+
+func (m *mockInvalidator) Invalidate() {
+	m.calls++
+}
+
+func TestService_GetStock(t *testing.T) {
+	t.Run("returns the stored stock", func(t *testing.T) {
+		expected := inventory.Stock{SKU: "000003", Levels: []inventory.Level{{Warehouse: "berlin", Quantity: 2}}}
+		service := NewService(&mockRepository{stock: map[string]inventory.Stock{"000003": expected}})
+
+		stock, err := service.GetStock(context.Background(), "000003")
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, stock)
+	})
+
+	t.Run("returns not found for unknown variants", func(t *testing.T) {
+		service := NewService(&mockRepository{})
+
+		_, err := service.GetStock(context.Background(), "NOPE")
+
+		assert.ErrorIs(t, err, product.ErrNotFound)
+	})
+}
+
+func TestService_SetStock(t *testing.T) {
+	newRepository := func(change product.Change) *mockRepository {
+		return &mockRepository{stock: map[string]inventory.Stock{"000003": {SKU: "000003"}}, change: change}
+	}
+
+	t.Run("replaces the stock and invalidates caches", func(t *testing.T) {
+		repo := newRepository(product.Updated)
+		invalidator := &mockInvalidator{}
+		service := NewService(repo, invalidator)
+
+		stock, err := service.SetStock(context.Background(), inventory.Stock{SKU: "000003", Levels: []inventory.Level{
+			{Warehouse: "madrid", Quantity: 1}, {Warehouse: "berlin", Quantity: 2},
+		}})
+
+		require.NoError(t, err)
+		require.Len(t, repo.replaced, 1)
+		assert.Equal(t, "berlin", stock.Levels[0].Warehouse)
+		assert.Equal(t, 3, stock.Quantity())
+		assert.Equal(t, 1, invalidator.calls)
+	})
+
+	t.Run("does not invalidate when nothing changed", func(t *testing.T) {
+		invalidator := &mockInvalidator{}
+		service := NewService(newRepository(product.Unchanged), invalidator)
+
+		_, err := service.SetStock(context.Background(), inventory.Stock{SKU: "000003"})
+
+		require.NoError(t, err)
+		assert.Zero(t, invalidator.calls)
+	})
+
+	t.Run("rejects invalid stock without writing", func(t *testing.T) {
+		repo := newRepository(product.Updated)
+		service := NewService(repo)
+
+		_, err := service.SetStock(context.Background(), inventory.Stock{SKU: "000003", Levels: []inventory.Level{
+			{Warehouse: inventory.DefaultWarehouse, Quantity: -1},
+		}})
+
+		assert.ErrorIs(t, err, inventory.ErrInvalidStock)
+		assert.Empty(t, repo.replaced)
+	})
+
+	t.Run("returns not found for unknown variants", func(t *testing.T) {
+		service := NewService(newRepository(product.Updated))
+
+		_, err := service.SetStock(context.Background(), inventory.Stock{SKU: "NOPE"})
+
+		assert.ErrorIs(t, err, product.ErrNotFound)
+	})
+}
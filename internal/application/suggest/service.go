@@ -0,0 +1,212 @@
+package suggest
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
+
+// ProductRepository defines the product reads needed to build the index.
+type ProductRepository interface {
+	GetAll(ctx context.Context) ([]product.Product, error)
+}
+
+// CategoryRepository defines the category reads needed to build the index.
+type CategoryRepository interface {
+	GetAll(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error)
+}
+
+// Suggestions holds the matches for a prefix, grouped by kind.
+type Suggestions struct {
+	Products   []string
+	Variants   []string
+	Categories []string
+}
+
+// Service defines operations for typeahead suggestions.
+type Service interface {
+	Suggest(prefix string, limit int) Suggestions
+	Refresh(ctx context.Context) error
+	Invalidate()
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type kind int
+
+const (
+	kindProduct kind = iota
+	kindVariant
+	kindCategory
+)
+
+type entry struct {
+	term  string
+	kind  kind
+	value string
+}
+
+type service struct {
+	products   ProductRepository
+	categories CategoryRepository
+
+	mu      sync.RWMutex
+	entries []entry
+
+	refresh chan struct{}
+}
+
+// NewService creates a suggestion service. The index is empty until Refresh is called.
+func NewService(products ProductRepository, categories CategoryRepository) Service {
+	return &service{
+		products:   products,
+		categories: categories,
+		refresh:    make(chan struct{}, 1),
+	}
+}
+
+// Suggest returns up to limit matches of each kind whose name starts with the prefix,
+// or has a word starting with it. Matching is case-insensitive.
+func (s *service) Suggest(prefix string, limit int) Suggestions {
+	result := Suggestions{
+		Products:   []string{},
+		Variants:   []string{},
+		Categories: []string{},
+	}
+
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || limit <= 0 {
+		return result
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[entry]bool)
+	start := sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].term >= prefix
+	})
+	for i := start; i < len(s.entries) && strings.HasPrefix(s.entries[i].term, prefix); i++ {
+		e := s.entries[i]
+		key := entry{kind: e.kind, value: e.value}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		switch e.kind {
+		case kindProduct:
+			if len(result.Products) < limit {
+				result.Products = append(result.Products, e.value)
+			}
+		case kindVariant:
+			if len(result.Variants) < limit {
+				result.Variants = append(result.Variants, e.value)
+			}
+		case kindCategory:
+			if len(result.Categories) < limit {
+				result.Categories = append(result.Categories, e.value)
+			}
+		}
+
+		if len(result.Products) == limit && len(result.Variants) == limit && len(result.Categories) == limit {
+			break
+		}
+	}
+
+	return result
+}
+
+// Refresh rebuilds the index from the repositories and swaps it in atomically.
+func (s *service) Refresh(ctx context.Context) error {
+	products, err := s.products.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	categories, _, err := s.categories.GetAll(ctx, product.CategoryQuery{})
+	if err != nil {
+		return err
+	}
+
+	var entries []entry
+	for _, p := range products {
+		entries = appendTerms(entries, kindProduct, p.Code)
+		for _, v := range p.Variants {
+			entries = appendTerms(entries, kindVariant, v.Name)
+		}
+	}
+	for _, c := range categories {
+		entries = appendTerms(entries, kindCategory, c.Name)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].term != entries[j].term {
+			return entries[i].term < entries[j].term
+		}
+		return entries[i].kind < entries[j].kind
+	})
+
+	s.mu.Lock()
+	s.entries = entries
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Invalidate schedules a rebuild of the index. Calls made while a rebuild is
+// already pending are coalesced into it.
+func (s *service) Invalidate() {
+	select {
+	case s.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// Run rebuilds the index whenever it is invalidated and, if interval is positive,
+// periodically to pick up writes made outside the API. It blocks until ctx is done.
+func (s *service) Run(ctx context.Context, interval time.Duration) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.refresh:
+		case <-tick:
+		}
+
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("suggest: refreshing index failed: %s", err)
+		}
+	}
+}
+
+// appendTerms indexes the value under its full name and under every word after the first,
+// so "Premium Leather" is found by both "prem" and "leath".
+func appendTerms(entries []entry, k kind, value string) []entry {
+	if value == "" {
+		return entries
+	}
+
+	term := strings.ToLower(value)
+	entries = append(entries, entry{term: term, kind: k, value: value})
+	for i := strings.IndexByte(term, ' '); i >= 0; i = strings.IndexByte(term, ' ') {
+		term = strings.TrimLeft(term[i:], " ")
+		if term == "" {
+			break
+		}
+		entries = append(entries, entry{term: term, kind: k, value: value})
+	}
+
+	return entries
+}
@@ -0,0 +1,145 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProductRepository struct {
+	products []product.Product
+	err      error
+}
+
+func (m *mockProductRepository) GetAll(ctx context.Context) ([]product.Product, error) {
+	return m.products, m.err
+}
+
+type mockCategoryRepository struct {
+	categories []product.Category
+	err        error
+}
+
+func (m *mockCategoryRepository) GetAll(context.Context, product.CategoryQuery) ([]product.CategorySummary, int64, error) {
+	summaries := make([]product.CategorySummary, len(m.categories))
+	for i, c := range m.categories {
+		summaries[i] = product.CategorySummary{Category: c}
+	}
+	return summaries, int64(len(summaries)), m.err
+}
+
+func newTestService(t *testing.T) (Service, *mockProductRepository, *mockCategoryRepository) {
+	products := &mockProductRepository{
+		products: []product.Product{
+			{Code: "PROD001", Variants: []product.Variant{{Name: "Variant A"}, {Name: "Premium Leather"}}},
+			{Code: "PROD002", Variants: []product.Variant{{Name: "Variant A"}}},
+			{Code: "SHOE01"},
+		},
+	}
+	categories := &mockCategoryRepository{
+		categories: []product.Category{
+			{Code: "shoes", Name: "Shoes"},
+			{Code: "accessories", Name: "Accessories"},
+		},
+	}
+
+	service := NewService(products, categories)
+	require.NoError(t, service.Refresh(context.Background()))
+
+	return service, products, categories
+}
+
+func TestService_Suggest(t *testing.T) {
+	t.Run("matches product codes by prefix case-insensitively", func(t *testing.T) {
+		service, _, _ := newTestService(t)
+
+		suggestions := service.Suggest("prod", 5)
+
+		assert.Equal(t, []string{"PROD001", "PROD002"}, suggestions.Products)
+		assert.Empty(t, suggestions.Categories)
+	})
+
+	t.Run("deduplicates variant names shared by several products", func(t *testing.T) {
+		service, _, _ := newTestService(t)
+
+		suggestions := service.Suggest("var", 5)
+
+		assert.Equal(t, []string{"Variant A"}, suggestions.Variants)
+	})
+
+	t.Run("matches words after the first one", func(t *testing.T) {
+		service, _, _ := newTestService(t)
+
+		suggestions := service.Suggest("leath", 5)
+
+		assert.Equal(t, []string{"Premium Leather"}, suggestions.Variants)
+	})
+
+	t.Run("returns matches of every kind", func(t *testing.T) {
+		service, _, _ := newTestService(t)
+
+		suggestions := service.Suggest("sho", 5)
+
+		assert.Equal(t, []string{"SHOE01"}, suggestions.Products)
+		assert.Equal(t, []string{"Shoes"}, suggestions.Categories)
+	})
+
+	t.Run("respects limit per kind", func(t *testing.T) {
+		service, _, _ := newTestService(t)
+
+		suggestions := service.Suggest("prod", 1)
+
+		assert.Equal(t, []string{"PROD001"}, suggestions.Products)
+	})
+
+	t.Run("returns empty slices for blank prefix", func(t *testing.T) {
+		service, _, _ := newTestService(t)
+
+		suggestions := service.Suggest("  ", 5)
+
+		assert.NotNil(t, suggestions.Products)
+		assert.Empty(t, suggestions.Products)
+		assert.Empty(t, suggestions.Variants)
+		assert.Empty(t, suggestions.Categories)
+	})
+}
+
+func TestService_Refresh(t *testing.T) {
+	t.Run("picks up new data", func(t *testing.T) {
+		service, _, categories := newTestService(t)
+		categories.categories = append(categories.categories, product.Category{Code: "boots", Name: "Boots"})
+
+		require.NoError(t, service.Refresh(context.Background()))
+
+		assert.Equal(t, []string{"Boots"}, service.Suggest("boo", 5).Categories)
+	})
+
+	t.Run("keeps previous index when repository fails", func(t *testing.T) {
+		service, products, _ := newTestService(t)
+		products.err = errors.New("db error")
+
+		assert.Error(t, service.Refresh(context.Background()))
+		assert.Equal(t, []string{"PROD001", "PROD002"}, service.Suggest("prod", 5).Products)
+	})
+}
+
+func TestService_Run(t *testing.T) {
+	t.Run("rebuilds the index when invalidated", func(t *testing.T) {
+		service, _, categories := newTestService(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go service.Run(ctx, 0)
+
+		categories.categories = []product.Category{{Code: "kids", Name: "Kids"}}
+		service.Invalidate()
+
+		assert.Eventually(t, func() bool {
+			return len(service.Suggest("kid", 5).Categories) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+}
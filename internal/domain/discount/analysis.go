@@ -0,0 +1,229 @@
+package discount
+
+import (
+	"slices"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
+
+// RuleReport tells how many products a rule matches and how many it wins,
+// pricing the product or one of its variants for at least one pricing context.
+type RuleReport struct {
+	Rule    Rule
+	Matches int
+	Wins    int
+}
+
+// Shadow is a rule that matches products but never prices any of them or
+// their variants, because an earlier rule of a covering audience always matches too.
+type Shadow struct {
+	Rule Rule
+	By   []Rule
+}
+
+// ProductRules lists the rules matching a product, in evaluation order.
+// PriceChanges is set for order-sensitive products when reordering the
+// rules would also change the percentage given.
+type ProductRules struct {
+	ProductCode  string
+	Rules        []Rule
+	PriceChanges bool
+}
+
+// Analysis describes how a set of rules applies to a catalog. Rules win a
+// product when they price it or one of its variants the way Engine does:
+// first match in order, with variants preferring their SKU rules. Overlaps
+// are products matched by more than one rule. OrderSensitive are products
+// where rules of intersecting audiences compete for the product price, so
+// that some pricing context gets another rule when the rules are reordered.
+// Guardrails are not taken into account.
+type Analysis struct {
+	Products       int
+	Rules          []RuleReport
+	Unmatched      []Rule
+	Shadowed       []Shadow
+	Overlaps       []ProductRules
+	OrderSensitive []ProductRules
+}
+
+// Analyzer runs rules against products one at a time, so that a catalog
+// can be analyzed without holding it in memory.
+type Analyzer struct {
+	rules      []Rule
+	strategies []Strategy
+	reports    []RuleReport
+	shadowers  [][]int
+	analysis   Analysis
+}
+
+// NewAnalyzer creates an analyzer for rules sorted by position.
+func NewAnalyzer(rules []Rule) (*Analyzer, error) {
+	a := &Analyzer{
+		rules:      rules,
+		strategies: make([]Strategy, len(rules)),
+		reports:    make([]RuleReport, len(rules)),
+		shadowers:  make([][]int, len(rules)),
+		analysis:   Analysis{Overlaps: []ProductRules{}, OrderSensitive: []ProductRules{}},
+	}
+	for i, rule := range rules {
+		strategy, err := rule.Strategy()
+		if err != nil {
+			return nil, err
+		}
+		a.strategies[i] = strategy
+		a.reports[i].Rule = rule
+	}
+	return a, nil
+}
+
+// Add runs every rule against a product, which needs its category and variants.
+func (a *Analyzer) Add(p product.Product) {
+	a.analysis.Products++
+
+	var matching []int
+	for i, strategy := range a.strategies {
+		if strategy.AppliesTo(p) {
+			matching = append(matching, i)
+		}
+	}
+
+	won := make([]bool, len(a.strategies))
+	a.markWinners(matching, won)
+	for _, v := range p.Variants {
+		// Variants prefer SKU rules, then fall back to the product rules.
+		var variantMatching []int
+		for i, strategy := range a.strategies {
+			if sku, ok := strategy.(*SKUDiscountStrategy); ok && sku.AppliesToVariant(v.SKU) {
+				variantMatching = append(variantMatching, i)
+			}
+		}
+		for _, i := range matching {
+			switch a.strategies[i].(type) {
+			case *CategoryDiscountStrategy, *ExpressionDiscountStrategy:
+				variantMatching = append(variantMatching, i)
+			}
+		}
+		a.markWinners(variantMatching, won)
+	}
+	for _, i := range matching {
+		a.reports[i].Matches++
+	}
+	for i := range won {
+		if won[i] {
+			a.reports[i].Wins++
+		}
+	}
+
+	if len(matching) < 2 {
+		return
+	}
+	a.analysis.Overlaps = append(a.analysis.Overlaps, ProductRules{ProductCode: p.Code, Rules: a.rulesAt(matching)})
+
+	competing := make([]bool, len(matching))
+	priceChanges := false
+	for n, i := range matching {
+		for m := n + 1; m < len(matching); m++ {
+			j := matching[m]
+			if intersect(a.rules[i].Audience, a.rules[j].Audience) {
+				competing[n], competing[m] = true, true
+				priceChanges = priceChanges || a.strategies[i].CalculatePercentage(p) != a.strategies[j].CalculatePercentage(p)
+			}
+		}
+	}
+	var indexes []int
+	for n, i := range matching {
+		if competing[n] {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) > 0 {
+		a.analysis.OrderSensitive = append(a.analysis.OrderSensitive, ProductRules{ProductCode: p.Code, Rules: a.rulesAt(indexes), PriceChanges: priceChanges})
+	}
+}
+
+// markWinners marks the rules at the indexes, in evaluation order, that win
+// for at least one pricing context, and records the earlier rules covering
+// the audience of the others.
+func (a *Analyzer) markWinners(indexes []int, won []bool) {
+	for n, i := range indexes {
+		covered := false
+		for _, earlier := range indexes[:n] {
+			if covers(a.rules[earlier].Audience, a.rules[i].Audience) {
+				covered = true
+				if !slices.Contains(a.shadowers[i], earlier) {
+					a.shadowers[i] = append(a.shadowers[i], earlier)
+				}
+				break
+			}
+		}
+		if !covered {
+			won[i] = true
+		}
+	}
+}
+
+// Analysis returns the analysis of the products added so far.
+func (a *Analyzer) Analysis() Analysis {
+	analysis := a.analysis
+	analysis.Rules = slices.Clone(a.reports)
+	analysis.Unmatched = []Rule{}
+	analysis.Shadowed = []Shadow{}
+	for i, report := range a.reports {
+		switch {
+		case report.Matches == 0:
+			analysis.Unmatched = append(analysis.Unmatched, report.Rule)
+		case report.Wins == 0:
+			analysis.Shadowed = append(analysis.Shadowed, Shadow{Rule: report.Rule, By: a.rulesAt(slices.Sorted(slices.Values(a.shadowers[i])))})
+		}
+	}
+	return analysis
+}
+
+func (a *Analyzer) rulesAt(indexes []int) []Rule {
+	rules := make([]Rule, len(indexes))
+	for n, i := range indexes {
+		rules[n] = a.rules[i]
+	}
+	return rules
+}
+
+// covers reports whether every pricing context of b is part of a.
+func covers(a, b Audience) bool {
+	return coversValues(a.Segments, b.Segments) &&
+		coversValues(a.Markets, b.Markets) &&
+		coversValues(a.Channels, b.Channels)
+}
+
+func coversValues(a, b []string) bool {
+	if len(a) == 0 {
+		return true
+	}
+	if len(b) == 0 {
+		return false
+	}
+	for _, v := range b {
+		if !slices.Contains(a, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// intersect reports whether some pricing context is part of both a and b.
+func intersect(a, b Audience) bool {
+	return intersectValues(a.Segments, b.Segments) &&
+		intersectValues(a.Markets, b.Markets) &&
+		intersectValues(a.Channels, b.Channels)
+}
+
+func intersectValues(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, v := range b {
+		if slices.Contains(a, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package discount
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer(t *testing.T) {
+	products := []product.Product{
+		{Code: "PROD001", Price: decimal.NewFromInt(10), Category: &product.Category{Code: "clothing"}},
+		{Code: "PROD009", Price: decimal.NewFromInt(90), Category: &product.Category{Code: "boots"}, Variants: []product.Variant{{SKU: "000003"}}},
+		{Code: "PROD010", Price: decimal.NewFromInt(120), Category: &product.Category{Code: "boots"}},
+	}
+	analyze := func(t *testing.T, rules []Rule) Analysis {
+		analyzer, err := NewAnalyzer(rules)
+		require.NoError(t, err)
+		for _, p := range products {
+			analyzer.Add(p)
+		}
+		return analyzer.Analysis()
+	}
+
+	boots := Rule{Kind: RuleCategory, Target: "boots", Percentage: 30, Position: 1}
+	sku := Rule{Kind: RuleSKU, Target: "000003", Percentage: 15, Position: 2}
+	vipClothing := Rule{Kind: RuleCategory, Target: "clothing", Percentage: 20, Audience: Audience{Segments: []string{"vip"}}, Position: 3}
+	vipBoots := Rule{Kind: RuleCategory, Target: "boots", Percentage: 40, Audience: Audience{Segments: []string{"vip"}, Markets: []string{"de"}}, Position: 4}
+	premium := Rule{Kind: RuleExpression, Target: "premium", Expression: `price > 100`, Percentage: 30, Position: 5}
+	shoes := Rule{Kind: RuleCategory, Target: "shoes", Percentage: 10, Position: 6}
+
+	t.Run("counts the matches and wins of each rule", func(t *testing.T) {
+		analysis := analyze(t, []Rule{boots, sku, vipClothing, vipBoots, premium, shoes})
+
+		assert.Equal(t, 3, analysis.Products)
+		assert.Equal(t, []RuleReport{
+			{Rule: boots, Matches: 2, Wins: 2},
+			{Rule: sku, Matches: 1, Wins: 1},
+			{Rule: vipClothing, Matches: 1, Wins: 1},
+			{Rule: vipBoots, Matches: 2, Wins: 0},
+			{Rule: premium, Matches: 1, Wins: 0},
+			{Rule: shoes, Matches: 0, Wins: 0},
+		}, analysis.Rules)
+	})
+
+	t.Run("reports rules matching no product", func(t *testing.T) {
+		analysis := analyze(t, []Rule{boots, shoes})
+
+		assert.Equal(t, []Rule{shoes}, analysis.Unmatched)
+		assert.Empty(t, analysis.Shadowed)
+	})
+
+	t.Run("reports rules shadowed by earlier rules of covering audiences", func(t *testing.T) {
+		analysis := analyze(t, []Rule{boots, sku, vipBoots, premium})
+
+		assert.Equal(t, []Shadow{
+			{Rule: vipBoots, By: []Rule{boots, sku}},
+			{Rule: premium, By: []Rule{boots}},
+		}, analysis.Shadowed)
+	})
+
+	t.Run("lets SKU rules win their variants after category rules", func(t *testing.T) {
+		analysis := analyze(t, []Rule{boots, sku})
+
+		assert.Empty(t, analysis.Shadowed)
+		assert.Equal(t, 1, analysis.Rules[1].Wins)
+	})
+
+	t.Run("does not shadow a rule behind a narrower audience", func(t *testing.T) {
+		analysis := analyze(t, []Rule{vipBoots, boots})
+
+		assert.Empty(t, analysis.Shadowed)
+		assert.Equal(t, 2, analysis.Rules[1].Wins)
+	})
+
+	t.Run("reports overlaps and order-sensitive products", func(t *testing.T) {
+		employeeBoots := Rule{Kind: RuleCategory, Target: "boots", Percentage: 30, Audience: Audience{Segments: []string{"employee"}}, Position: 2}
+		analysis := analyze(t, []Rule{vipBoots, employeeBoots, premium})
+
+		assert.Equal(t, []ProductRules{
+			{ProductCode: "PROD009", Rules: []Rule{vipBoots, employeeBoots}},
+			{ProductCode: "PROD010", Rules: []Rule{vipBoots, employeeBoots, premium}},
+		}, analysis.Overlaps)
+		assert.Equal(t, []ProductRules{
+			{ProductCode: "PROD010", Rules: []Rule{vipBoots, employeeBoots, premium}, PriceChanges: true},
+		}, analysis.OrderSensitive, "vip and employee audiences never compete")
+	})
+
+	t.Run("tells when reordering keeps the price", func(t *testing.T) {
+		analysis := analyze(t, []Rule{boots, premium})
+
+		assert.Equal(t, []ProductRules{{ProductCode: "PROD010", Rules: []Rule{boots, premium}}}, analysis.OrderSensitive)
+	})
+
+	t.Run("fails on an invalid rule", func(t *testing.T) {
+		_, err := NewAnalyzer([]Rule{{Kind: RuleExpression, Target: "premium", Expression: "price >", Percentage: 10}})
+
+		assert.ErrorIs(t, err, ErrInvalidRule)
+	})
+}
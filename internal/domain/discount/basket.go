@@ -0,0 +1,333 @@
+package discount
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// BasketRuleKind selects the basket strategy of a rule.
+type BasketRuleKind string
+
+const (
+	// BasketBundle frees the Free cheapest units of every Quantity matching units.
+	BasketBundle BasketRuleKind = "bundle"
+	// BasketThreshold takes Percentage off the matching lines when they add up to at least MinSpend.
+	BasketThreshold BasketRuleKind = "threshold"
+	// BasketMix takes Percentage off the lines of Categories when every one of them is in the basket.
+	BasketMix BasketRuleKind = "mix"
+)
+
+// Precedence tells how a basket rule combines with item-level discounts.
+// Item-level discounts are always applied first.
+type Precedence string
+
+const (
+	// PrecedenceAfterItems applies the rule to line amounts after item discounts, stacking with them.
+	PrecedenceAfterItems Precedence = "after_items"
+	// PrecedenceExcludeDiscounted ignores lines that already have an item discount.
+	PrecedenceExcludeDiscounted Precedence = "exclude_discounted"
+)
+
+// BasketRule is a stored basket-level promotion. Rules are evaluated by
+// Position after item discounts, each on the amounts left by the ones before
+// it; once a rule with Stop applies, later rules are skipped. A CouponOnly
+// rule is only evaluated when a coupon linked to it is supplied.
+type BasketRule struct {
+	Name       string
+	Kind       BasketRuleKind
+	Categories []string
+	Quantity   int
+	Free       int
+	MinSpend   decimal.Decimal
+	Percentage int
+	Precedence Precedence
+	Stop       bool
+	CouponOnly bool
+	Position   int
+}
+
+// Validate checks that the rule has the settings its kind needs.
+func (r BasketRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: basket rule without name", ErrInvalidRule)
+	}
+	if r.Precedence != PrecedenceAfterItems && r.Precedence != PrecedenceExcludeDiscounted {
+		return fmt.Errorf("%w: basket rule %s has unknown precedence %q", ErrInvalidRule, r.Name, r.Precedence)
+	}
+
+	switch r.Kind {
+	case BasketBundle:
+		if r.Free <= 0 || r.Quantity <= r.Free {
+			return fmt.Errorf("%w: bundle %s needs 0 < free < quantity", ErrInvalidRule, r.Name)
+		}
+	case BasketThreshold:
+		if !r.MinSpend.IsPositive() {
+			return fmt.Errorf("%w: threshold %s needs a positive minimum spend", ErrInvalidRule, r.Name)
+		}
+		if err := r.validatePercentage(); err != nil {
+			return err
+		}
+	case BasketMix:
+		if len(r.Categories) < 2 {
+			return fmt.Errorf("%w: mix %s needs at least two categories", ErrInvalidRule, r.Name)
+		}
+		if err := r.validatePercentage(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: basket rule %s has unknown kind %q", ErrInvalidRule, r.Name, r.Kind)
+	}
+	return nil
+}
+
+func (r BasketRule) validatePercentage() error {
+	if r.Percentage <= 0 || r.Percentage > 100 {
+		return fmt.Errorf("%w: percentage %d of %s is outside 1-100", ErrInvalidRule, r.Percentage, r.Name)
+	}
+	return nil
+}
+
+// Strategy builds the basket strategy of the rule.
+func (r BasketRule) Strategy() (BasketStrategy, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	switch r.Kind {
+	case BasketBundle:
+		return &BundleStrategy{categories: r.Categories, quantity: r.Quantity, free: r.Free}, nil
+	case BasketThreshold:
+		return &ThresholdStrategy{categories: r.Categories, minSpend: r.MinSpend, percentage: r.Percentage}, nil
+	default:
+		return &MixStrategy{categories: r.Categories, percentage: r.Percentage}, nil
+	}
+}
+
+// BasketLine is one line of a basket as seen by basket strategies.
+type BasketLine struct {
+	SKU      string
+	Category string
+	Quantity int
+	// Total is the amount of the line left by the discounts applied before.
+	Total decimal.Decimal
+	// ItemDiscounted reports whether an item-level discount applied to the line.
+	ItemDiscounted bool
+	// Floor is the lowest amount promotions may take the line to, zero for none.
+	Floor decimal.Decimal
+}
+
+// UnitPrice returns the amount left per unit of the line.
+func (l BasketLine) UnitPrice() decimal.Decimal {
+	return l.Total.Div(decimal.NewFromInt(int64(l.Quantity))).Round(2)
+}
+
+// BasketStrategy evaluates a promotion over a whole basket.
+type BasketStrategy interface {
+	// Apply returns the amount to take off each line, in line order, or nil
+	// when the promotion does not apply.
+	Apply(lines []BasketLine) []decimal.Decimal
+}
+
+// BundleStrategy frees the cheapest units of every group of matching units.
+// Units are grouped from the most to the least expensive, so "buy 2, get the
+// cheapest free" over units of 50, 30, 20 and 10 frees the 30 and the 10.
+type BundleStrategy struct {
+	categories []string
+	quantity   int
+	free       int
+}
+
+func (s *BundleStrategy) Apply(lines []BasketLine) []decimal.Decimal {
+	type unit struct {
+		line  int
+		price decimal.Decimal
+	}
+	var units []unit
+	for i, l := range lines {
+		if !inCategories(l.Category, s.categories) {
+			continue
+		}
+		for n := 0; n < l.Quantity; n++ {
+			units = append(units, unit{line: i, price: l.UnitPrice()})
+		}
+	}
+	if len(units) < s.quantity {
+		return nil
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].price.GreaterThan(units[j].price)
+	})
+
+	amounts := zeros(len(lines))
+	for start := 0; start+s.quantity <= len(units); start += s.quantity {
+		for _, u := range units[start+s.quantity-s.free : start+s.quantity] {
+			amounts[u.line] = amounts[u.line].Add(u.price)
+		}
+	}
+	return amounts
+}
+
+// ThresholdStrategy takes a percentage off the matching lines when they add up
+// to at least a minimum spend.
+type ThresholdStrategy struct {
+	categories []string
+	minSpend   decimal.Decimal
+	percentage int
+}
+
+func (s *ThresholdStrategy) Apply(lines []BasketLine) []decimal.Decimal {
+	spend := decimal.Zero
+	for _, l := range lines {
+		if inCategories(l.Category, s.categories) {
+			spend = spend.Add(l.Total)
+		}
+	}
+	if spend.LessThan(s.minSpend) {
+		return nil
+	}
+	return percentageOff(lines, s.categories, s.percentage)
+}
+
+// MixStrategy takes a percentage off the lines of its categories when the
+// basket holds at least one line of each of them.
+type MixStrategy struct {
+	categories []string
+	percentage int
+}
+
+func (s *MixStrategy) Apply(lines []BasketLine) []decimal.Decimal {
+	for _, category := range s.categories {
+		found := false
+		for _, l := range lines {
+			if l.Category == category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return percentageOff(lines, s.categories, s.percentage)
+}
+
+// AppliedPromotion is a basket rule that took an amount off a basket.
+type AppliedPromotion struct {
+	Name    string
+	Kind    BasketRuleKind
+	Amounts []decimal.Decimal
+	// Requested is what the rule asked to take off each line, above the
+	// amount taken when the line would have gone below its floor or zero.
+	Requested []decimal.Decimal
+}
+
+// Amount returns the total taken off by the promotion.
+func (p AppliedPromotion) Amount() decimal.Decimal {
+	total := decimal.Zero
+	for _, a := range p.Amounts {
+		total = total.Add(a)
+	}
+	return total
+}
+
+// BasketEngine applies basket rules in order of position.
+type BasketEngine struct {
+	rules      []BasketRule
+	strategies []BasketStrategy
+}
+
+// NewBasketEngine builds an engine from stored rules, which must be in
+// evaluation order.
+func NewBasketEngine(rules []BasketRule) (*BasketEngine, error) {
+	strategies := make([]BasketStrategy, len(rules))
+	for i, rule := range rules {
+		strategy, err := rule.Strategy()
+		if err != nil {
+			return nil, err
+		}
+		strategies[i] = strategy
+	}
+	return &BasketEngine{rules: rules, strategies: strategies}, nil
+}
+
+// Apply evaluates the rules over the lines and returns the promotions that
+// took an amount off, with the amount taken off each line. Coupon-only rules
+// are evaluated when their name is in unlocked. Lines are never taken below
+// their floor, nor below zero.
+func (e *BasketEngine) Apply(lines []BasketLine, unlocked []string) []AppliedPromotion {
+	remaining := append([]BasketLine(nil), lines...)
+	var applied []AppliedPromotion
+
+	for i, rule := range e.rules {
+		if rule.CouponOnly && !slices.Contains(unlocked, rule.Name) {
+			continue
+		}
+		eligible := make([]int, 0, len(remaining))
+		for j, l := range remaining {
+			if rule.Precedence == PrecedenceExcludeDiscounted && l.ItemDiscounted {
+				continue
+			}
+			eligible = append(eligible, j)
+		}
+		view := make([]BasketLine, len(eligible))
+		for k, j := range eligible {
+			view[k] = remaining[j]
+		}
+
+		amounts := e.strategies[i].Apply(view)
+		if amounts == nil {
+			continue
+		}
+
+		promotion := AppliedPromotion{Name: rule.Name, Kind: rule.Kind, Amounts: zeros(len(lines)), Requested: zeros(len(lines))}
+		for k, j := range eligible {
+			left := decimal.Max(remaining[j].Total.Sub(remaining[j].Floor), decimal.Zero)
+			amount := decimal.Min(amounts[k], left)
+			promotion.Amounts[j] = amount
+			promotion.Requested[j] = amounts[k]
+			remaining[j].Total = remaining[j].Total.Sub(amount)
+		}
+		if !promotion.Amount().IsPositive() {
+			continue
+		}
+		applied = append(applied, promotion)
+		if rule.Stop {
+			break
+		}
+	}
+	return applied
+}
+
+// percentageOff takes a percentage off the total of every line in the categories.
+func percentageOff(lines []BasketLine, categories []string, percentage int) []decimal.Decimal {
+	amounts := zeros(len(lines))
+	for i, l := range lines {
+		if inCategories(l.Category, categories) {
+			amounts[i] = l.Total.Mul(decimal.NewFromInt(int64(percentage))).Div(decimal.NewFromInt(100)).Round(2)
+		}
+	}
+	return amounts
+}
+
+// inCategories reports whether category is one of categories. No categories matches every line.
+func inCategories(category string, categories []string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func zeros(n int) []decimal.Decimal {
+	amounts := make([]decimal.Decimal, n)
+	for i := range amounts {
+		amounts[i] = decimal.Zero
+	}
+	return amounts
+}
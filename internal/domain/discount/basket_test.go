@@ -0,0 +1,186 @@
+package discount
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func line(sku, category string, quantity int, total string, itemDiscounted bool) BasketLine {
+	return BasketLine{SKU: sku, Category: category, Quantity: quantity, Total: decimal.RequireFromString(total), ItemDiscounted: itemDiscounted}
+}
+
+func amounts(values []decimal.Decimal) []string {
+	if values == nil {
+		return nil
+	}
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.StringFixed(2)
+	}
+	return result
+}
+
+func TestBasketRule_Validate(t *testing.T) {
+	t.Run("accepts complete rules", func(t *testing.T) {
+		rules := []BasketRule{
+			{Name: "b2g1", Kind: BasketBundle, Quantity: 2, Free: 1, Precedence: PrecedenceAfterItems},
+			{Name: "spend200", Kind: BasketThreshold, MinSpend: decimal.NewFromInt(200), Percentage: 10, Precedence: PrecedenceAfterItems},
+			{Name: "outfit", Kind: BasketMix, Categories: []string{"shoes", "clothing"}, Percentage: 5, Precedence: PrecedenceExcludeDiscounted},
+		}
+		for _, rule := range rules {
+			assert.NoError(t, rule.Validate(), rule.Name)
+		}
+	})
+
+	t.Run("rejects incomplete rules", func(t *testing.T) {
+		rules := map[string]BasketRule{
+			"missing name":       {Kind: BasketBundle, Quantity: 2, Free: 1, Precedence: PrecedenceAfterItems},
+			"unknown kind":       {Name: "x", Kind: "gift", Precedence: PrecedenceAfterItems},
+			"unknown precedence": {Name: "x", Kind: BasketBundle, Quantity: 2, Free: 1, Precedence: "first"},
+			"bundle all free":    {Name: "x", Kind: BasketBundle, Quantity: 2, Free: 2, Precedence: PrecedenceAfterItems},
+			"threshold no spend": {Name: "x", Kind: BasketThreshold, Percentage: 10, Precedence: PrecedenceAfterItems},
+			"threshold zero":     {Name: "x", Kind: BasketThreshold, MinSpend: decimal.NewFromInt(200), Precedence: PrecedenceAfterItems},
+			"mix one category":   {Name: "x", Kind: BasketMix, Categories: []string{"shoes"}, Percentage: 5, Precedence: PrecedenceAfterItems},
+		}
+		for name, rule := range rules {
+			assert.ErrorIs(t, rule.Validate(), ErrInvalidRule, name)
+		}
+	})
+}
+
+func TestBundleStrategy(t *testing.T) {
+	strategy, err := BasketRule{Name: "b2g1", Kind: BasketBundle, Categories: []string{"accessories"}, Quantity: 2, Free: 1, Precedence: PrecedenceAfterItems}.Strategy()
+	require.NoError(t, err)
+
+	t.Run("frees the cheapest unit of every pair", func(t *testing.T) {
+		lines := []BasketLine{
+			line("BELT", "accessories", 2, "60", false),
+			line("HAT", "accessories", 1, "20", false),
+			line("BOOT", "shoes", 1, "100", false),
+			line("SCARF", "accessories", 1, "10", false),
+		}
+
+		assert.Equal(t, []string{"30.00", "0.00", "0.00", "10.00"}, amounts(strategy.Apply(lines)))
+	})
+
+	t.Run("does not apply below the bundle quantity", func(t *testing.T) {
+		assert.Nil(t, strategy.Apply([]BasketLine{line("HAT", "accessories", 1, "20", false), line("BOOT", "shoes", 3, "300", false)}))
+	})
+}
+
+func TestThresholdStrategy(t *testing.T) {
+	strategy, err := BasketRule{Name: "spend200", Kind: BasketThreshold, MinSpend: decimal.NewFromInt(200), Percentage: 10, Precedence: PrecedenceAfterItems}.Strategy()
+	require.NoError(t, err)
+
+	t.Run("takes a percentage off every line from the minimum spend", func(t *testing.T) {
+		lines := []BasketLine{line("BOOT", "shoes", 1, "150", false), line("HAT", "accessories", 1, "50.05", false)}
+
+		assert.Equal(t, []string{"15.00", "5.01"}, amounts(strategy.Apply(lines)))
+	})
+
+	t.Run("does not apply below the minimum spend", func(t *testing.T) {
+		assert.Nil(t, strategy.Apply([]BasketLine{line("BOOT", "shoes", 1, "199.99", false)}))
+	})
+}
+
+func TestMixStrategy(t *testing.T) {
+	strategy, err := BasketRule{Name: "outfit", Kind: BasketMix, Categories: []string{"shoes", "clothing"}, Percentage: 10, Precedence: PrecedenceAfterItems}.Strategy()
+	require.NoError(t, err)
+
+	t.Run("discounts the lines of the mix when every category is present", func(t *testing.T) {
+		lines := []BasketLine{line("BOOT", "shoes", 1, "100", false), line("SHIRT", "clothing", 1, "40", false), line("HAT", "accessories", 1, "20", false)}
+
+		assert.Equal(t, []string{"10.00", "4.00", "0.00"}, amounts(strategy.Apply(lines)))
+	})
+
+	t.Run("does not apply when a category is missing", func(t *testing.T) {
+		assert.Nil(t, strategy.Apply([]BasketLine{line("BOOT", "shoes", 2, "200", false)}))
+	})
+}
+
+func TestBasketEngine_Apply(t *testing.T) {
+	lines := []BasketLine{
+		line("BOOT", "shoes", 1, "140", true),
+		line("BELT", "accessories", 2, "60", false),
+		line("HAT", "accessories", 1, "20", false),
+	}
+
+	t.Run("applies rules in order on the amounts left by earlier ones", func(t *testing.T) {
+		engine, err := NewBasketEngine([]BasketRule{
+			{Name: "b2g1", Kind: BasketBundle, Categories: []string{"accessories"}, Quantity: 2, Free: 1, Precedence: PrecedenceAfterItems},
+			{Name: "spend200", Kind: BasketThreshold, MinSpend: decimal.NewFromInt(150), Percentage: 10, Precedence: PrecedenceAfterItems},
+		})
+		require.NoError(t, err)
+
+		applied := engine.Apply(lines, nil)
+
+		require.Len(t, applied, 2)
+		assert.Equal(t, "b2g1", applied[0].Name)
+		assert.Equal(t, []string{"0.00", "30.00", "0.00"}, amounts(applied[0].Amounts))
+		assert.Equal(t, "spend200", applied[1].Name)
+		assert.Equal(t, []string{"14.00", "3.00", "2.00"}, amounts(applied[1].Amounts))
+		assert.Equal(t, "19.00", applied[1].Amount().StringFixed(2))
+	})
+
+	t.Run("never takes a line below its floor", func(t *testing.T) {
+		floored := append([]BasketLine(nil), lines...)
+		floored[1].Floor = decimal.NewFromInt(50)
+		floored[2].Floor = decimal.NewFromInt(25)
+		engine, err := NewBasketEngine([]BasketRule{
+			{Name: "spend", Kind: BasketThreshold, MinSpend: decimal.NewFromInt(1), Percentage: 50, Precedence: PrecedenceAfterItems},
+		})
+		require.NoError(t, err)
+
+		applied := engine.Apply(floored, nil)
+
+		require.Len(t, applied, 1)
+		assert.Equal(t, []string{"70.00", "10.00", "0.00"}, amounts(applied[0].Amounts))
+		assert.Equal(t, []string{"70.00", "30.00", "10.00"}, amounts(applied[0].Requested))
+	})
+
+	t.Run("skips item-discounted lines when asked to", func(t *testing.T) {
+		engine, err := NewBasketEngine([]BasketRule{
+			{Name: "spend", Kind: BasketThreshold, MinSpend: decimal.NewFromInt(100), Percentage: 10, Precedence: PrecedenceExcludeDiscounted},
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, engine.Apply(lines, nil))
+	})
+
+	t.Run("stops after a rule with stop applies", func(t *testing.T) {
+		engine, err := NewBasketEngine([]BasketRule{
+			{Name: "never", Kind: BasketMix, Categories: []string{"shoes", "clothing"}, Percentage: 50, Precedence: PrecedenceAfterItems, Stop: true},
+			{Name: "b2g1", Kind: BasketBundle, Quantity: 2, Free: 1, Precedence: PrecedenceAfterItems, Stop: true},
+			{Name: "spend", Kind: BasketThreshold, MinSpend: decimal.NewFromInt(1), Percentage: 10, Precedence: PrecedenceAfterItems},
+		})
+		require.NoError(t, err)
+
+		applied := engine.Apply(lines, nil)
+
+		require.Len(t, applied, 1)
+		assert.Equal(t, "b2g1", applied[0].Name)
+	})
+
+	t.Run("applies coupon-only rules when unlocked", func(t *testing.T) {
+		engine, err := NewBasketEngine([]BasketRule{
+			{Name: "welcome", Kind: BasketThreshold, MinSpend: decimal.NewFromInt(1), Percentage: 10, Precedence: PrecedenceAfterItems, CouponOnly: true},
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, engine.Apply(lines, nil))
+		assert.Empty(t, engine.Apply(lines, []string{"other"}))
+
+		applied := engine.Apply(lines, []string{"welcome"})
+		require.Len(t, applied, 1)
+		assert.Equal(t, "22.00", applied[0].Amount().StringFixed(2))
+	})
+
+	t.Run("fails on an invalid rule", func(t *testing.T) {
+		_, err := NewBasketEngine([]BasketRule{{Name: "x", Kind: "gift"}})
+
+		assert.ErrorIs(t, err, ErrInvalidRule)
+	})
+}
@@ -0,0 +1,90 @@
+package discount
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Coupon errors.
+var (
+	ErrInvalidCoupon   = errors.New("invalid coupon")
+	ErrCouponNotFound  = errors.New("coupon not found")
+	ErrCouponInactive  = errors.New("coupon is not active")
+	ErrCouponExhausted = errors.New("coupon redemption limit reached")
+
+	ErrInvalidRedemption = errors.New("invalid redemption")
+)
+
+// NormalizeCode returns the stored form of a coupon code, so that codes match
+// regardless of case and surrounding spaces.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Coupon is a code that unlocks a coupon-only basket rule. It is valid from
+// StartsAt until EndsAt, either of which may be zero for an open window.
+// MaxRedemptions and MaxPerCustomer bound how often it can be redeemed in
+// total and by one customer; zero means unlimited.
+type Coupon struct {
+	Code           string
+	Rule           string
+	StartsAt       time.Time
+	EndsAt         time.Time
+	MaxRedemptions int
+	MaxPerCustomer int
+}
+
+// Usage counts the redemptions of a coupon, in total and by one customer.
+type Usage struct {
+	Total    int
+	Customer int
+}
+
+// Redemption is one use of a coupon by a customer.
+type Redemption struct {
+	Code       string
+	CustomerID string
+	RedeemedAt time.Time
+}
+
+// Validate checks that the coupon names a rule and has a consistent window and limits.
+func (c Coupon) Validate() error {
+	if c.Code == "" {
+		return fmt.Errorf("%w: coupon without code", ErrInvalidCoupon)
+	}
+	if c.Rule == "" {
+		return fmt.Errorf("%w: coupon %s needs a basket rule", ErrInvalidCoupon, c.Code)
+	}
+	if !c.StartsAt.IsZero() && !c.EndsAt.IsZero() && !c.StartsAt.Before(c.EndsAt) {
+		return fmt.Errorf("%w: coupon %s ends before it starts", ErrInvalidCoupon, c.Code)
+	}
+	if c.MaxRedemptions < 0 || c.MaxPerCustomer < 0 {
+		return fmt.Errorf("%w: coupon %s has a negative limit", ErrInvalidCoupon, c.Code)
+	}
+	return nil
+}
+
+// Active reports whether now is inside the validity window of the coupon.
+func (c Coupon) Active(now time.Time) bool {
+	if !c.StartsAt.IsZero() && now.Before(c.StartsAt) {
+		return false
+	}
+	return c.EndsAt.IsZero() || now.Before(c.EndsAt)
+}
+
+// Check returns an error when the coupon cannot be redeemed once more at now,
+// given its usage so far.
+func (c Coupon) Check(now time.Time, usage Usage) error {
+	if !c.Active(now) {
+		return fmt.Errorf("%w: %s", ErrCouponInactive, c.Code)
+	}
+	if c.MaxRedemptions > 0 && usage.Total >= c.MaxRedemptions {
+		return fmt.Errorf("%w: %s has been redeemed %d times", ErrCouponExhausted, c.Code, usage.Total)
+	}
+	if c.MaxPerCustomer > 0 && usage.Customer >= c.MaxPerCustomer {
+		return fmt.Errorf("%w: %s has been redeemed %d times by this customer", ErrCouponExhausted, c.Code, usage.Customer)
+	}
+	return nil
+}
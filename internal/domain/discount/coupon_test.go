@@ -0,0 +1,54 @@
+package discount
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoupon_Validate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("accepts open and bounded coupons", func(t *testing.T) {
+		assert.NoError(t, Coupon{Code: "WELCOME", Rule: "welcome"}.Validate())
+		assert.NoError(t, Coupon{Code: "SPRING", Rule: "spring", StartsAt: start, EndsAt: start.AddDate(0, 1, 0), MaxRedemptions: 100, MaxPerCustomer: 1}.Validate())
+	})
+
+	t.Run("rejects incomplete coupons", func(t *testing.T) {
+		coupons := map[string]Coupon{
+			"missing code":      {Rule: "welcome"},
+			"missing rule":      {Code: "WELCOME"},
+			"ends before start": {Code: "SPRING", Rule: "spring", StartsAt: start, EndsAt: start},
+			"negative limit":    {Code: "WELCOME", Rule: "welcome", MaxPerCustomer: -1},
+		}
+		for name, coupon := range coupons {
+			assert.ErrorIs(t, coupon.Validate(), ErrInvalidCoupon, name)
+		}
+	})
+}
+
+func TestCoupon_Check(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	coupon := Coupon{Code: "SPRING", Rule: "spring", StartsAt: start, EndsAt: start.AddDate(0, 1, 0), MaxRedemptions: 10, MaxPerCustomer: 2}
+
+	t.Run("accepts redemptions inside the window and limits", func(t *testing.T) {
+		assert.NoError(t, coupon.Check(start, Usage{Total: 9, Customer: 1}))
+	})
+
+	t.Run("rejects redemptions outside the window", func(t *testing.T) {
+		assert.ErrorIs(t, coupon.Check(start.Add(-time.Second), Usage{}), ErrCouponInactive)
+		assert.ErrorIs(t, coupon.Check(coupon.EndsAt, Usage{}), ErrCouponInactive)
+	})
+
+	t.Run("rejects redemptions over the limits", func(t *testing.T) {
+		assert.ErrorIs(t, coupon.Check(start, Usage{Total: 10}), ErrCouponExhausted)
+		assert.ErrorIs(t, coupon.Check(start, Usage{Total: 3, Customer: 2}), ErrCouponExhausted)
+	})
+
+	t.Run("treats zero limits and window bounds as unlimited", func(t *testing.T) {
+		open := Coupon{Code: "WELCOME", Rule: "welcome"}
+
+		assert.NoError(t, open.Check(start.AddDate(10, 0, 0), Usage{Total: 1000, Customer: 1000}))
+	})
+}
@@ -0,0 +1,494 @@
+package discount
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidExpression is returned for conditions that cannot be parsed.
+var ErrInvalidExpression = errors.New("invalid expression")
+
+const (
+	// maxExpressionLength bounds the source of a condition.
+	maxExpressionLength = 1000
+	// maxExpressionDepth bounds the nesting of not and parentheses.
+	maxExpressionDepth = 32
+)
+
+// ExpressionError locates a parse error in the source of a condition.
+// Column counts characters from 1. It matches ErrInvalidExpression.
+type ExpressionError struct {
+	Column  int
+	Message string
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+func (e *ExpressionError) Is(target error) bool {
+	return target == ErrInvalidExpression
+}
+
+// fieldType is the type of the values of a field.
+type fieldType int
+
+const (
+	textField fieldType = iota
+	numberField
+)
+
+// expressionFields are the product fields a condition can test: the category
+// code (empty without a category), the product code and the product price.
+var expressionFields = map[string]fieldType{
+	"category": textField,
+	"code":     textField,
+	"price":    numberField,
+}
+
+// fieldNames lists the fields for error messages.
+const fieldNames = "category, code or price"
+
+// Expression is a parsed condition over a product, such as
+//
+//	category in ["shoes", "boots"] and price > 100 and not code startsWith "PROD00"
+//
+// Conditions compare fields with ==, !=, <, <=, >, >= (numbers only), in a
+// list, startsWith, endsWith and contains (text only), combined with and, or,
+// not and parentheses. Fields are type-checked when the condition is parsed,
+// so evaluating it cannot fail.
+type Expression struct {
+	source string
+	root   expressionNode
+}
+
+// ParseExpression parses and type-checks a condition. Errors are *ExpressionError.
+func ParseExpression(source string) (*Expression, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, &ExpressionError{Column: 1, Message: "empty expression"}
+	}
+	if utf8.RuneCountInString(source) > maxExpressionLength {
+		return nil, &ExpressionError{Column: maxExpressionLength + 1, Message: fmt.Sprintf("expression is longer than %d characters", maxExpressionLength)}
+	}
+
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, p.errorf(t, "expected and, or or the end of the expression, found %s", t)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Matches evaluates the condition for a product.
+func (e *Expression) Matches(p product.Product) bool {
+	return e.root.eval(p)
+}
+
+// String returns the source of the condition.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Relations returns the product relations the condition inspects.
+func (e *Expression) Relations() product.Relations {
+	return product.Relations{Category: e.root.uses("category")}
+}
+
+// Condition returns the condition as a product.Condition, for the persistence
+// layer to find the matching products.
+func (e *Expression) Condition() product.Condition {
+	return e.root.condition()
+}
+
+// expressionNode is a node of a parsed condition.
+type expressionNode interface {
+	eval(p product.Product) bool
+	uses(field string) bool
+	condition() product.Condition
+}
+
+type andNode struct{ left, right expressionNode }
+
+func (n andNode) eval(p product.Product) bool { return n.left.eval(p) && n.right.eval(p) }
+func (n andNode) uses(field string) bool      { return n.left.uses(field) || n.right.uses(field) }
+func (n andNode) condition() product.Condition {
+	return product.Condition{Op: "and", Conditions: []product.Condition{n.left.condition(), n.right.condition()}}
+}
+
+type orNode struct{ left, right expressionNode }
+
+func (n orNode) eval(p product.Product) bool { return n.left.eval(p) || n.right.eval(p) }
+func (n orNode) uses(field string) bool      { return n.left.uses(field) || n.right.uses(field) }
+func (n orNode) condition() product.Condition {
+	return product.Condition{Op: "or", Conditions: []product.Condition{n.left.condition(), n.right.condition()}}
+}
+
+type notNode struct{ operand expressionNode }
+
+func (n notNode) eval(p product.Product) bool { return !n.operand.eval(p) }
+func (n notNode) uses(field string) bool      { return n.operand.uses(field) }
+func (n notNode) condition() product.Condition {
+	return product.Condition{Op: "not", Conditions: []product.Condition{n.operand.condition()}}
+}
+
+// textNode compares a text field with one or more strings.
+type textNode struct {
+	field  string
+	op     string
+	values []string
+}
+
+func (n textNode) eval(p product.Product) bool {
+	var value string
+	switch n.field {
+	case "category":
+		if p.Category != nil {
+			value = p.Category.Code
+		}
+	case "code":
+		value = p.Code
+	}
+
+	switch n.op {
+	case "==":
+		return value == n.values[0]
+	case "!=":
+		return value != n.values[0]
+	case "startsWith":
+		return strings.HasPrefix(value, n.values[0])
+	case "endsWith":
+		return strings.HasSuffix(value, n.values[0])
+	case "contains":
+		return strings.Contains(value, n.values[0])
+	default: // in
+		for _, v := range n.values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (n textNode) uses(field string) bool { return n.field == field }
+
+func (n textNode) condition() product.Condition {
+	return product.Condition{Op: n.op, Field: n.field, Values: n.values}
+}
+
+// numberNode compares a number field with one or more numbers.
+type numberNode struct {
+	field  string
+	op     string
+	values []decimal.Decimal
+}
+
+func (n numberNode) eval(p product.Product) bool {
+	value := p.Price
+	switch n.op {
+	case "==":
+		return value.Equal(n.values[0])
+	case "!=":
+		return !value.Equal(n.values[0])
+	case "<":
+		return value.LessThan(n.values[0])
+	case "<=":
+		return value.LessThanOrEqual(n.values[0])
+	case ">":
+		return value.GreaterThan(n.values[0])
+	case ">=":
+		return value.GreaterThanOrEqual(n.values[0])
+	default: // in
+		for _, v := range n.values {
+			if value.Equal(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (n numberNode) uses(field string) bool { return n.field == field }
+
+func (n numberNode) condition() product.Condition {
+	return product.Condition{Op: n.op, Field: n.field, Numbers: n.values}
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenPunct
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEnd:
+		return "the end of the expression"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	case tokenNumber:
+		return fmt.Sprintf("number %s", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lex splits a condition into tokens. Strings are double-quoted with
+// backslash escapes for quotes and backslashes.
+func lex(source string) ([]token, error) {
+	runes := []rune(source)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		column := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), column: column})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), column: column})
+		case r == '"':
+			var text strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, &ExpressionError{Column: column, Message: "unterminated string"}
+				}
+				if runes[i] == '"' {
+					i++
+					break
+				}
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					i++
+				}
+				text.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text.String(), column: column})
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, token{kind: tokenPunct, text: string(r), column: column})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			switch op {
+			case "=":
+				return nil, &ExpressionError{Column: column, Message: "use == to compare"}
+			case "!":
+				return nil, &ExpressionError{Column: column, Message: "use not to negate"}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, column: column})
+			i += len(op)
+		case r == '\'':
+			return nil, &ExpressionError{Column: column, Message: "strings are double-quoted"}
+		default:
+			return nil, &ExpressionError{Column: column, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, column: len(runes) + 1}), nil
+}
+
+// parser is a recursive descent parser over the tokens of a condition:
+//
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" or ")" | comparison
+//	comparison = field operator value
+//	value      = string | number | "[" [ literal { "," literal } ] "]"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokenIdent && t.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) punct(text string) bool {
+	t := p.peek()
+	if t.kind == tokenPunct && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return &ExpressionError{Column: t.column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) or(depth int) (expressionNode, error) {
+	left, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and(depth int) (expressionNode, error) {
+	left, err := p.unary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.unary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary(depth int) (expressionNode, error) {
+	if depth > maxExpressionDepth {
+		return nil, p.errorf(p.peek(), "expression is nested deeper than %d levels", maxExpressionDepth)
+	}
+	if p.keyword("not") {
+		operand, err := p.unary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	open := p.peek()
+	if p.punct("(") {
+		inner, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if !p.punct(")") {
+			return nil, p.errorf(p.peek(), "expected ) to close the ( at column %d, found %s", open.column, p.peek())
+		}
+		return inner, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (expressionNode, error) {
+	field := p.next()
+	if field.kind != tokenIdent {
+		return nil, p.errorf(field, "expected a field (%s), found %s", fieldNames, field)
+	}
+	typ, ok := expressionFields[field.text]
+	if !ok {
+		return nil, p.errorf(field, "unknown field %q, expected %s", field.text, fieldNames)
+	}
+
+	op := p.next()
+	switch {
+	case op.kind == tokenOperator:
+	case op.kind == tokenIdent && (op.text == "in" || op.text == "startsWith" || op.text == "endsWith" || op.text == "contains"):
+	default:
+		return nil, p.errorf(op, "expected an operator after %s, found %s", field.text, op)
+	}
+	if typ == textField && strings.ContainsAny(op.text, "<>") {
+		return nil, p.errorf(op, "%s is text and cannot be compared with %s", field.text, op.text)
+	}
+	if typ == numberField && (op.text == "startsWith" || op.text == "endsWith" || op.text == "contains") {
+		return nil, p.errorf(op, "%s is a number and does not support %s", field.text, op.text)
+	}
+
+	var literals []token
+	if op.text == "in" {
+		open := p.peek()
+		if !p.punct("[") {
+			return nil, p.errorf(open, "expected [ to start the list after in, found %s", open)
+		}
+		for !p.punct("]") {
+			if len(literals) > 0 && !p.punct(",") {
+				return nil, p.errorf(p.peek(), "expected , or ] in the list at column %d, found %s", open.column, p.peek())
+			}
+			literals = append(literals, p.next())
+		}
+		if len(literals) == 0 {
+			return nil, p.errorf(open, "empty list after in")
+		}
+	} else {
+		literals = []token{p.next()}
+	}
+
+	if typ == textField {
+		node := textNode{field: field.text, op: op.text}
+		for _, l := range literals {
+			if l.kind != tokenString {
+				return nil, p.errorf(l, "%s is text and compares with double-quoted strings, found %s", field.text, l)
+			}
+			node.values = append(node.values, l.text)
+		}
+		return node, nil
+	}
+
+	node := numberNode{field: field.text, op: op.text}
+	for _, l := range literals {
+		if l.kind != tokenNumber {
+			return nil, p.errorf(l, "%s is a number and compares with numbers, found %s", field.text, l)
+		}
+		value, err := decimal.NewFromString(l.text)
+		if err != nil {
+			return nil, p.errorf(l, "invalid number %s", l.text)
+		}
+		node.values = append(node.values, value)
+	}
+	return node, nil
+}
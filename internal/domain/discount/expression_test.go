@@ -0,0 +1,158 @@
+package discount
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	boots := product.Product{Code: "PROD009", Price: decimal.NewFromInt(150), Category: &product.Category{Code: "boots"}}
+	shoes := product.Product{Code: "PROD001", Price: decimal.NewFromFloat(120.5), Category: &product.Category{Code: "shoes"}}
+	cheap := product.Product{Code: "PROD010", Price: decimal.NewFromInt(40), Category: &product.Category{Code: "boots"}}
+	uncategorized := product.Product{Code: "SALE001", Price: decimal.NewFromInt(200)}
+
+	t.Run("evaluates conditions", func(t *testing.T) {
+		tests := map[string][]bool{
+			`category in ["shoes","boots"] and price > 100 and not code startsWith "PROD00"`: {false, false, false, false},
+			`category in ["shoes", "boots"] and price > 100`:                                 {true, true, false, false},
+			`category == "boots" or code endsWith "001"`:                                     {true, true, true, true},
+			`category != "boots" and (price >= 200 or code contains "00")`:                   {false, true, false, true},
+			`price <= 120.50 and not category == ""`:                                         {false, true, true, false},
+			`category == ""`:                                                                 {false, false, false, true},
+			`price in [40, 150]`:                                                             {true, false, true, false},
+			`not not price < 41`:                                                             {false, false, true, false},
+		}
+		for source, want := range tests {
+			expression, err := ParseExpression(source)
+			require.NoError(t, err, source)
+
+			got := []bool{expression.Matches(boots), expression.Matches(shoes), expression.Matches(cheap), expression.Matches(uncategorized)}
+			assert.Equal(t, want, got, source)
+		}
+	})
+
+	t.Run("binds and tighter than or", func(t *testing.T) {
+		expression, err := ParseExpression(`code == "SALE001" or category == "boots" and price > 100`)
+		require.NoError(t, err)
+
+		assert.True(t, expression.Matches(uncategorized))
+		assert.True(t, expression.Matches(boots))
+		assert.False(t, expression.Matches(cheap))
+	})
+
+	t.Run("reports the relations it inspects", func(t *testing.T) {
+		category, err := ParseExpression(`price > 10 and category == "boots"`)
+		require.NoError(t, err)
+		code, err := ParseExpression(`code startsWith "PROD" and price > 10`)
+		require.NoError(t, err)
+
+		assert.Equal(t, product.Relations{Category: true}, category.Relations())
+		assert.Equal(t, product.Relations{}, code.Relations())
+	})
+
+	t.Run("converts to a product condition", func(t *testing.T) {
+		expression, err := ParseExpression(`category in ["shoes", "boots"] and not (price < 100 or code startsWith "SALE")`)
+		require.NoError(t, err)
+
+		assert.Equal(t, product.Condition{Op: "and", Conditions: []product.Condition{
+			{Op: "in", Field: "category", Values: []string{"shoes", "boots"}},
+			{Op: "not", Conditions: []product.Condition{
+				{Op: "or", Conditions: []product.Condition{
+					{Op: "<", Field: "price", Numbers: []decimal.Decimal{decimal.NewFromInt(100)}},
+					{Op: "startsWith", Field: "code", Values: []string{"SALE"}},
+				}},
+			}},
+		}}, expression.Condition())
+	})
+
+	t.Run("explains invalid conditions", func(t *testing.T) {
+		tests := map[string]string{
+			``:                          "column 1: empty expression",
+			`brand == "acme"`:           `column 1: unknown field "brand", expected category, code or price`,
+			`category = "boots"`:        "column 10: use == to compare",
+			`category == 'boots'`:       "column 13: strings are double-quoted",
+			`category == "boots`:        "column 13: unterminated string",
+			`price > "100"`:             `column 9: price is a number and compares with numbers, found string "100"`,
+			`category > "a"`:            "column 10: category is text and cannot be compared with >",
+			`price startsWith "1"`:      "column 7: price is a number and does not support startsWith",
+			`code in []`:                "column 9: empty list after in",
+			`code in "PROD001"`:         `column 9: expected [ to start the list after in, found string "PROD001"`,
+			`(price > 10`:               "column 12: expected ) to close the ( at column 1, found the end of the expression",
+			`price > 10 price < 20`:     `column 12: expected and, or or the end of the expression, found "price"`,
+			`price > 10 and`:            "column 15: expected a field (category, code or price), found the end of the expression",
+			`code ~ "PROD"`:             "column 6: unexpected character '~'",
+			`price > 1.2.3`:             "column 9: invalid number 1.2.3",
+			`code in ["a" "b"]`:         `column 14: expected , or ] in the list at column 9, found string "b"`,
+			`category matches "boots"`:  `column 10: expected an operator after category, found "matches"`,
+			`price == 10 and not`:       "column 20: expected a field (category, code or price), found the end of the expression",
+			`code startsWith PROD`:      `column 17: code is text and compares with double-quoted strings, found "PROD"`,
+			`category in ["boots", 10]`: "column 23: category is text and compares with double-quoted strings, found number 10",
+		}
+		for source, message := range tests {
+			_, err := ParseExpression(source)
+
+			require.Error(t, err, source)
+			assert.ErrorIs(t, err, ErrInvalidExpression, source)
+			assert.Equal(t, message, err.Error(), source)
+		}
+	})
+
+	t.Run("bounds the nesting", func(t *testing.T) {
+		source := ""
+		for i := 0; i < 40; i++ {
+			source += "not "
+		}
+
+		_, err := ParseExpression(source + `code == "PROD001"`)
+
+		assert.ErrorIs(t, err, ErrInvalidExpression)
+	})
+}
+
+func TestEngine_ExpressionRules(t *testing.T) {
+	engine, err := NewEngineFromRules([]Rule{
+		{Kind: RuleSKU, Target: "000003", Percentage: 50, Position: 1},
+		{Kind: RuleExpression, Target: "premium boots", Expression: `category == "boots" and price > 100`, Percentage: 25, Position: 2},
+	})
+	require.NoError(t, err)
+	boots := product.Product{
+		Code:     "PROD009",
+		Price:    decimal.NewFromInt(150),
+		Category: &product.Category{Code: "boots"},
+		Variants: []product.Variant{{SKU: "000003", Price: decimal.NewFromInt(150)}, {SKU: "000004", Price: decimal.NewFromInt(150)}},
+	}
+
+	t.Run("prices products and variants matching the condition", func(t *testing.T) {
+		assert.Equal(t, 25, engine.PriceVariant(PricingContext{}, boots.Variants[1], boots).Percentage)
+		assert.Equal(t, 50, engine.PriceVariant(PricingContext{}, boots.Variants[0], boots).Percentage)
+	})
+
+	t.Run("needs the relations of the condition", func(t *testing.T) {
+		assert.Equal(t, product.AllRelations(), engine.RequiredRelations())
+	})
+
+	t.Run("describes expression rules as sale conditions", func(t *testing.T) {
+		criteria := engine.SaleCriteria(PricingContext{})
+
+		assert.Empty(t, criteria.CategoryCodes)
+		assert.Equal(t, []string{"000003"}, criteria.SKUs)
+		require.Len(t, criteria.Conditions, 1)
+		assert.Equal(t, "and", criteria.Conditions[0].Op)
+	})
+
+	t.Run("leaves categories capped at zero out of the sale conditions", func(t *testing.T) {
+		criteria := engine.WithGuardrails(Guardrails{Caps: []Cap{{Category: "boots", Percentage: 0}}}).SaleCriteria(PricingContext{})
+
+		require.Len(t, criteria.Conditions, 1)
+		expression, err := ParseExpression(`category == "boots" and price > 100`)
+		require.NoError(t, err)
+		assert.Equal(t, product.Condition{Op: "and", Conditions: []product.Condition{
+			expression.Condition(),
+			{Op: "not", Conditions: []product.Condition{{Op: "in", Field: "category", Values: []string{"boots"}}}},
+		}}, criteria.Conditions[0])
+	})
+}
@@ -0,0 +1,180 @@
+package discount
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidGuardrail is returned for caps and floors that cannot bound a discount.
+var ErrInvalidGuardrail = errors.New("invalid discount guardrail")
+
+// LimitKind names the guardrail that limited a discount.
+type LimitKind string
+
+const (
+	// LimitCap lowered the percentage to the cap of the product category.
+	LimitCap LimitKind = "cap"
+	// LimitMinPrice raised the price to the minimum sellable price.
+	LimitMinPrice LimitKind = "min_price"
+	// LimitMargin raised the price to the cost price plus the minimum margin.
+	LimitMargin LimitKind = "margin"
+)
+
+// Cap is the highest discount percentage products of a category may get.
+type Cap struct {
+	Category   string
+	Percentage int
+}
+
+// Validate checks the cap names a category and a percentage in 0-100.
+func (c Cap) Validate() error {
+	if c.Category == "" {
+		return fmt.Errorf("%w: cap without category", ErrInvalidGuardrail)
+	}
+	if c.Percentage < 0 || c.Percentage > 100 {
+		return fmt.Errorf("%w: cap %d of category %s is outside 0-100", ErrInvalidGuardrail, c.Percentage, c.Category)
+	}
+	return nil
+}
+
+// Guardrails bound the discounts of an Engine, whichever rules match.
+// Caps limit the percentage per category. MinPrice is the lowest price a
+// discount may sell at, zero for none. MinMargin is the margin in percent over
+// the cost price a discount may not go below, for products with a cost price;
+// with no margin discounts still never sell below cost.
+type Guardrails struct {
+	Caps      []Cap
+	MinPrice  decimal.Decimal
+	MinMargin int
+}
+
+// Validate checks the caps and floors.
+func (g Guardrails) Validate() error {
+	seen := make(map[string]bool, len(g.Caps))
+	for _, c := range g.Caps {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if seen[c.Category] {
+			return fmt.Errorf("%w: duplicate cap for category %s", ErrInvalidGuardrail, c.Category)
+		}
+		seen[c.Category] = true
+	}
+	if g.MinPrice.IsNegative() {
+		return fmt.Errorf("%w: negative minimum price %s", ErrInvalidGuardrail, g.MinPrice)
+	}
+	if g.MinMargin < 0 {
+		return fmt.Errorf("%w: negative minimum margin %d", ErrInvalidGuardrail, g.MinMargin)
+	}
+	return nil
+}
+
+// blocks reports whether discounts of the category are capped at zero.
+func (g Guardrails) blocks(category string) bool {
+	for _, c := range g.Caps {
+		if c.Category == category && c.Percentage == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// blocked returns the categories whose discounts are capped at zero.
+func (g Guardrails) blocked() []string {
+	var categories []string
+	for _, c := range g.Caps {
+		if c.Percentage == 0 {
+			categories = append(categories, c.Category)
+		}
+	}
+	return categories
+}
+
+// Limit explains how a guardrail changed a discount. Requested is the
+// percentage of the matching rule. Reason is shown to customers, so it never
+// states the cost price.
+type Limit struct {
+	Kind      LimitKind
+	Requested int
+	Reason    string
+}
+
+// String describes the limit for logs.
+func (l Limit) String() string {
+	return fmt.Sprintf("%s: requested %d%%, %s", l.Kind, l.Requested, l.Reason)
+}
+
+// Price is a price after discounts and guardrails. Percentage is the discount
+// actually given, rounded down when a floor set the final price. Limit is nil
+// when no guardrail changed the discount.
+type Price struct {
+	Original   decimal.Decimal
+	Final      decimal.Decimal
+	Percentage int
+	Limit      *Limit
+}
+
+// apply takes percentage off price, a price of product p, within the guardrails.
+// The cap of the product category applies first, then the highest floor; a
+// floor above the original price removes the discount instead of raising it.
+func (g Guardrails) apply(price decimal.Decimal, percentage int, p product.Product) Price {
+	result := Price{Original: price, Final: price}
+	if percentage <= 0 {
+		return result
+	}
+
+	requested := percentage
+	if p.Category != nil {
+		for _, c := range g.Caps {
+			if c.Category == p.Category.Code && percentage > c.Percentage {
+				percentage = c.Percentage
+				result.Limit = &Limit{
+					Kind:      LimitCap,
+					Requested: requested,
+					Reason:    fmt.Sprintf("category %s is capped at %d%%", c.Category, c.Percentage),
+				}
+			}
+		}
+	}
+
+	result.Percentage = percentage
+	result.Final = price.Sub(price.Mul(decimal.NewFromInt(int64(percentage))).Div(decimal.NewFromInt(100)))
+
+	floor, limit := g.floor(p, requested)
+	if !price.IsPositive() || result.Final.GreaterThanOrEqual(floor) {
+		return result
+	}
+
+	result.Final = decimal.Min(floor, price)
+	result.Percentage = int(price.Sub(result.Final).Mul(decimal.NewFromInt(100)).Div(price).IntPart())
+	result.Limit = limit
+	return result
+}
+
+// floor returns the lowest price products like p may be discounted to, with
+// the limit explaining it.
+func (g Guardrails) floor(p product.Product, requested int) (decimal.Decimal, *Limit) {
+	floor := g.MinPrice
+	limit := &Limit{
+		Kind:      LimitMinPrice,
+		Requested: requested,
+		Reason:    fmt.Sprintf("minimum price is %s", g.MinPrice.StringFixed(2)),
+	}
+
+	if p.CostPrice.IsPositive() {
+		margin := p.CostPrice.Mul(decimal.NewFromInt(int64(100 + g.MinMargin))).Div(decimal.NewFromInt(100)).RoundUp(2)
+		if margin.GreaterThan(floor) {
+			floor = margin
+			limit = &Limit{
+				Kind:      LimitMargin,
+				Requested: requested,
+				Reason:    fmt.Sprintf("discounts keep a %d%% margin over cost", g.MinMargin),
+			}
+		}
+	}
+
+	return floor, limit
+}
@@ -0,0 +1,148 @@
+package discount
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardrails_Validate(t *testing.T) {
+	t.Run("accepts caps and floors", func(t *testing.T) {
+		g := Guardrails{
+			Caps:      []Cap{{Category: "boots", Percentage: 25}, {Category: "clothing", Percentage: 40}},
+			MinPrice:  decimal.NewFromInt(5),
+			MinMargin: 10,
+		}
+
+		assert.NoError(t, g.Validate())
+	})
+
+	t.Run("rejects invalid caps and floors", func(t *testing.T) {
+		for name, g := range map[string]Guardrails{
+			"cap without category": {Caps: []Cap{{Percentage: 10}}},
+			"cap over 100":         {Caps: []Cap{{Category: "boots", Percentage: 101}}},
+			"duplicate cap":        {Caps: []Cap{{Category: "boots", Percentage: 10}, {Category: "boots", Percentage: 20}}},
+			"negative min price":   {MinPrice: decimal.NewFromInt(-1)},
+			"negative margin":      {MinMargin: -5},
+		} {
+			assert.ErrorIs(t, g.Validate(), ErrInvalidGuardrail, name)
+		}
+	})
+}
+
+func TestEngine_Guardrails(t *testing.T) {
+	boots := product.Product{
+		Code:     "PROD009",
+		Price:    decimal.NewFromInt(100),
+		Category: &product.Category{Code: "boots"},
+		Variants: []product.Variant{{SKU: "000003", Price: decimal.NewFromInt(80)}},
+	}
+	engine := NewEngine([]Strategy{
+		NewCategoryDiscountStrategy("boots", 30),
+		NewSKUDiscountStrategy("000003", 50),
+	})
+
+	t.Run("leaves discounts within the guardrails alone", func(t *testing.T) {
+		price := engine.WithGuardrails(Guardrails{Caps: []Cap{{Category: "boots", Percentage: 30}}}).PriceProduct(PricingContext{}, boots)
+
+		assert.Equal(t, 30, price.Percentage)
+		assert.True(t, price.Final.Equal(decimal.NewFromInt(70)))
+		assert.Nil(t, price.Limit)
+	})
+
+	t.Run("caps the percentage of the category", func(t *testing.T) {
+		price := engine.WithGuardrails(Guardrails{Caps: []Cap{{Category: "boots", Percentage: 20}}}).PriceProduct(PricingContext{}, boots)
+
+		assert.Equal(t, 20, price.Percentage)
+		assert.True(t, price.Final.Equal(decimal.NewFromInt(80)))
+		require.NotNil(t, price.Limit)
+		assert.Equal(t, LimitCap, price.Limit.Kind)
+		assert.Equal(t, 30, price.Limit.Requested)
+		assert.Equal(t, "cap: requested 30%, category boots is capped at 20%", price.Limit.String())
+	})
+
+	t.Run("raises the price to the minimum price", func(t *testing.T) {
+		price := engine.WithGuardrails(Guardrails{MinPrice: decimal.NewFromInt(75)}).PriceProduct(PricingContext{}, boots)
+
+		assert.Equal(t, 25, price.Percentage)
+		assert.True(t, price.Final.Equal(decimal.NewFromInt(75)))
+		require.NotNil(t, price.Limit)
+		assert.Equal(t, LimitMinPrice, price.Limit.Kind)
+	})
+
+	t.Run("keeps the margin over the cost price", func(t *testing.T) {
+		costed := boots
+		costed.CostPrice = decimal.NewFromInt(40)
+
+		price := engine.WithGuardrails(Guardrails{MinPrice: decimal.NewFromInt(20), MinMargin: 10}).PriceVariant(PricingContext{}, costed.Variants[0], costed)
+
+		assert.True(t, price.Original.Equal(decimal.NewFromInt(80)))
+		assert.True(t, price.Final.Equal(decimal.NewFromInt(44)))
+		assert.Equal(t, 45, price.Percentage)
+		require.NotNil(t, price.Limit)
+		assert.Equal(t, LimitMargin, price.Limit.Kind)
+		assert.Equal(t, 50, price.Limit.Requested)
+		assert.Equal(t, "discounts keep a 10% margin over cost", price.Limit.Reason)
+	})
+
+	t.Run("exposes the floor for basket promotions", func(t *testing.T) {
+		costed := boots
+		costed.CostPrice = decimal.NewFromInt(40)
+		bounded := engine.WithGuardrails(Guardrails{MinPrice: decimal.NewFromInt(20), MinMargin: 10})
+
+		floor, limit := bounded.Floor(costed)
+		assert.True(t, floor.Equal(decimal.NewFromInt(44)))
+		assert.Equal(t, LimitMargin, limit.Kind)
+		assert.Zero(t, limit.Requested)
+
+		floor, limit = bounded.Floor(boots)
+		assert.True(t, floor.Equal(decimal.NewFromInt(20)))
+		assert.Equal(t, LimitMinPrice, limit.Kind)
+	})
+
+	t.Run("never sells below cost without a margin", func(t *testing.T) {
+		costed := boots
+		costed.CostPrice = decimal.NewFromInt(45)
+
+		price := engine.WithGuardrails(Guardrails{}).PriceVariant(PricingContext{}, costed.Variants[0], costed)
+
+		assert.True(t, price.Final.Equal(decimal.NewFromInt(45)))
+		require.NotNil(t, price.Limit)
+		assert.Equal(t, LimitMargin, price.Limit.Kind)
+	})
+
+	t.Run("removes the discount when the floor is above the price", func(t *testing.T) {
+		price := engine.WithGuardrails(Guardrails{MinPrice: decimal.NewFromInt(150)}).PriceProduct(PricingContext{}, boots)
+
+		assert.Zero(t, price.Percentage)
+		assert.True(t, price.Final.Equal(decimal.NewFromInt(100)))
+		require.NotNil(t, price.Limit)
+	})
+
+	t.Run("does not limit undiscounted prices", func(t *testing.T) {
+		shoes := product.Product{Code: "PROD001", Price: decimal.NewFromInt(10), Category: &product.Category{Code: "shoes"}}
+
+		price := engine.WithGuardrails(Guardrails{MinPrice: decimal.NewFromInt(50)}).PriceProduct(PricingContext{}, shoes)
+
+		assert.Zero(t, price.Percentage)
+		assert.Nil(t, price.Limit)
+	})
+
+	t.Run("leaves categories capped at zero out of the sale criteria", func(t *testing.T) {
+		criteria := engine.WithGuardrails(Guardrails{Caps: []Cap{{Category: "boots", Percentage: 0}}}).SaleCriteria(PricingContext{})
+
+		assert.Empty(t, criteria.CategoryCodes)
+		assert.Equal(t, []string{"000003"}, criteria.SKUs)
+	})
+
+	t.Run("needs the category for caps", func(t *testing.T) {
+		relations := NewEngine([]Strategy{NewSKUDiscountStrategy("000003", 50)}).
+			WithGuardrails(Guardrails{Caps: []Cap{{Category: "boots", Percentage: 20}}}).
+			RequiredRelations()
+
+		assert.Equal(t, product.Relations{Category: true, Variants: true}, relations)
+	})
+}
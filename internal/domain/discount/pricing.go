@@ -0,0 +1,77 @@
+package discount
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PricingContext tells who is asking for a price: the customer segment (such
+// as vip, employee or wholesale), the market and the sales channel. The zero
+// value is an anonymous customer.
+type PricingContext struct {
+	Segment string
+	Market  string
+	Channel string
+}
+
+// NewPricingContext returns a pricing context with its values lower-cased and trimmed.
+func NewPricingContext(segment, market, channel string) PricingContext {
+	return PricingContext{
+		Segment: normalizeAudienceValue(segment),
+		Market:  normalizeAudienceValue(market),
+		Channel: normalizeAudienceValue(channel),
+	}
+}
+
+type pricingContextKey struct{}
+
+// WithPricingContext returns a copy of ctx carrying the pricing context.
+func WithPricingContext(ctx context.Context, pc PricingContext) context.Context {
+	return context.WithValue(ctx, pricingContextKey{}, pc)
+}
+
+// PricingContextFrom returns the pricing context carried by ctx, or the
+// anonymous one when there is none.
+func PricingContextFrom(ctx context.Context) PricingContext {
+	pc, _ := ctx.Value(pricingContextKey{}).(PricingContext)
+	return pc
+}
+
+// Audience restricts a strategy to some pricing contexts. Each list that is
+// not empty must contain the matching value of the context; an empty
+// Audience matches every context.
+type Audience struct {
+	Segments []string
+	Markets  []string
+	Channels []string
+}
+
+// Matches reports whether the pricing context is part of the audience.
+func (a Audience) Matches(pc PricingContext) bool {
+	return matchesAudienceValue(a.Segments, pc.Segment) &&
+		matchesAudienceValue(a.Markets, pc.Market) &&
+		matchesAudienceValue(a.Channels, pc.Channel)
+}
+
+// Validate checks that the audience values are lower-case words that can be
+// stored as comma-separated lists.
+func (a Audience) Validate() error {
+	for _, values := range [][]string{a.Segments, a.Markets, a.Channels} {
+		for _, v := range values {
+			if v == "" || v != normalizeAudienceValue(v) || strings.Contains(v, ",") {
+				return fmt.Errorf("%w: audience value %q must be lower case, without commas", ErrInvalidRule, v)
+			}
+		}
+	}
+	return nil
+}
+
+func matchesAudienceValue(values []string, value string) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
+
+func normalizeAudienceValue(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
@@ -0,0 +1,30 @@
+package discount
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPricingContext(t *testing.T) {
+	t.Run("normalizes values", func(t *testing.T) {
+		assert.Equal(t, PricingContext{Segment: "vip", Market: "de", Channel: "app"}, NewPricingContext(" VIP ", "DE", "App"))
+	})
+
+	t.Run("travels in a context", func(t *testing.T) {
+		pc := PricingContext{Segment: "employee"}
+
+		assert.Equal(t, pc, PricingContextFrom(WithPricingContext(context.Background(), pc)))
+		assert.Equal(t, PricingContext{}, PricingContextFrom(context.Background()), "anonymous by default")
+	})
+}
+
+func TestAudience_Matches(t *testing.T) {
+	audience := Audience{Segments: []string{"vip", "employee"}, Markets: []string{"de"}}
+
+	assert.True(t, audience.Matches(PricingContext{Segment: "vip", Market: "de", Channel: "app"}))
+	assert.False(t, audience.Matches(PricingContext{Segment: "vip", Market: "fr"}))
+	assert.False(t, audience.Matches(PricingContext{Market: "de"}), "anonymous customers are not in a segment")
+	assert.True(t, Audience{}.Matches(PricingContext{}), "an empty audience matches everyone")
+}
@@ -0,0 +1,86 @@
+package discount
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RuleKind selects the strategy a stored rule is turned into.
+type RuleKind string
+
+const (
+	RuleCategory   RuleKind = "category"
+	RuleSKU        RuleKind = "sku"
+	RuleExpression RuleKind = "expression"
+)
+
+// ErrInvalidRule is returned for rules that cannot be turned into a strategy.
+var ErrInvalidRule = errors.New("invalid discount rule")
+
+// Rule is the stored form of a discount strategy. Rules are evaluated by
+// ascending Position, first match wins, skipping rules whose Audience does not
+// include the pricing context. Expression rules are named by Target and
+// match the products their Expression condition holds for.
+type Rule struct {
+	Kind       RuleKind
+	Target     string
+	Expression string
+	Percentage int
+	Audience   Audience
+	Position   int
+}
+
+// Validate checks the rule can be turned into a strategy.
+func (r Rule) Validate() error {
+	if r.Kind != RuleCategory && r.Kind != RuleSKU && r.Kind != RuleExpression {
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidRule, r.Kind)
+	}
+	if r.Target == "" {
+		return fmt.Errorf("%w: %s rule without target", ErrInvalidRule, r.Kind)
+	}
+	if r.Percentage < 0 || r.Percentage > 100 {
+		return fmt.Errorf("%w: percentage %d of %s %s is outside 0-100", ErrInvalidRule, r.Percentage, r.Kind, r.Target)
+	}
+	if r.Kind != RuleExpression && r.Expression != "" {
+		return fmt.Errorf("%w: %s rule %s with an expression", ErrInvalidRule, r.Kind, r.Target)
+	}
+	if r.Kind == RuleExpression {
+		if _, err := ParseExpression(r.Expression); err != nil {
+			return fmt.Errorf("%w: expression %s: %w", ErrInvalidRule, r.Target, err)
+		}
+	}
+	return r.Audience.Validate()
+}
+
+// Strategy returns the discount strategy described by the rule.
+func (r Rule) Strategy() (Strategy, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	switch r.Kind {
+	case RuleCategory:
+		return NewCategoryDiscountStrategy(r.Target, r.Percentage), nil
+	case RuleExpression:
+		expression, err := ParseExpression(r.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return NewExpressionDiscountStrategy(r.Target, expression, r.Percentage), nil
+	}
+	return NewSKUDiscountStrategy(r.Target, r.Percentage), nil
+}
+
+// NewEngineFromRules creates a discount engine from rules sorted by position.
+func NewEngineFromRules(rules []Rule) (*Engine, error) {
+	strategies := make([]Strategy, 0, len(rules))
+	audiences := make([]Audience, 0, len(rules))
+	for _, rule := range rules {
+		strategy, err := rule.Strategy()
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, strategy)
+		audiences = append(audiences, rule.Audience)
+	}
+	return NewEngineWithAudiences(strategies, audiences), nil
+}
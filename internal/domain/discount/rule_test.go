@@ -0,0 +1,90 @@
+package discount
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Strategy(t *testing.T) {
+	t.Run("builds the strategy of each kind", func(t *testing.T) {
+		category, err := Rule{Kind: RuleCategory, Target: "boots", Percentage: 30}.Strategy()
+		require.NoError(t, err)
+		assert.Equal(t, NewCategoryDiscountStrategy("boots", 30), category)
+
+		sku, err := Rule{Kind: RuleSKU, Target: "000003", Percentage: 15}.Strategy()
+		require.NoError(t, err)
+		assert.Equal(t, NewSKUDiscountStrategy("000003", 15), sku)
+
+		expression, err := Rule{Kind: RuleExpression, Target: "premium", Expression: `price > 100`, Percentage: 10}.Strategy()
+		require.NoError(t, err)
+		assert.IsType(t, &ExpressionDiscountStrategy{}, expression)
+	})
+
+	t.Run("rejects invalid rules", func(t *testing.T) {
+		rules := map[string]Rule{
+			"unknown kind":        {Kind: "brand", Target: "acme", Percentage: 10},
+			"missing target":      {Kind: RuleCategory, Percentage: 10},
+			"negative percentage": {Kind: RuleSKU, Target: "000003", Percentage: -5},
+			"percentage over 100": {Kind: RuleSKU, Target: "000003", Percentage: 120},
+			"upper-case segment":  {Kind: RuleSKU, Target: "000003", Percentage: 10, Audience: Audience{Segments: []string{"VIP"}}},
+			"empty market":        {Kind: RuleSKU, Target: "000003", Percentage: 10, Audience: Audience{Markets: []string{""}}},
+			"invalid expression":  {Kind: RuleExpression, Target: "premium", Expression: `price > "100"`, Percentage: 10},
+			"missing expression":  {Kind: RuleExpression, Target: "premium", Percentage: 10},
+			"stray expression":    {Kind: RuleCategory, Target: "boots", Expression: `price > 100`, Percentage: 10},
+		}
+		for name, rule := range rules {
+			_, err := rule.Strategy()
+			assert.ErrorIs(t, err, ErrInvalidRule, name)
+		}
+	})
+}
+
+func TestNewEngineFromRules(t *testing.T) {
+	t.Run("evaluates rules in order", func(t *testing.T) {
+		engine, err := NewEngineFromRules([]Rule{
+			{Kind: RuleCategory, Target: "boots", Percentage: 30, Position: 1},
+			{Kind: RuleSKU, Target: "000003", Percentage: 15, Position: 2},
+		})
+		require.NoError(t, err)
+
+		prod := product.Product{
+			Code:     "PROD009",
+			Price:    decimal.NewFromFloat(100.0),
+			Category: &product.Category{Code: "boots"},
+			Variants: []product.Variant{{SKU: "000003"}},
+		}
+
+		assert.Equal(t, 30, engine.GetDiscountPercentage(PricingContext{}, prod))
+		assert.Equal(t, 15, engine.GetVariantDiscountPercentage(PricingContext{}, "000003", prod))
+	})
+
+	t.Run("applies rules to their audience only", func(t *testing.T) {
+		engine, err := NewEngineFromRules([]Rule{
+			{Kind: RuleCategory, Target: "boots", Percentage: 40, Audience: Audience{Segments: []string{"vip", "employee"}}, Position: 1},
+			{Kind: RuleCategory, Target: "boots", Percentage: 30, Position: 2},
+		})
+		require.NoError(t, err)
+		prod := product.Product{Code: "PROD009", Price: decimal.NewFromFloat(100.0), Category: &product.Category{Code: "boots"}}
+
+		assert.Equal(t, 40, engine.GetDiscountPercentage(PricingContext{Segment: "vip"}, prod))
+		assert.Equal(t, 30, engine.GetDiscountPercentage(PricingContext{Segment: "wholesale"}, prod))
+		assert.Equal(t, 30, engine.GetDiscountPercentage(PricingContext{}, prod))
+	})
+
+	t.Run("names the rule with an invalid expression", func(t *testing.T) {
+		err := Rule{Kind: RuleExpression, Target: "premium", Expression: `brand == "acme"`, Percentage: 10}.Validate()
+
+		assert.ErrorIs(t, err, ErrInvalidExpression)
+		assert.EqualError(t, err, `invalid discount rule: expression premium: column 1: unknown field "brand", expected category, code or price`)
+	})
+
+	t.Run("fails on an invalid rule", func(t *testing.T) {
+		_, err := NewEngineFromRules([]Rule{{Kind: "brand", Target: "acme", Percentage: 10}})
+
+		assert.ErrorIs(t, err, ErrInvalidRule)
+	})
+}
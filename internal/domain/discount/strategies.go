@@ -63,3 +63,29 @@ func (s *SKUDiscountStrategy) CalculatePercentage(p product.Product) int {
 func (s *SKUDiscountStrategy) AppliesToVariant(sku string) bool {
 	return s.sku == sku
 }
+
+// ExpressionDiscountStrategy applies discount to the products matching a condition.
+type ExpressionDiscountStrategy struct {
+	name       string
+	expression *Expression
+	percentage int
+}
+
+// NewExpressionDiscountStrategy creates a discount strategy for a named condition.
+func NewExpressionDiscountStrategy(name string, expression *Expression, percentage int) *ExpressionDiscountStrategy {
+	return &ExpressionDiscountStrategy{
+		name:       name,
+		expression: expression,
+		percentage: percentage,
+	}
+}
+
+// AppliesTo checks if the condition holds for the product.
+func (s *ExpressionDiscountStrategy) AppliesTo(p product.Product) bool {
+	return s.expression.Matches(p)
+}
+
+// CalculatePercentage returns the configured discount percentage.
+func (s *ExpressionDiscountStrategy) CalculatePercentage(p product.Product) int {
+	return s.percentage
+}
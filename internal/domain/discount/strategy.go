@@ -17,33 +17,79 @@ type Strategy interface {
 
 // Engine orchestrates multiple discount strategies.
 // It applies the first matching strategy (discounts are not stackable).
+// Each strategy may be restricted to an audience; strategies outside the
+// audience of the pricing context are skipped. The discount found is then
+// bounded by the guardrails of the engine.
 type Engine struct {
 	strategies []Strategy
+	audiences  []Audience
+	guardrails Guardrails
 }
 
-// NewEngine creates a discount engine with the given strategies.
-// Strategies are evaluated in order, first match wins.
+// NewEngine creates a discount engine with the given strategies, which apply
+// to every pricing context. Strategies are evaluated in order, first match wins.
 func NewEngine(strategies []Strategy) *Engine {
-	return &Engine{strategies: strategies}
+	return &Engine{strategies: strategies, audiences: make([]Audience, len(strategies))}
 }
 
-// ApplyDiscount calculates the discounted price for a product.
-// Returns the original price if no discount applies.
-func (e *Engine) ApplyDiscount(p product.Product) decimal.Decimal {
-	for _, strategy := range e.strategies {
-		if strategy.AppliesTo(p) {
-			percentage := strategy.CalculatePercentage(p)
-			discount := p.Price.Mul(decimal.NewFromInt(int64(percentage))).Div(decimal.NewFromInt(100))
-			return p.Price.Sub(discount)
+// NewEngineWithAudiences creates a discount engine where each strategy only
+// applies to the pricing contexts of the audience at the same index.
+func NewEngineWithAudiences(strategies []Strategy, audiences []Audience) *Engine {
+	return &Engine{strategies: strategies, audiences: audiences}
+}
+
+// WithGuardrails returns a copy of the engine whose discounts are bounded by g.
+func (e *Engine) WithGuardrails(g Guardrails) *Engine {
+	bounded := *e
+	bounded.guardrails = g
+	return &bounded
+}
+
+// applicable returns the strategies whose audience includes the pricing context, in order.
+func (e *Engine) applicable(pc PricingContext) []Strategy {
+	strategies := make([]Strategy, 0, len(e.strategies))
+	for i, strategy := range e.strategies {
+		if e.audiences[i].Matches(pc) {
+			strategies = append(strategies, strategy)
 		}
 	}
-	return p.Price
+	return strategies
+}
+
+// PriceProduct prices a product: the first matching strategy within the guardrails.
+func (e *Engine) PriceProduct(pc PricingContext, p product.Product) Price {
+	return e.guardrails.apply(p.Price, e.productPercentage(pc, p), p)
+}
+
+// PriceVariant prices a variant of product p: its SKU discount, or else the
+// discount of the product, within the guardrails.
+func (e *Engine) PriceVariant(pc PricingContext, v product.Variant, p product.Product) Price {
+	return e.guardrails.apply(v.Price, e.variantPercentage(pc, v.SKU, p), p)
+}
+
+// Floor returns the lowest unit price the guardrails let discounts take
+// products like p to, with the limit explaining it. The limit has no
+// Requested percentage; callers set the one they asked for.
+func (e *Engine) Floor(p product.Product) (decimal.Decimal, Limit) {
+	floor, limit := e.guardrails.floor(p, 0)
+	return floor, *limit
+}
+
+// ApplyDiscount calculates the discounted price for a product.
+// Returns the original price if no discount applies.
+func (e *Engine) ApplyDiscount(pc PricingContext, p product.Product) decimal.Decimal {
+	return e.PriceProduct(pc, p).Final
 }
 
 // GetDiscountPercentage returns the discount percentage for a product.
 // Returns 0 if no discount applies.
-func (e *Engine) GetDiscountPercentage(p product.Product) int {
-	for _, strategy := range e.strategies {
+func (e *Engine) GetDiscountPercentage(pc PricingContext, p product.Product) int {
+	return e.PriceProduct(pc, p).Percentage
+}
+
+// productPercentage returns the percentage of the first matching strategy.
+func (e *Engine) productPercentage(pc PricingContext, p product.Product) int {
+	for _, strategy := range e.applicable(pc) {
 		if strategy.AppliesTo(p) {
 			return strategy.CalculatePercentage(p)
 		}
@@ -51,21 +97,95 @@ func (e *Engine) GetDiscountPercentage(p product.Product) int {
 	return 0
 }
 
+// SaleCriteria describes the products hit by any non-zero discount strategy
+// of the pricing context, so that on-sale counts can be computed by the
+// persistence layer. Categories capped at zero are left out, also from the
+// conditions of expression strategies; price floors are not taken into account.
+func (e *Engine) SaleCriteria(pc PricingContext) product.SaleCriteria {
+	var criteria product.SaleCriteria
+	for _, strategy := range e.applicable(pc) {
+		switch s := strategy.(type) {
+		case *CategoryDiscountStrategy:
+			if s.percentage > 0 && !e.guardrails.blocks(s.categoryCode) {
+				criteria.CategoryCodes = append(criteria.CategoryCodes, s.categoryCode)
+			}
+		case *SKUDiscountStrategy:
+			if s.percentage > 0 {
+				criteria.SKUs = append(criteria.SKUs, s.sku)
+			}
+		case *ExpressionDiscountStrategy:
+			if s.percentage > 0 {
+				criteria.Conditions = append(criteria.Conditions, e.unblocked(s.expression.Condition()))
+			}
+		}
+	}
+	return criteria
+}
+
+// unblocked restricts a condition to the products of categories not capped at zero.
+func (e *Engine) unblocked(condition product.Condition) product.Condition {
+	blocked := e.guardrails.blocked()
+	if len(blocked) == 0 {
+		return condition
+	}
+	return product.Condition{Op: "and", Conditions: []product.Condition{
+		condition,
+		{Op: "not", Conditions: []product.Condition{{Op: "in", Field: "category", Values: blocked}}},
+	}}
+}
+
+// RequiredRelations returns the product relations the strategies of every
+// audience inspect:
+// category strategies need the category and SKU strategies need the variants.
+// Expression strategies need the category when their condition tests it.
+// Category caps need the category too.
+// Unknown strategies are assumed to need every relation.
+func (e *Engine) RequiredRelations() product.Relations {
+	relations := product.Relations{Category: len(e.guardrails.Caps) > 0}
+	for _, strategy := range e.strategies {
+		switch s := strategy.(type) {
+		case *CategoryDiscountStrategy:
+			relations.Category = true
+		case *SKUDiscountStrategy:
+			relations.Variants = true
+		case *ExpressionDiscountStrategy:
+			relations = relations.Union(s.expression.Relations())
+		default:
+			return product.AllRelations()
+		}
+	}
+	return relations
+}
+
 // GetVariantDiscountPercentage returns the discount percentage for a specific variant SKU.
 // First checks SKU-specific discounts, then falls back to category discount.
 // Returns 0 if no discount applies.
-func (e *Engine) GetVariantDiscountPercentage(sku string, p product.Product) int {
+func (e *Engine) GetVariantDiscountPercentage(pc PricingContext, sku string, p product.Product) int {
+	variant := product.Variant{SKU: sku, Price: p.Price}
+	for _, v := range p.Variants {
+		if v.SKU == sku {
+			variant = v
+		}
+	}
+	return e.PriceVariant(pc, variant, p).Percentage
+}
+
+// variantPercentage returns the percentage of the first matching SKU strategy,
+// or else of the first category or expression strategy matching the product.
+func (e *Engine) variantPercentage(pc PricingContext, sku string, p product.Product) int {
+	strategies := e.applicable(pc)
 	// First check if there's a SKU-specific discount
-	for _, strategy := range e.strategies {
+	for _, strategy := range strategies {
 		if skuStrategy, ok := strategy.(*SKUDiscountStrategy); ok {
 			if skuStrategy.AppliesToVariant(sku) {
 				return skuStrategy.CalculatePercentage(p)
 			}
 		}
 	}
-	// Fall back to category discount
-	for _, strategy := range e.strategies {
-		if _, ok := strategy.(*CategoryDiscountStrategy); ok {
+	// Fall back to category and expression discounts
+	for _, strategy := range strategies {
+		switch strategy.(type) {
+		case *CategoryDiscountStrategy, *ExpressionDiscountStrategy:
 			if strategy.AppliesTo(p) {
 				return strategy.CalculatePercentage(p)
 			}
@@ -101,8 +101,8 @@ func TestEngine(t *testing.T) {
 			},
 		}
 
-		discountedPrice := engine.ApplyDiscount(prod)
-		percentage := engine.GetDiscountPercentage(prod)
+		discountedPrice := engine.ApplyDiscount(PricingContext{}, prod)
+		percentage := engine.GetDiscountPercentage(PricingContext{}, prod)
 
 		assert.Equal(t, "70", discountedPrice.String())
 		assert.Equal(t, 30, percentage)
@@ -123,8 +123,8 @@ func TestEngine(t *testing.T) {
 			},
 		}
 
-		discountedPrice := engine.ApplyDiscount(prod)
-		percentage := engine.GetDiscountPercentage(prod)
+		discountedPrice := engine.ApplyDiscount(PricingContext{}, prod)
+		percentage := engine.GetDiscountPercentage(PricingContext{}, prod)
 
 		assert.Equal(t, "100", discountedPrice.String())
 		assert.Equal(t, 0, percentage)
@@ -146,8 +146,8 @@ func TestEngine(t *testing.T) {
 			},
 		}
 
-		discountedPrice := engine.ApplyDiscount(prod)
-		percentage := engine.GetDiscountPercentage(prod)
+		discountedPrice := engine.ApplyDiscount(PricingContext{}, prod)
+		percentage := engine.GetDiscountPercentage(PricingContext{}, prod)
 
 		assert.Equal(t, "85", discountedPrice.String())
 		assert.Equal(t, 15, percentage)
@@ -167,7 +167,7 @@ func TestEngine(t *testing.T) {
 			},
 		}
 
-		discountedPrice := engine.ApplyDiscount(prod)
+		discountedPrice := engine.ApplyDiscount(PricingContext{}, prod)
 
 		// 89.99 - 30% = 62.993 ~= 62.99
 		assert.True(t, discountedPrice.LessThan(decimal.NewFromFloat(63.0)))
@@ -182,10 +182,64 @@ func TestEngine(t *testing.T) {
 			Price: decimal.NewFromFloat(100.0),
 		}
 
-		discountedPrice := engine.ApplyDiscount(prod)
-		percentage := engine.GetDiscountPercentage(prod)
+		discountedPrice := engine.ApplyDiscount(PricingContext{}, prod)
+		percentage := engine.GetDiscountPercentage(PricingContext{}, prod)
 
 		assert.Equal(t, "100", discountedPrice.String())
 		assert.Equal(t, 0, percentage)
 	})
 }
+
+func TestEngine_SaleCriteria(t *testing.T) {
+	t.Run("collects categories and SKUs of non-zero strategies", func(t *testing.T) {
+		engine := NewEngine([]Strategy{
+			NewCategoryDiscountStrategy("boots", 30),
+			NewSKUDiscountStrategy("000003", 15),
+			NewCategoryDiscountStrategy("shoes", 0),
+		})
+
+		criteria := engine.SaleCriteria(PricingContext{})
+
+		assert.Equal(t, []string{"boots"}, criteria.CategoryCodes)
+		assert.Equal(t, []string{"000003"}, criteria.SKUs)
+	})
+
+	t.Run("is empty without strategies", func(t *testing.T) {
+		engine := NewEngine([]Strategy{})
+
+		assert.True(t, engine.SaleCriteria(PricingContext{}).IsEmpty())
+	})
+
+	t.Run("only counts the strategies of the pricing context", func(t *testing.T) {
+		engine := NewEngineWithAudiences(
+			[]Strategy{NewCategoryDiscountStrategy("boots", 30), NewSKUDiscountStrategy("000003", 15)},
+			[]Audience{{Segments: []string{"employee"}}, {}},
+		)
+
+		assert.Equal(t, []string{"boots"}, engine.SaleCriteria(PricingContext{Segment: "employee"}).CategoryCodes)
+		assert.Empty(t, engine.SaleCriteria(PricingContext{}).CategoryCodes)
+	})
+}
+
+func TestEngine_RequiredRelations(t *testing.T) {
+	t.Run("requires relations inspected by the strategies", func(t *testing.T) {
+		engine := NewEngine([]Strategy{
+			NewCategoryDiscountStrategy("boots", 30),
+			NewSKUDiscountStrategy("000003", 15),
+		})
+
+		assert.Equal(t, product.AllRelations(), engine.RequiredRelations())
+	})
+
+	t.Run("requires only the category for category strategies", func(t *testing.T) {
+		engine := NewEngine([]Strategy{NewCategoryDiscountStrategy("boots", 30)})
+
+		assert.Equal(t, product.Relations{Category: true}, engine.RequiredRelations())
+	})
+
+	t.Run("requires nothing without strategies", func(t *testing.T) {
+		engine := NewEngine([]Strategy{})
+
+		assert.Equal(t, product.Relations{}, engine.RequiredRelations())
+	})
+}
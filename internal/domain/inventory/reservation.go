@@ -0,0 +1,103 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Reservation errors.
+var (
+	ErrInvalidReservation  = errors.New("invalid reservation")
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationClosed   = errors.New("reservation is no longer pending")
+)
+
+// Status is the state of a reservation. Only pending reservations hold units
+// that can still be confirmed or released.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusConfirmed Status = "confirmed"
+	StatusCanceled  Status = "canceled"
+	StatusExpired   Status = "expired"
+)
+
+// Line is the quantity of one variant held by a reservation, with the
+// warehouses the units were taken from.
+type Line struct {
+	SKU      string
+	Quantity int
+	Levels   []Level
+}
+
+// Reservation holds units of several variants until it is confirmed,
+// canceled or expires.
+type Reservation struct {
+	ID        uint
+	Status    Status
+	Lines     []Line
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// Expired reports whether a pending reservation is past its expiry at now.
+func (r Reservation) Expired(now time.Time) bool {
+	return r.Status == StatusPending && !now.Before(r.ExpiresAt)
+}
+
+// ValidateLines checks that a reservation request names each SKU once with a
+// positive quantity.
+func ValidateLines(lines []Line) error {
+	if len(lines) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidReservation)
+	}
+
+	seen := make(map[string]bool, len(lines))
+	for _, l := range lines {
+		if l.SKU == "" {
+			return fmt.Errorf("%w: sku is required", ErrInvalidReservation)
+		}
+		if seen[l.SKU] {
+			return fmt.Errorf("%w: sku %s is listed twice", ErrInvalidReservation, l.SKU)
+		}
+		seen[l.SKU] = true
+		if l.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity of %s must be greater than 0", ErrInvalidReservation, l.SKU)
+		}
+	}
+	return nil
+}
+
+// Allocate takes quantity units from levels, emptying the fullest warehouses
+// first so that a reservation touches as few warehouses as possible. Ties are
+// broken by warehouse name. It returns ErrInsufficientStock when the levels
+// hold fewer units than requested.
+func Allocate(sku string, levels []Level, quantity int) ([]Level, error) {
+	stock := Stock{SKU: sku, Levels: levels}
+	if available := stock.Quantity(); available < quantity {
+		return nil, fmt.Errorf("%w: %s has %d available, %d requested", ErrInsufficientStock, sku, available, quantity)
+	}
+
+	sorted := stock.Sorted().Levels
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Quantity > sorted[j].Quantity
+	})
+
+	var taken []Level
+	for _, l := range sorted {
+		if quantity == 0 {
+			break
+		}
+		n := min(l.Quantity, quantity)
+		if n == 0 {
+			continue
+		}
+		taken = append(taken, Level{Warehouse: l.Warehouse, Quantity: n})
+		quantity -= n
+	}
+	return taken, nil
+}
@@ -0,0 +1,60 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLines(t *testing.T) {
+	t.Run("accepts distinct skus with positive quantities", func(t *testing.T) {
+		assert.NoError(t, ValidateLines([]Line{{SKU: "000003", Quantity: 1}, {SKU: "SKU009B", Quantity: 2}}))
+	})
+
+	t.Run("rejects invalid lines", func(t *testing.T) {
+		tests := map[string][]Line{
+			"no lines":      nil,
+			"missing sku":   {{Quantity: 1}},
+			"zero quantity": {{SKU: "000003"}},
+			"repeated sku":  {{SKU: "000003", Quantity: 1}, {SKU: "000003", Quantity: 1}},
+		}
+		for name, lines := range tests {
+			assert.ErrorIs(t, ValidateLines(lines), ErrInvalidReservation, name)
+		}
+	})
+}
+
+func TestAllocate(t *testing.T) {
+	levels := []Level{{Warehouse: "madrid", Quantity: 2}, {Warehouse: "berlin", Quantity: 5}, {Warehouse: "paris", Quantity: 2}}
+
+	t.Run("takes from the fullest warehouse first", func(t *testing.T) {
+		taken, err := Allocate("000003", levels, 4)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Level{{Warehouse: "berlin", Quantity: 4}}, taken)
+	})
+
+	t.Run("spreads over warehouses, ties by name", func(t *testing.T) {
+		taken, err := Allocate("000003", levels, 8)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Level{{Warehouse: "berlin", Quantity: 5}, {Warehouse: "madrid", Quantity: 2}, {Warehouse: "paris", Quantity: 1}}, taken)
+	})
+
+	t.Run("rejects quantities over the stock", func(t *testing.T) {
+		_, err := Allocate("000003", levels, 10)
+
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+		assert.ErrorContains(t, err, "000003 has 9 available, 10 requested")
+	})
+}
+
+func TestReservation_Expired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, Reservation{Status: StatusPending, ExpiresAt: now}.Expired(now))
+	assert.False(t, Reservation{Status: StatusPending, ExpiresAt: now.Add(time.Second)}.Expired(now))
+	assert.False(t, Reservation{Status: StatusConfirmed, ExpiresAt: now}.Expired(now))
+}
@@ -0,0 +1,78 @@
+// Package inventory tracks how many units of each variant can be sold.
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// DefaultWarehouse holds the stock of variants not tracked per warehouse.
+const DefaultWarehouse = "default"
+
+// maxWarehouseLength is the size of the warehouse column.
+const maxWarehouseLength = 32
+
+// Stock errors.
+var (
+	ErrInvalidStock  = errors.New("invalid stock")
+	ErrReservedStock = errors.New("stock is held by pending reservations")
+)
+
+// Level is the quantity of a variant held in one warehouse.
+type Level struct {
+	Warehouse string
+	Quantity  int
+}
+
+// Stock is the quantity of a variant across warehouses.
+type Stock struct {
+	SKU    string
+	Levels []Level
+}
+
+// Quantity returns the units held across all warehouses.
+func (s Stock) Quantity() int {
+	total := 0
+	for _, l := range s.Levels {
+		total += l.Quantity
+	}
+	return total
+}
+
+// Available reports whether at least one unit can be sold.
+func (s Stock) Available() bool {
+	return s.Quantity() > 0
+}
+
+// Validate checks that every level names a distinct warehouse and holds a
+// non-negative quantity. No levels at all means the variant is out of stock.
+func (s Stock) Validate() error {
+	if s.SKU == "" {
+		return fmt.Errorf("%w: sku is required", ErrInvalidStock)
+	}
+
+	seen := make(map[string]bool, len(s.Levels))
+	for _, l := range s.Levels {
+		if l.Warehouse == "" || len(l.Warehouse) > maxWarehouseLength {
+			return fmt.Errorf("%w: warehouse must have 1 to %d characters", ErrInvalidStock, maxWarehouseLength)
+		}
+		if seen[l.Warehouse] {
+			return fmt.Errorf("%w: warehouse %s is listed twice", ErrInvalidStock, l.Warehouse)
+		}
+		seen[l.Warehouse] = true
+		if l.Quantity < 0 {
+			return fmt.Errorf("%w: quantity in warehouse %s must not be negative", ErrInvalidStock, l.Warehouse)
+		}
+	}
+	return nil
+}
+
+// Sorted returns a copy of the stock with levels ordered by warehouse.
+func (s Stock) Sorted() Stock {
+	levels := append([]Level(nil), s.Levels...)
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Warehouse < levels[j].Warehouse
+	})
+	return Stock{SKU: s.SKU, Levels: levels}
+}
@@ -0,0 +1,53 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStock_Quantity(t *testing.T) {
+	t.Run("sums the levels of every warehouse", func(t *testing.T) {
+		stock := Stock{SKU: "000003", Levels: []Level{{Warehouse: "berlin", Quantity: 2}, {Warehouse: "madrid", Quantity: 3}}}
+
+		assert.Equal(t, 5, stock.Quantity())
+		assert.True(t, stock.Available())
+	})
+
+	t.Run("is not available without units", func(t *testing.T) {
+		assert.False(t, Stock{SKU: "000003"}.Available())
+		assert.False(t, Stock{SKU: "000003", Levels: []Level{{Warehouse: DefaultWarehouse}}}.Available())
+	})
+}
+
+func TestStock_Validate(t *testing.T) {
+	t.Run("accepts levels and empty stock", func(t *testing.T) {
+		assert.NoError(t, Stock{SKU: "000003"}.Validate())
+		assert.NoError(t, Stock{SKU: "000003", Levels: []Level{{Warehouse: DefaultWarehouse, Quantity: 0}}}.Validate())
+	})
+
+	t.Run("rejects invalid stock", func(t *testing.T) {
+		tests := map[string]Stock{
+			"missing sku":        {Levels: []Level{{Warehouse: DefaultWarehouse, Quantity: 1}}},
+			"empty warehouse":    {SKU: "000003", Levels: []Level{{Quantity: 1}}},
+			"long warehouse":     {SKU: "000003", Levels: []Level{{Warehouse: strings.Repeat("w", 33), Quantity: 1}}},
+			"negative quantity":  {SKU: "000003", Levels: []Level{{Warehouse: DefaultWarehouse, Quantity: -1}}},
+			"repeated warehouse": {SKU: "000003", Levels: []Level{{Warehouse: "berlin"}, {Warehouse: "berlin"}}},
+		}
+		for name, stock := range tests {
+			assert.ErrorIs(t, stock.Validate(), ErrInvalidStock, name)
+		}
+	})
+}
+
+func TestStock_Sorted(t *testing.T) {
+	t.Run("orders levels by warehouse without changing the original", func(t *testing.T) {
+		stock := Stock{SKU: "000003", Levels: []Level{{Warehouse: "madrid"}, {Warehouse: "berlin"}}}
+
+		sorted := stock.Sorted()
+
+		assert.Equal(t, "berlin", sorted.Levels[0].Warehouse)
+		assert.Equal(t, "madrid", stock.Levels[0].Warehouse)
+	})
+}
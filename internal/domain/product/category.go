@@ -0,0 +1,26 @@
+package product
+
+// Sort keys accepted by the category listing.
+const (
+	CategorySortName     = "name"
+	CategorySortProducts = "productCount"
+	CategorySortOnSale   = "onSaleCount"
+)
+
+// CategoryQuery selects a page of category summaries.
+// A zero Limit returns every category; an empty Sort orders by name.
+type CategoryQuery struct {
+	Offset int
+	Limit  int
+	Sort   string
+	Desc   bool
+	Sale   SaleCriteria
+}
+
+// CategorySummary is a category together with the number of products it holds
+// and how many of those are on sale.
+type CategorySummary struct {
+	Category
+	ProductCount int64
+	OnSaleCount  int64
+}
@@ -1,31 +1,59 @@
 package product
 
 import (
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
-// Product represents a product in the catalog.
+// Product represents a product in the catalog. CostPrice is what the product
+// costs the business, zero when unknown; it is shared by its variants.
 type Product struct {
 	ID         uint
 	Code       string
 	Price      decimal.Decimal
+	CostPrice  decimal.Decimal
 	CategoryID *uint
 	Category   *Category
 	Variants   []Variant
+	UpdatedAt  time.Time
 }
 
 // Category represents a product category.
 type Category struct {
-	ID   uint
-	Code string
-	Name string
+	ID        uint
+	Code      string
+	Name      string
+	UpdatedAt time.Time
 }
 
 // Variant represents a product variant with optional pricing.
+// Quantity is the stock held across all warehouses.
 type Variant struct {
 	ID        uint
 	ProductID uint
 	Name      string
 	SKU       string
 	Price     decimal.Decimal
+	Quantity  int
+	UpdatedAt time.Time
+}
+
+// Available reports whether the variant has stock to sell.
+func (v Variant) Available() bool {
+	return v.Quantity > 0
+}
+
+// LastModified returns the latest update time of the product and its loaded relations.
+func (p Product) LastModified() time.Time {
+	modified := p.UpdatedAt
+	if p.Category != nil && p.Category.UpdatedAt.After(modified) {
+		modified = p.Category.UpdatedAt
+	}
+	for _, v := range p.Variants {
+		if v.UpdatedAt.After(modified) {
+			modified = v.UpdatedAt
+		}
+	}
+	return modified
 }
@@ -0,0 +1,46 @@
+package product
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProduct_LastModified(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("returns the product update time without relations", func(t *testing.T) {
+		p := Product{UpdatedAt: base}
+
+		assert.Equal(t, base, p.LastModified())
+	})
+
+	t.Run("returns the latest update among product, category and variants", func(t *testing.T) {
+		p := Product{
+			UpdatedAt: base,
+			Category:  &Category{UpdatedAt: base.Add(time.Hour)},
+			Variants: []Variant{
+				{UpdatedAt: base.Add(2 * time.Hour)},
+				{UpdatedAt: base.Add(-time.Hour)},
+			},
+		}
+
+		assert.Equal(t, base.Add(2*time.Hour), p.LastModified())
+	})
+}
+
+func TestChange_String(t *testing.T) {
+	t.Run("names every change", func(t *testing.T) {
+		assert.Equal(t, "unchanged", Unchanged.String())
+		assert.Equal(t, "created", Created.String())
+		assert.Equal(t, "updated", Updated.String())
+	})
+}
+
+func TestVariant_Available(t *testing.T) {
+	t.Run("is available with stock", func(t *testing.T) {
+		assert.True(t, Variant{Quantity: 1}.Available())
+		assert.False(t, Variant{}.Available())
+	})
+}
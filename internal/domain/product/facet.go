@@ -0,0 +1,95 @@
+package product
+
+import "github.com/shopspring/decimal"
+
+// Facet names accepted by the catalog.
+const (
+	FacetCategory = "category"
+	FacetPrice    = "price"
+	FacetOnSale   = "onSale"
+)
+
+// FacetRequest selects which facets should be computed.
+type FacetRequest struct {
+	Category bool
+	Price    bool
+	OnSale   bool
+}
+
+// Any reports whether at least one facet was requested.
+func (r FacetRequest) Any() bool {
+	return r.Category || r.Price || r.OnSale
+}
+
+// PriceBucket is a half-open price range [Min, Max). A nil Max means unbounded.
+type PriceBucket struct {
+	Min decimal.Decimal
+	Max *decimal.Decimal
+}
+
+// DefaultPriceBuckets returns the price ranges used for the price facet.
+func DefaultPriceBuckets() []PriceBucket {
+	bounds := []int64{0, 25, 50, 100, 250}
+	buckets := make([]PriceBucket, len(bounds))
+	for i, b := range bounds {
+		buckets[i] = PriceBucket{Min: decimal.NewFromInt(b)}
+		if i+1 < len(bounds) {
+			upper := decimal.NewFromInt(bounds[i+1])
+			buckets[i].Max = &upper
+		}
+	}
+	return buckets
+}
+
+// SaleCriteria describes which products are on sale in terms the persistence
+// layer can evaluate: products in any of the categories, whose code or
+// variant SKUs match any of the SKUs, or that match any of the conditions.
+type SaleCriteria struct {
+	CategoryCodes []string
+	SKUs          []string
+	Conditions    []Condition
+}
+
+// IsEmpty reports whether no product can match the criteria.
+func (c SaleCriteria) IsEmpty() bool {
+	return len(c.CategoryCodes) == 0 && len(c.SKUs) == 0 && len(c.Conditions) == 0
+}
+
+// Condition is a condition over the category code, code and price of a
+// product. Op is "and" or "or" over Conditions, "not" over the only one, or
+// compares Field with Values, for category and code, or Numbers, for price:
+// "==", "!=", "<", "<=", ">", ">=", "in", "startsWith", "endsWith" or
+// "contains". Products without a category have an empty category code.
+type Condition struct {
+	Op         string
+	Field      string
+	Values     []string
+	Numbers    []decimal.Decimal
+	Conditions []Condition
+}
+
+// Facets holds facet counts for a filtered product set.
+// Each facet is nil when it was not requested.
+type Facets struct {
+	Categories   []CategoryCount
+	PriceBuckets []PriceBucketCount
+	OnSale       *OnSaleCount
+}
+
+// CategoryCount is the number of products in a category.
+type CategoryCount struct {
+	Code  string
+	Count int64
+}
+
+// PriceBucketCount is the number of products within a price bucket.
+type PriceBucketCount struct {
+	Bucket PriceBucket
+	Count  int64
+}
+
+// OnSaleCount splits products by whether a discount applies to them.
+type OnSaleCount struct {
+	OnSale    int64
+	NotOnSale int64
+}
@@ -3,7 +3,9 @@ package product
 import "github.com/shopspring/decimal"
 
 // Filter contains information for filtering products.
+// InStock keeps only products with at least one available variant.
 type Filter struct {
 	Category      string
 	PriceLessThan *decimal.Decimal
+	InStock       bool
 }
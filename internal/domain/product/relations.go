@@ -0,0 +1,20 @@
+package product
+
+// Relations selects which relations are loaded together with a product.
+type Relations struct {
+	Category bool
+	Variants bool
+}
+
+// AllRelations selects every product relation.
+func AllRelations() Relations {
+	return Relations{Category: true, Variants: true}
+}
+
+// Union returns the relations selected by either r or other.
+func (r Relations) Union(other Relations) Relations {
+	return Relations{
+		Category: r.Category || other.Category,
+		Variants: r.Variants || other.Variants,
+	}
+}
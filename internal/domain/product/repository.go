@@ -1 +1,27 @@
 package product
+
+import "errors"
+
+// ErrNotFound is returned by repositories when the requested record does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Change tells what an upsert did to the stored record.
+type Change int
+
+const (
+	Unchanged Change = iota
+	Created
+	Updated
+)
+
+// String names the change as it appears in reports.
+func (c Change) String() string {
+	switch c {
+	case Created:
+		return "created"
+	case Updated:
+		return "updated"
+	default:
+		return "unchanged"
+	}
+}
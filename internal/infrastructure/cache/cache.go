@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Stats are the cache counters since the process started.
+// Evictions and Entries are only reported by stores that track them.
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// sizedStore is implemented by stores that can report their size.
+type sizedStore interface {
+	Len() int
+	Evictions() uint64
+}
+
+// Cache reads through a Store, loading each missing key once for all concurrent callers.
+// Store failures are logged and treated as misses, so the cache never fails a read.
+type Cache struct {
+	store Store
+	ttl   time.Duration
+
+	mu         sync.Mutex
+	generation uint64
+
+	group singleflight.Group
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// New creates a cache whose entries live for ttl in the store.
+func New(store Store, ttl time.Duration) *Cache {
+	return &Cache{store: store, ttl: ttl}
+}
+
+// Invalidate drops every entry. Loads that started before the call are not stored.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	c.generation++
+	c.mu.Unlock()
+
+	if err := c.store.Invalidate(); err != nil {
+		log.Printf("cache: invalidating store failed: %s", err)
+	}
+}
+
+// Stats returns the cache counters.
+func (c *Cache) Stats() Stats {
+	stats := Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+	if s, ok := c.store.(sizedStore); ok {
+		stats.Evictions = s.Evictions()
+		stats.Entries = s.Len()
+	}
+	return stats
+}
+
+func (c *Cache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// load returns the value cached under key, or runs fetch once for all concurrent
+// misses on it and caches the result. Errors are not cached.
+//
+// The shared fetch runs with the context of the caller that started it; callers
+// whose own context is still live retry on their own when that context ends first.
+func load[T any](ctx context.Context, c *Cache, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	if value, ok := lookup[T](c, key); ok {
+		c.hits.Add(1)
+		return value, nil
+	}
+	c.misses.Add(1)
+
+	generation := c.currentGeneration()
+	value, err, _ := c.group.Do(fmt.Sprintf("%d:%s", generation, key), func() (any, error) {
+		// A load that finished just before this one started has already stored the entry.
+		if value, ok := lookup[T](c, key); ok {
+			return value, nil
+		}
+
+		value, err := fetch(ctx)
+		if err != nil {
+			return value, err
+		}
+
+		if c.currentGeneration() == generation {
+			c.put(key, value)
+		}
+		return value, nil
+	})
+	if err != nil && ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return fetch(ctx)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value.(T), nil
+}
+
+func lookup[T any](c *Cache, key string) (T, bool) {
+	var value T
+
+	data, ok, err := c.store.Get(key)
+	if err != nil {
+		log.Printf("cache: reading %s failed: %s", key, err)
+		return value, false
+	}
+	if !ok {
+		return value, false
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		log.Printf("cache: decoding %s failed: %s", key, err)
+		return value, false
+	}
+	return value, true
+}
+
+func (c *Cache) put(key string, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("cache: encoding %s failed: %s", key, err)
+		return
+	}
+
+	if err := c.store.Set(key, data, c.ttl); err != nil {
+		log.Printf("cache: writing %s failed: %s", key, err)
+	}
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingStore struct{}
+
+func (failingStore) Get(string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failingStore) Set(string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingStore) Invalidate() error {
+	return errors.New("connection refused")
+}
+
+func TestCache_Load(t *testing.T) {
+	t.Run("counts hits and misses", func(t *testing.T) {
+		cache := newTestCache()
+		var calls int
+		fetch := func(context.Context) (string, error) {
+			calls++
+			return "value", nil
+		}
+
+		first, err := load(context.Background(), cache, "key", fetch)
+		require.NoError(t, err)
+		second, err := load(context.Background(), cache, "key", fetch)
+		require.NoError(t, err)
+
+		assert.Equal(t, "value", first)
+		assert.Equal(t, "value", second)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, Stats{Hits: 1, Misses: 1, Entries: 1}, cache.Stats())
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		cache := newTestCache()
+
+		_, err := load(context.Background(), cache, "key", func(context.Context) (string, error) { return "", errors.New("database error") })
+		assert.EqualError(t, err, "database error")
+
+		value, err := load(context.Background(), cache, "key", func(context.Context) (string, error) { return "value", nil })
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("falls back to the loader when the store fails", func(t *testing.T) {
+		cache := New(failingStore{}, time.Minute)
+
+		value, err := load(context.Background(), cache, "key", func(context.Context) (string, error) { return "value", nil })
+
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+		cache.Invalidate()
+	})
+
+	t.Run("loads concurrent misses once", func(t *testing.T) {
+		cache := newTestCache()
+		release := make(chan struct{})
+		var calls atomic.Int32
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := load(context.Background(), cache, "key", func(context.Context) (string, error) {
+					calls.Add(1)
+					<-release
+					return "value", nil
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, "value", value)
+			}()
+		}
+
+		require.Eventually(t, func() bool { return cache.Stats().Misses == 10 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	t.Run("drops every entry", func(t *testing.T) {
+		cache := newTestCache()
+		_, _ = load(context.Background(), cache, "a", func(context.Context) (string, error) { return "a", nil })
+		_, _ = load(context.Background(), cache, "b", func(context.Context) (string, error) { return "b", nil })
+
+		cache.Invalidate()
+
+		assert.Equal(t, 0, cache.Stats().Entries)
+	})
+
+	t.Run("does not store loads started before the invalidation", func(t *testing.T) {
+		cache := newTestCache()
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = load(context.Background(), cache, "key", func(context.Context) (string, error) {
+				close(started)
+				<-release
+				return "stale", nil
+			})
+		}()
+
+		<-started
+		cache.Invalidate()
+		close(release)
+		<-done
+
+		assert.Equal(t, 0, cache.Stats().Entries)
+	})
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/category"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
+
+// categoryPage is the cached result of GetAll.
+type categoryPage struct {
+	Categories []product.CategorySummary
+	Total      int64
+}
+
+// CategoryRepository is a read-through cache in front of a category.Repository.
+// It caches the category listing; writes go straight to the wrapped repository.
+type CategoryRepository struct {
+	next  category.Repository
+	cache *Cache
+}
+
+// NewCategoryRepository wraps next with the cache.
+func NewCategoryRepository(next category.Repository, cache *Cache) *CategoryRepository {
+	return &CategoryRepository{next: next, cache: cache}
+}
+
+// GetAll returns a cached page of category summaries, loading it on a miss.
+// Entries are keyed by the whole query, including every sale criterion.
+func (r *CategoryRepository) GetAll(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error) {
+	sale, err := json.Marshal(query.Sale)
+	if err != nil {
+		return nil, 0, err
+	}
+	key := fmt.Sprintf("categories:%d:%d:%s:%t:%s",
+		query.Offset, query.Limit, strconv.Quote(query.Sort), query.Desc, sale)
+
+	page, err := load(ctx, r.cache, key, func(ctx context.Context) (categoryPage, error) {
+		categories, total, err := r.next.GetAll(ctx, query)
+		return categoryPage{Categories: categories, Total: total}, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return page.Categories, page.Total, nil
+}
+
+// Create creates a category with the wrapped repository.
+func (r *CategoryRepository) Create(ctx context.Context, cat product.Category) (*product.Category, error) {
+	return r.next.Create(ctx, cat)
+}
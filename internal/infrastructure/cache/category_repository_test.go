@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCategoryRepository struct {
+	summaries []product.CategorySummary
+	calls     int
+}
+
+func (m *mockCategoryRepository) GetAll(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error) {
+	m.calls++
+	return m.summaries, int64(len(m.summaries)), nil
+}
+
+func (m *mockCategoryRepository) Create(ctx context.Context, cat product.Category) (*product.Category, error) {
+	m.calls++
+	return &cat, nil
+}
+
+func TestCategoryRepository_GetAll(t *testing.T) {
+	t.Run("serves repeated queries from the cache", func(t *testing.T) {
+		next := &mockCategoryRepository{
+			summaries: []product.CategorySummary{
+				{Category: product.Category{ID: 1, Code: "boots", Name: "Boots"}, ProductCount: 4, OnSaleCount: 4},
+			},
+		}
+		repo := NewCategoryRepository(next, newTestCache())
+		query := product.CategoryQuery{Limit: 10, Sort: product.CategorySortName}
+
+		summaries, total, err := repo.GetAll(context.Background(), query)
+		require.NoError(t, err)
+		cached, cachedTotal, err := repo.GetAll(context.Background(), query)
+		require.NoError(t, err)
+
+		assert.Equal(t, summaries, cached)
+		assert.Equal(t, total, cachedTotal)
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("keys entries by the whole query", func(t *testing.T) {
+		next := &mockCategoryRepository{}
+		repo := NewCategoryRepository(next, newTestCache())
+
+		queries := []product.CategoryQuery{
+			{Limit: 10},
+			{Limit: 10, Offset: 10},
+			{Limit: 10, Sort: product.CategorySortProducts},
+			{Limit: 10, Sort: product.CategorySortProducts, Desc: true},
+			{Limit: 10, Sale: product.SaleCriteria{CategoryCodes: []string{"boots"}}},
+			{Limit: 10, Sale: product.SaleCriteria{SKUs: []string{"boots"}}},
+			{Limit: 10, Sale: product.SaleCriteria{SKUs: []string{"boots"}, Conditions: []product.Condition{
+				{Op: ">", Field: "price", Numbers: []decimal.Decimal{decimal.NewFromInt(20)}},
+			}}},
+			{Limit: 10, Sale: product.SaleCriteria{SKUs: []string{"boots"}, Conditions: []product.Condition{
+				{Op: "==", Field: "category", Values: []string{"boots"}},
+			}}},
+		}
+		for _, q := range queries {
+			_, _, err := repo.GetAll(context.Background(), q)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, len(queries), next.calls)
+	})
+
+	t.Run("passes writes through", func(t *testing.T) {
+		next := &mockCategoryRepository{}
+		repo := NewCategoryRepository(next, newTestCache())
+
+		created, err := repo.Create(context.Background(), product.Category{Code: "kids", Name: "Kids"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "kids", created.Code)
+		assert.Equal(t, 1, next.calls)
+	})
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// MemoryStore is an in-process Store bounded in size, evicting the least
+// recently used entry first.
+type MemoryStore struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*list.Element
+	lru       *list.List
+	evictions uint64
+}
+
+// NewMemoryStore creates an in-process store holding at most maxEntries entries.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Get returns the value stored under key unless it expired.
+func (s *MemoryStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	e := elem.Value.(*memoryEntry)
+	if !s.now().Before(e.expires) {
+		s.lru.Remove(elem)
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+
+	s.lru.MoveToFront(elem)
+	return e.value, true, nil
+}
+
+// Set stores value under key for ttl, evicting the least recently used entries over the bound.
+func (s *MemoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxEntries <= 0 {
+		return nil
+	}
+
+	e := &memoryEntry{key: key, value: value, expires: s.now().Add(ttl)}
+	if elem, ok := s.entries[key]; ok {
+		elem.Value = e
+		s.lru.MoveToFront(elem)
+		return nil
+	}
+
+	s.entries[key] = s.lru.PushFront(e)
+	for s.lru.Len() > s.maxEntries {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).key)
+		s.evictions++
+	}
+	return nil
+}
+
+// Invalidate drops every entry.
+func (s *MemoryStore) Invalidate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]*list.Element)
+	s.lru.Init()
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet dropped.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// Evictions returns the number of entries evicted to respect the size bound.
+func (s *MemoryStore) Evictions() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.evictions
+}
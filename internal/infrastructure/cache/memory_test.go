@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Run("expires entries after the TTL", func(t *testing.T) {
+		store := NewMemoryStore(10)
+		now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		store.now = func() time.Time { return now }
+
+		require.NoError(t, store.Set("key", []byte("value"), time.Minute))
+
+		now = now.Add(59 * time.Second)
+		value, ok, err := store.Get("key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []byte("value"), value)
+
+		now = now.Add(time.Second)
+		_, ok, err = store.Get("key")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, 0, store.Len())
+	})
+
+	t.Run("evicts the least recently used entry", func(t *testing.T) {
+		store := NewMemoryStore(2)
+
+		require.NoError(t, store.Set("a", []byte("a"), time.Minute))
+		require.NoError(t, store.Set("b", []byte("b"), time.Minute))
+		_, _, _ = store.Get("a")
+		require.NoError(t, store.Set("c", []byte("c"), time.Minute))
+
+		_, okA, _ := store.Get("a")
+		_, okB, _ := store.Get("b")
+		_, okC, _ := store.Get("c")
+		assert.True(t, okA)
+		assert.False(t, okB)
+		assert.True(t, okC)
+		assert.Equal(t, uint64(1), store.Evictions())
+		assert.Equal(t, 2, store.Len())
+	})
+
+	t.Run("replaces existing entries without evicting", func(t *testing.T) {
+		store := NewMemoryStore(1)
+
+		require.NoError(t, store.Set("a", []byte("old"), time.Minute))
+		require.NoError(t, store.Set("a", []byte("new"), time.Minute))
+
+		value, ok, _ := store.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("new"), value)
+		assert.Equal(t, uint64(0), store.Evictions())
+	})
+
+	t.Run("drops every entry on invalidation", func(t *testing.T) {
+		store := NewMemoryStore(10)
+		require.NoError(t, store.Set("a", []byte("a"), time.Minute))
+
+		require.NoError(t, store.Invalidate())
+
+		_, ok, _ := store.Get("a")
+		assert.False(t, ok)
+	})
+}
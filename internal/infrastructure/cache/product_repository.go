@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
+
+// filteredPage is the cached result of GetFiltered.
+type filteredPage struct {
+	Products []product.Product
+	Total    int64
+}
+
+// ProductRepository is a read-through cache in front of a catalog.ProductRepository.
+// It caches GetByCode and GetFiltered; every other method goes straight to the wrapped repository.
+type ProductRepository struct {
+	next  catalog.ProductRepository
+	cache *Cache
+}
+
+// NewProductRepository wraps next with the cache.
+func NewProductRepository(next catalog.ProductRepository, cache *Cache) *ProductRepository {
+	return &ProductRepository{next: next, cache: cache}
+}
+
+// GetAll retrieves all products from the wrapped repository.
+func (r *ProductRepository) GetAll(ctx context.Context) ([]product.Product, error) {
+	return r.next.GetAll(ctx)
+}
+
+// GetFiltered returns a cached page of products, loading it on a miss.
+func (r *ProductRepository) GetFiltered(ctx context.Context, offset, limit int, filters product.Filter, relations product.Relations) ([]product.Product, int64, error) {
+	price := ""
+	if filters.PriceLessThan != nil {
+		price = filters.PriceLessThan.String()
+	}
+	key := fmt.Sprintf("products:filtered:%d:%d:%s:%s:%t:%s", offset, limit, strconv.Quote(filters.Category), price, filters.InStock, relationsKey(relations))
+
+	page, err := load(ctx, r.cache, key, func(ctx context.Context) (filteredPage, error) {
+		products, total, err := r.next.GetFiltered(ctx, offset, limit, filters, relations)
+		return filteredPage{Products: products, Total: total}, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return page.Products, page.Total, nil
+}
+
+// GetByCode returns a cached product, loading it on a miss.
+func (r *ProductRepository) GetByCode(ctx context.Context, code string, relations product.Relations) (*product.Product, error) {
+	key := fmt.Sprintf("products:code:%s:%s", strconv.Quote(code), relationsKey(relations))
+
+	p, err := load(ctx, r.cache, key, func(ctx context.Context) (*product.Product, error) {
+		return r.next.GetByCode(ctx, code, relations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// GetByCodes retrieves products by code from the wrapped repository.
+func (r *ProductRepository) GetByCodes(ctx context.Context, codes []string) ([]product.Product, error) {
+	return r.next.GetByCodes(ctx, codes)
+}
+
+// GetByVariantSKU retrieves a product by variant SKU from the wrapped repository.
+func (r *ProductRepository) GetByVariantSKU(ctx context.Context, sku string) (*product.Product, error) {
+	return r.next.GetByVariantSKU(ctx, sku)
+}
+
+// GetFacets computes facets with the wrapped repository.
+func (r *ProductRepository) GetFacets(ctx context.Context, filters product.Filter, req product.FacetRequest, sale product.SaleCriteria) (product.Facets, error) {
+	return r.next.GetFacets(ctx, filters, req, sale)
+}
+
+// Stream reads products in batches from the wrapped repository.
+func (r *ProductRepository) Stream(ctx context.Context, filters product.Filter, batchSize int, fn func([]product.Product) error) error {
+	return r.next.Stream(ctx, filters, batchSize, fn)
+}
+
+func relationsKey(relations product.Relations) string {
+	return fmt.Sprintf("%t:%t", relations.Category, relations.Variants)
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	products []product.Product
+	err      error
+	calls    atomic.Int32
+}
+
+func (m *mockRepository) GetAll(ctx context.Context) ([]product.Product, error) {
+	m.calls.Add(1)
+	return m.products, m.err
+}
+
+func (m *mockRepository) GetFiltered(ctx context.Context, offset, limit int, filters product.Filter, relations product.Relations) ([]product.Product, int64, error) {
+	m.calls.Add(1)
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+	return m.products, int64(len(m.products)), nil
+}
+
+func (m *mockRepository) GetByCode(ctx context.Context, code string, relations product.Relations) (*product.Product, error) {
+	m.calls.Add(1)
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, p := range m.products {
+		if p.Code == code {
+			return &p, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockRepository) GetByCodes(ctx context.Context, codes []string) ([]product.Product, error) {
+	m.calls.Add(1)
+	return m.products, m.err
+}
+
+func (m *mockRepository) GetByVariantSKU(ctx context.Context, sku string) (*product.Product, error) {
+	m.calls.Add(1)
+	return &m.products[0], m.err
+}
+
+func (m *mockRepository) GetFacets(ctx context.Context, filters product.Filter, req product.FacetRequest, sale product.SaleCriteria) (product.Facets, error) {
+	m.calls.Add(1)
+	return product.Facets{}, m.err
+}
+
+func (m *mockRepository) Stream(ctx context.Context, filters product.Filter, batchSize int, fn func([]product.Product) error) error {
+	m.calls.Add(1)
+	if m.err != nil {
+		return m.err
+	}
+	return fn(m.products)
+}
+
+func newTestRepository() *mockRepository {
+	return &mockRepository{
+		products: []product.Product{
+			{ID: 1, Code: "PROD001", Price: decimal.NewFromInt(10), CostPrice: decimal.NewFromInt(6)},
+			{ID: 2, Code: "PROD002", Price: decimal.NewFromInt(20), CostPrice: decimal.NewFromInt(12)},
+		},
+	}
+}
+
+func newTestCache() *Cache {
+	return New(NewMemoryStore(10), time.Minute)
+}
+
+func TestProductRepository_GetFiltered(t *testing.T) {
+	t.Run("serves repeated queries from the cache", func(t *testing.T) {
+		next := newTestRepository()
+		repo := NewProductRepository(next, newTestCache())
+
+		products, total, err := repo.GetFiltered(context.Background(), 0, 10, product.Filter{}, product.Relations{})
+		require.NoError(t, err)
+		cached, cachedTotal, err := repo.GetFiltered(context.Background(), 0, 10, product.Filter{}, product.Relations{})
+		require.NoError(t, err)
+
+		assert.Equal(t, products, cached)
+		assert.Equal(t, total, cachedTotal)
+		assert.Equal(t, int32(1), next.calls.Load())
+	})
+
+	t.Run("keys entries by pagination, filters and relations", func(t *testing.T) {
+		next := newTestRepository()
+		cache := newTestCache()
+		repo := NewProductRepository(next, cache)
+		price := decimal.NewFromInt(50)
+
+		queries := []struct {
+			offset, limit int
+			filters       product.Filter
+			relations     product.Relations
+		}{
+			{0, 10, product.Filter{}, product.Relations{}},
+			{10, 10, product.Filter{}, product.Relations{}},
+			{0, 5, product.Filter{}, product.Relations{}},
+			{0, 10, product.Filter{Category: "boots"}, product.Relations{}},
+			{0, 10, product.Filter{PriceLessThan: &price}, product.Relations{}},
+			{0, 10, product.Filter{}, product.Relations{Category: true}},
+		}
+		for _, q := range queries {
+			_, _, err := repo.GetFiltered(context.Background(), q.offset, q.limit, q.filters, q.relations)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(len(queries)), next.calls.Load())
+		assert.Equal(t, len(queries), cache.Stats().Entries)
+	})
+}
+
+func TestProductRepository_GetByCode(t *testing.T) {
+	t.Run("serves repeated lookups from the cache", func(t *testing.T) {
+		next := newTestRepository()
+		repo := NewProductRepository(next, newTestCache())
+
+		p, err := repo.GetByCode(context.Background(), "PROD001", product.AllRelations())
+		require.NoError(t, err)
+		p.Code = "mutated"
+
+		cached, err := repo.GetByCode(context.Background(), "PROD001", product.AllRelations())
+		require.NoError(t, err)
+
+		assert.Equal(t, "PROD001", cached.Code)
+		assert.True(t, cached.Price.Equal(decimal.NewFromInt(10)))
+		assert.Equal(t, int32(1), next.calls.Load())
+	})
+
+	t.Run("returns errors for unknown codes", func(t *testing.T) {
+		cache := newTestCache()
+		repo := NewProductRepository(newTestRepository(), cache)
+
+		_, err := repo.GetByCode(context.Background(), "UNKNOWN", product.AllRelations())
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, cache.Stats().Entries)
+	})
+}
+
+func TestProductRepository_Passthrough(t *testing.T) {
+	t.Run("passes uncached methods through", func(t *testing.T) {
+		next := newTestRepository()
+		cache := newTestCache()
+		repo := NewProductRepository(next, cache)
+
+		_, _ = repo.GetAll(context.Background())
+		_, _ = repo.GetAll(context.Background())
+		_, _ = repo.GetByCodes(context.Background(), []string{"PROD001"})
+		_, _ = repo.GetByVariantSKU(context.Background(), "PROD001-S")
+		_, _ = repo.GetFacets(context.Background(), product.Filter{}, product.FacetRequest{}, product.SaleCriteria{})
+
+		assert.Equal(t, int32(5), next.calls.Load())
+		assert.Equal(t, Stats{}, cache.Stats())
+	})
+}
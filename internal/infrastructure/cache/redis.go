@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	redisTimeout    = time.Second
+	redisPoolSize   = 8
+	redisRetryDelay = time.Second
+)
+
+// RedisStore is a Store backed by a Redis-compatible server, shared by every replica.
+//
+// Keys are namespaced by a generation counter kept on the server. Invalidate
+// increments it and publishes the new value; replicas running Run follow the
+// channel, so entries written under an older generation stop being read and
+// expire on their own.
+type RedisStore struct {
+	addr   string
+	prefix string
+
+	idle       chan *respConn
+	generation atomic.Int64
+}
+
+// NewRedisStore creates a store on the server at addr, namespacing keys with prefix.
+func NewRedisStore(addr, prefix string) *RedisStore {
+	return &RedisStore{
+		addr:   addr,
+		prefix: prefix,
+		idle:   make(chan *respConn, redisPoolSize),
+	}
+}
+
+// Get returns the value stored under key in the current generation.
+func (s *RedisStore) Get(key string) ([]byte, bool, error) {
+	reply, err := s.do("GET", s.key(key))
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key in the current generation for ttl.
+func (s *RedisStore) Set(key string, value []byte, ttl time.Duration) error {
+	_, err := s.do("SET", s.key(key), string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// Invalidate moves every replica to a new generation.
+func (s *RedisStore) Invalidate() error {
+	reply, err := s.do("INCR", s.generationKey())
+	if err != nil {
+		return err
+	}
+
+	generation, ok := reply.(int64)
+	if !ok {
+		return fmt.Errorf("redis: unexpected INCR reply %T", reply)
+	}
+	s.advance(generation)
+
+	_, err = s.do("PUBLISH", s.channel(), strconv.FormatInt(generation, 10))
+	return err
+}
+
+// Sync reads the current generation from the server.
+func (s *RedisStore) Sync() error {
+	reply, err := s.do("GET", s.generationKey())
+	if err != nil {
+		return err
+	}
+	if reply == nil {
+		return nil
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	generation, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return fmt.Errorf("redis: invalid generation %q", value)
+	}
+	s.advance(generation)
+	return nil
+}
+
+// Run follows invalidations published by other replicas, reconnecting on failure.
+// It blocks until ctx is done.
+func (s *RedisStore) Run(ctx context.Context) {
+	for {
+		if err := s.subscribe(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("cache: following invalidations failed: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(redisRetryDelay):
+		}
+	}
+}
+
+func (s *RedisStore) subscribe(ctx context.Context) error {
+	conn, err := dialRESP(s.addr, redisTimeout)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		_ = conn.close()
+	}()
+
+	if _, err := conn.do("SUBSCRIBE", s.channel()); err != nil {
+		return err
+	}
+
+	// Invalidations published while this replica was not subscribed are only visible in the counter.
+	if err := s.Sync(); err != nil {
+		return err
+	}
+
+	for {
+		reply, err := conn.receive()
+		if err != nil {
+			return err
+		}
+
+		message, ok := reply.([]any)
+		if !ok || len(message) != 3 {
+			continue
+		}
+		if kind, _ := message[0].([]byte); string(kind) != "message" {
+			continue
+		}
+		payload, _ := message[2].([]byte)
+		generation, err := strconv.ParseInt(string(payload), 10, 64)
+		if err != nil {
+			continue
+		}
+		s.advance(generation)
+	}
+}
+
+// advance moves to generation unless a newer one is already known.
+func (s *RedisStore) advance(generation int64) {
+	for {
+		current := s.generation.Load()
+		if generation <= current || s.generation.CompareAndSwap(current, generation) {
+			return
+		}
+	}
+}
+
+func (s *RedisStore) key(key string) string {
+	return fmt.Sprintf("%s:%d:%s", s.prefix, s.generation.Load(), key)
+}
+
+func (s *RedisStore) generationKey() string {
+	return s.prefix + ":generation"
+}
+
+func (s *RedisStore) channel() string {
+	return s.prefix + ":invalidations"
+}
+
+// do runs a command on a pooled connection. Connections are dropped after
+// transport errors and reused after error replies.
+func (s *RedisStore) do(args ...string) (any, error) {
+	var conn *respConn
+	select {
+	case conn = <-s.idle:
+	default:
+		var err error
+		if conn, err = dialRESP(s.addr, redisTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	_ = conn.conn.SetDeadline(time.Now().Add(redisTimeout))
+	reply, err := conn.do(args...)
+	if _, ok := err.(respError); err != nil && !ok {
+		_ = conn.close()
+		return nil, err
+	}
+
+	select {
+	case s.idle <- conn:
+	default:
+		_ = conn.close()
+	}
+	return reply, err
+}
@@ -0,0 +1,288 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// respServer is an in-process stand-in for Redis implementing the commands
+// RedisStore uses: GET, SET with PX, INCR, PUBLISH and SUBSCRIBE.
+type respServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	values      map[string]string
+	expires     map[string]time.Time
+	subscribers map[string][]*respClient
+}
+
+type respClient struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func (s *respClient) write(value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeValue(s.w, value)
+	_ = s.w.Flush()
+}
+
+func newRESPServer(t *testing.T) *respServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &respServer{
+		listener:    listener,
+		values:      make(map[string]string),
+		expires:     make(map[string]time.Time),
+		subscribers: make(map[string][]*respClient),
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *respServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *respServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	client := &respClient{w: bufio.NewWriter(conn)}
+
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+
+		parts, _ := reply.([]any)
+		args := make([]string, len(parts))
+		for i, p := range parts {
+			b, _ := p.([]byte)
+			args[i] = string(b)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		client.write(s.execute(client, strings.ToUpper(args[0]), args[1:]))
+	}
+}
+
+func (s *respServer) execute(client *respClient, command string, args []string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch command {
+	case "GET":
+		if exp, ok := s.expires[args[0]]; ok && !time.Now().Before(exp) {
+			delete(s.values, args[0])
+			delete(s.expires, args[0])
+		}
+		value, ok := s.values[args[0]]
+		if !ok {
+			return nil
+		}
+		return []byte(value)
+	case "SET":
+		s.values[args[0]] = args[1]
+		delete(s.expires, args[0])
+		if len(args) == 4 && strings.EqualFold(args[2], "PX") {
+			ms, _ := strconv.Atoi(args[3])
+			s.expires[args[0]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		return "OK"
+	case "INCR":
+		n, _ := strconv.ParseInt(s.values[args[0]], 10, 64)
+		n++
+		s.values[args[0]] = strconv.FormatInt(n, 10)
+		return n
+	case "PUBLISH":
+		subscribers := s.subscribers[args[0]]
+		for _, sub := range subscribers {
+			go sub.write([]any{[]byte("message"), []byte(args[0]), []byte(args[1])})
+		}
+		return int64(len(subscribers))
+	case "SUBSCRIBE":
+		s.subscribers[args[0]] = append(s.subscribers[args[0]], client)
+		return []any{[]byte("subscribe"), []byte(args[0]), int64(1)}
+	default:
+		return respError("ERR unknown command '" + command + "'")
+	}
+}
+
+func (s *respServer) subscriberCount(channel string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers[channel])
+}
+
+func writeValue(w *bufio.Writer, value any) {
+	switch v := value.(type) {
+	case nil:
+		_, _ = w.WriteString("$-1\r\n")
+	case string:
+		_, _ = w.WriteString("+" + v + "\r\n")
+	case respError:
+		_, _ = w.WriteString("-" + string(v) + "\r\n")
+	case int64:
+		_, _ = w.WriteString(":" + strconv.FormatInt(v, 10) + "\r\n")
+	case []byte:
+		_, _ = w.WriteString("$" + strconv.Itoa(len(v)) + "\r\n" + string(v) + "\r\n")
+	case []any:
+		_, _ = w.WriteString("*" + strconv.Itoa(len(v)) + "\r\n")
+		for _, e := range v {
+			writeValue(w, e)
+		}
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Run("stores and reads values", func(t *testing.T) {
+		server := newRESPServer(t)
+		store := NewRedisStore(server.addr(), "test")
+
+		_, ok, err := store.Get("key")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, store.Set("key", []byte(`{"code":"PROD001"}`), time.Minute))
+
+		value, ok, err := store.Get("key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []byte(`{"code":"PROD001"}`), value)
+	})
+
+	t.Run("expires values after the TTL", func(t *testing.T) {
+		server := newRESPServer(t)
+		store := NewRedisStore(server.addr(), "test")
+
+		require.NoError(t, store.Set("key", []byte("value"), 20*time.Millisecond))
+
+		assert.Eventually(t, func() bool {
+			_, ok, err := store.Get("key")
+			return err == nil && !ok
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("shares entries between replicas", func(t *testing.T) {
+		server := newRESPServer(t)
+		first := NewRedisStore(server.addr(), "test")
+		second := NewRedisStore(server.addr(), "test")
+
+		require.NoError(t, first.Set("key", []byte("value"), time.Minute))
+
+		value, ok, err := second.Get("key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []byte("value"), value)
+	})
+
+	t.Run("propagates invalidations to subscribed replicas", func(t *testing.T) {
+		server := newRESPServer(t)
+		first := NewRedisStore(server.addr(), "test")
+		second := NewRedisStore(server.addr(), "test")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go second.Run(ctx)
+		require.Eventually(t, func() bool { return server.subscriberCount("test:invalidations") == 1 }, time.Second, time.Millisecond)
+
+		require.NoError(t, second.Set("key", []byte("value"), time.Minute))
+		require.NoError(t, first.Invalidate())
+
+		_, ok, err := first.Get("key")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		assert.Eventually(t, func() bool {
+			_, ok, err := second.Get("key")
+			return err == nil && !ok
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("picks up the current generation on sync", func(t *testing.T) {
+		server := newRESPServer(t)
+		first := NewRedisStore(server.addr(), "test")
+		require.NoError(t, first.Invalidate())
+		require.NoError(t, first.Set("key", []byte("value"), time.Minute))
+
+		late := NewRedisStore(server.addr(), "test")
+		require.NoError(t, late.Sync())
+
+		_, ok, err := late.Get("key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("returns errors when the server is unreachable", func(t *testing.T) {
+		server := newRESPServer(t)
+		addr := server.addr()
+		require.NoError(t, server.listener.Close())
+
+		store := NewRedisStore(addr, "test")
+
+		_, _, err := store.Get("key")
+		assert.Error(t, err)
+	})
+
+	t.Run("backs a cache", func(t *testing.T) {
+		server := newRESPServer(t)
+		cache := New(NewRedisStore(server.addr(), "test"), time.Minute)
+		repo := NewProductRepository(newTestRepository(), cache)
+
+		first, err := repo.GetByCode(context.Background(), "PROD001", product.Relations{})
+		require.NoError(t, err)
+		second, err := repo.GetByCode(context.Background(), "PROD001", product.Relations{})
+		require.NoError(t, err)
+
+		assert.Equal(t, first.Code, second.Code)
+		assert.True(t, first.Price.Equal(second.Price))
+		assert.Equal(t, Stats{Hits: 1, Misses: 1}, cache.Stats())
+	})
+}
+
+func TestReadReply(t *testing.T) {
+	t.Run("parses every reply type", func(t *testing.T) {
+		input := "+OK\r\n-ERR wrong\r\n:42\r\n$5\r\nhello\r\n$-1\r\n*2\r\n$1\r\na\r\n:1\r\n"
+		r := bufio.NewReader(strings.NewReader(input))
+
+		expected := []any{"OK", respError("ERR wrong"), int64(42), []byte("hello"), nil, []any{[]byte("a"), int64(1)}}
+		for _, want := range expected {
+			got, err := readReply(r)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("rejects malformed replies", func(t *testing.T) {
+		for _, input := range []string{"?x\r\n", "+OK\n", "$abc\r\n"} {
+			_, err := readReply(bufio.NewReader(strings.NewReader(input)))
+			assert.Error(t, err, input)
+		}
+	})
+}
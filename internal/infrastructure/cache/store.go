@@ -0,0 +1,14 @@
+package cache
+
+import "time"
+
+// Store is a cache backend. Values are opaque bytes so that backends shared
+// between replicas can hold them.
+type Store interface {
+	// Get returns the value stored under key and whether it was found.
+	Get(key string) ([]byte, bool, error)
+	// Set stores value under key for ttl.
+	Set(key string, value []byte, ttl time.Duration) error
+	// Invalidate drops every entry, for every replica sharing the store.
+	Invalidate() error
+}
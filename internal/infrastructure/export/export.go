@@ -0,0 +1,187 @@
+// Package export writes catalog products as CSV or JSON Lines.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/importer"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
+	"github.com/shopspring/decimal"
+)
+
+// Format is the encoding of an export.
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// ErrUnknownFormat is returned for formats other than csv and jsonl.
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// CSV columns besides the import ones, so that an export can be imported back.
+const (
+	ColumnDiscount          = "discount"
+	ColumnFinalPrice        = "final_price"
+	ColumnVariantDiscount   = "variant_discount"
+	ColumnVariantFinalPrice = "variant_final_price"
+	ColumnVariantQuantity   = "variant_quantity"
+)
+
+var csvHeader = []string{
+	importer.ColumnProductCode, importer.ColumnPrice, importer.ColumnCategoryCode,
+	importer.ColumnVariantSKU, importer.ColumnVariantName, importer.ColumnVariantPrice,
+	ColumnDiscount, ColumnFinalPrice, ColumnVariantDiscount, ColumnVariantFinalPrice,
+	ColumnVariantQuantity,
+}
+
+// ParseFormat reads a format name. ndjson is accepted as an alias of jsonl.
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("%w %q, supported formats: csv, jsonl", ErrUnknownFormat, name)
+	}
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Writer encodes products one at a time. Output is buffered until Flush.
+type Writer interface {
+	Write(detail catalog.ProductDetail) error
+	Flush() error
+}
+
+// NewWriter creates a writer for the format on w.
+func NewWriter(w io.Writer, format Format) Writer {
+	if format == FormatCSV {
+		return newCSVWriter(w)
+	}
+	return newJSONLWriter(w)
+}
+
+// csvWriter writes one record per variant, or a single record without
+// variant columns for a product that has none.
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(detail catalog.ProductDetail) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	p := detail.Product
+	category := ""
+	if p.Category != nil {
+		category = p.Category.Code
+	}
+	base := []string{p.Code, p.Price.StringFixed(2), category}
+	discount := []string{percentage(detail.Percentage), price(detail.DiscountedPrice)}
+
+	if len(p.Variants) == 0 {
+		return c.w.Write(concat(base, []string{"", "", ""}, discount, []string{"", "", ""}))
+	}
+	for _, v := range p.Variants {
+		variantDiscount := detail.VariantDiscounts[v.SKU]
+		record := concat(base,
+			[]string{v.SKU, v.Name, v.Price.StringFixed(2)},
+			discount,
+			[]string{percentage(variantDiscount.Percentage), variantFinalPrice(v, variantDiscount), strconv.Itoa(v.Quantity)})
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// writeHeader writes the header before the first record, or on Flush for an empty export.
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(csvHeader)
+}
+
+// jsonlWriter writes one product per line, shaped like GET /catalog/{code}.
+type jsonlWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	buf := bufio.NewWriter(w)
+	return &jsonlWriter{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (j *jsonlWriter) Write(detail catalog.ProductDetail) error {
+	variantDiscounts := make(map[string]mapper.VariantDiscountInfo, len(detail.VariantDiscounts))
+	for sku, d := range detail.VariantDiscounts {
+		variantDiscounts[sku] = mapper.VariantDiscountInfo{DiscountedPrice: d.DiscountedPrice, Percentage: d.Percentage, Limit: d.Limit}
+	}
+	response := mapper.ToProductDetailResponse(detail.Product, detail.DiscountedPrice, detail.Percentage, variantDiscounts)
+	response.DiscountLimit = mapper.ToDiscountLimitResponse(detail.Limit)
+	return j.enc.Encode(response)
+}
+
+func (j *jsonlWriter) Flush() error {
+	return j.buf.Flush()
+}
+
+func percentage(p int) string {
+	if p == 0 {
+		return ""
+	}
+	return strconv.Itoa(p)
+}
+
+func price(p float64) string {
+	return decimal.NewFromFloat(p).StringFixed(2)
+}
+
+func variantFinalPrice(v product.Variant, d catalog.VariantDiscount) string {
+	if d.Percentage == 0 {
+		return v.Price.StringFixed(2)
+	}
+	return price(d.DiscountedPrice)
+}
+
+func concat(parts ...[]string) []string {
+	var record []string
+	for _, part := range parts {
+		record = append(record, part...)
+	}
+	return record
+}
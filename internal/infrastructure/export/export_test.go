@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/application/importer"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDetails() []catalog.ProductDetail {
+	return []catalog.ProductDetail{
+		{
+			Product: product.Product{
+				Code:     "PROD001",
+				Price:    decimal.RequireFromString("100"),
+				Category: &product.Category{Code: "boots"},
+				Variants: []product.Variant{
+					{SKU: "SKU001A", Name: "Size 40", Price: decimal.RequireFromString("100"), Quantity: 4},
+					{SKU: "SKU001B", Name: "Size, 41", Price: decimal.RequireFromString("110")},
+				},
+			},
+			DiscountedPrice: 70,
+			Percentage:      30,
+			VariantDiscounts: map[string]catalog.VariantDiscount{
+				"SKU001A": {DiscountedPrice: 70, Percentage: 30},
+				"SKU001B": {DiscountedPrice: 77, Percentage: 30},
+			},
+		},
+		{
+			Product:         product.Product{Code: "PROD002", Price: decimal.RequireFromString("12.5")},
+			DiscountedPrice: 12.5,
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Run("accepts csv, jsonl and ndjson", func(t *testing.T) {
+		for name, expected := range map[string]Format{"csv": FormatCSV, "jsonl": FormatJSONL, "ndjson": FormatJSONL} {
+			format, err := ParseFormat(name)
+
+			require.NoError(t, err)
+			assert.Equal(t, expected, format)
+		}
+	})
+
+	t.Run("rejects other formats", func(t *testing.T) {
+		_, err := ParseFormat("xml")
+
+		assert.ErrorIs(t, err, ErrUnknownFormat)
+	})
+}
+
+func TestWriter_CSV(t *testing.T) {
+	t.Run("writes one record per variant with final prices", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, FormatCSV)
+		for _, d := range testDetails() {
+			require.NoError(t, writer.Write(d))
+		}
+		require.NoError(t, writer.Flush())
+
+		assert.Equal(t, strings.Join([]string{
+			"product_code,price,category_code,variant_sku,variant_name,variant_price,discount,final_price,variant_discount,variant_final_price,variant_quantity",
+			"PROD001,100.00,boots,SKU001A,Size 40,100.00,30,70.00,30,70.00,4",
+			`PROD001,100.00,boots,SKU001B,"Size, 41",110.00,30,70.00,30,77.00,0`,
+			"PROD002,12.50,,,,,,12.50,,,",
+			"",
+		}, "\n"), buf.String())
+	})
+
+	t.Run("writes the header for an empty export", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		require.NoError(t, NewWriter(&buf, FormatCSV).Flush())
+
+		assert.Equal(t, strings.Join(csvHeader, ",")+"\n", buf.String())
+	})
+
+	t.Run("can be imported back", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, FormatCSV)
+		for _, d := range testDetails() {
+			require.NoError(t, writer.Write(d))
+		}
+		require.NoError(t, writer.Flush())
+
+		rows, err := importer.ParseCSV(&buf)
+
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, "SKU001B", rows[1].VariantSKU)
+		assert.Equal(t, "110.00", rows[1].VariantPrice)
+	})
+}
+
+func TestWriter_JSONL(t *testing.T) {
+	t.Run("writes one product per line", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, FormatJSONL)
+		for _, d := range testDetails() {
+			require.NoError(t, writer.Write(d))
+		}
+		require.NoError(t, writer.Flush())
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+		assert.JSONEq(t, `{
+			"code": "PROD001", "price": 100, "category": "boots", "discount": "30%", "final_price": 70,
+			"variants": [
+				{"code": "SKU001A", "price": 100, "discount": "30%", "final_price": 70, "available": true, "quantity": 4},
+				{"code": "SKU001B", "price": 110, "discount": "30%", "final_price": 77, "available": false, "quantity": 0}
+			]
+		}`, lines[0])
+		assert.JSONEq(t, `{"code": "PROD002", "price": 12.5, "category": "", "variants": []}`, lines[1])
+	})
+}
@@ -0,0 +1,233 @@
+// Package fixture reads seed data from YAML or JSON files.
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/seed"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of a fixture file.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+type file struct {
+	Categories    []categoryEntry   `yaml:"categories" json:"categories"`
+	Products      []productEntry    `yaml:"products" json:"products"`
+	DiscountRules []ruleEntry       `yaml:"discountRules" json:"discountRules"`
+	DiscountCaps  []capEntry        `yaml:"discountCaps" json:"discountCaps"`
+	BasketRules   []basketRuleEntry `yaml:"basketRules" json:"basketRules"`
+	Coupons       []couponEntry     `yaml:"coupons" json:"coupons"`
+}
+
+type categoryEntry struct {
+	Code string `yaml:"code" json:"code"`
+	Name string `yaml:"name" json:"name"`
+}
+
+// productEntry.CostPrice is optional; without it the stored cost price is kept.
+type productEntry struct {
+	Code      string          `yaml:"code" json:"code"`
+	Price     decimal.Decimal `yaml:"price" json:"price"`
+	CostPrice decimal.Decimal `yaml:"costPrice" json:"costPrice"`
+	Category  string          `yaml:"category" json:"category"`
+	Variants  []variantEntry  `yaml:"variants" json:"variants"`
+}
+
+// variantEntry.Stock is the quantity held in the default warehouse.
+type variantEntry struct {
+	SKU   string           `yaml:"sku" json:"sku"`
+	Name  string           `yaml:"name" json:"name"`
+	Price *decimal.Decimal `yaml:"price" json:"price"`
+	Stock *int             `yaml:"stock" json:"stock"`
+}
+
+// ruleEntry without segments, markets or channels applies to every customer.
+// Expression rules name themselves by target and set the condition in expression.
+type ruleEntry struct {
+	Kind       string   `yaml:"kind" json:"kind"`
+	Target     string   `yaml:"target" json:"target"`
+	Expression string   `yaml:"expression" json:"expression"`
+	Percentage int      `yaml:"percentage" json:"percentage"`
+	Segments   []string `yaml:"segments" json:"segments"`
+	Markets    []string `yaml:"markets" json:"markets"`
+	Channels   []string `yaml:"channels" json:"channels"`
+}
+
+type capEntry struct {
+	Category   string `yaml:"category" json:"category"`
+	Percentage int    `yaml:"percentage" json:"percentage"`
+}
+
+// basketRuleEntry.Precedence defaults to after_items.
+type basketRuleEntry struct {
+	Name       string          `yaml:"name" json:"name"`
+	Kind       string          `yaml:"kind" json:"kind"`
+	Categories []string        `yaml:"categories" json:"categories"`
+	Quantity   int             `yaml:"quantity" json:"quantity"`
+	Free       int             `yaml:"free" json:"free"`
+	MinSpend   decimal.Decimal `yaml:"minSpend" json:"minSpend"`
+	Percentage int             `yaml:"percentage" json:"percentage"`
+	Precedence string          `yaml:"precedence" json:"precedence"`
+	Stop       bool            `yaml:"stop" json:"stop"`
+	CouponOnly bool            `yaml:"couponOnly" json:"couponOnly"`
+}
+
+// couponEntry.StartsAt and EndsAt are RFC 3339 timestamps; either may be left out.
+type couponEntry struct {
+	Code           string    `yaml:"code" json:"code"`
+	Rule           string    `yaml:"rule" json:"rule"`
+	StartsAt       time.Time `yaml:"startsAt" json:"startsAt"`
+	EndsAt         time.Time `yaml:"endsAt" json:"endsAt"`
+	MaxRedemptions int       `yaml:"maxRedemptions" json:"maxRedemptions"`
+	MaxPerCustomer int       `yaml:"maxPerCustomer" json:"maxPerCustomer"`
+}
+
+// Load reads the fixture at path, picking the format from its extension.
+func Load(path string) (seed.Fixture, error) {
+	var format Format
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	case ".json":
+		format = FormatJSON
+	default:
+		return seed.Fixture{}, fmt.Errorf("fixture %s: unsupported extension, use .yaml, .yml or .json", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return seed.Fixture{}, fmt.Errorf("reading fixture: %w", err)
+	}
+
+	fixture, err := Parse(data, format)
+	if err != nil {
+		return seed.Fixture{}, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// Parse decodes a fixture. Unknown fields are rejected so that typos do not
+// silently drop data.
+func Parse(data []byte, format Format) (seed.Fixture, error) {
+	var f file
+	switch format {
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&f); err != nil {
+			return seed.Fixture{}, err
+		}
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&f); err != nil {
+			return seed.Fixture{}, err
+		}
+	default:
+		return seed.Fixture{}, fmt.Errorf("unsupported fixture format %q", format)
+	}
+
+	return f.toFixture(), nil
+}
+
+func (f file) toFixture() seed.Fixture {
+	fixture := seed.Fixture{
+		Categories:  make([]product.Category, len(f.Categories)),
+		Products:    make([]product.Product, len(f.Products)),
+		Rules:       make([]discount.Rule, len(f.DiscountRules)),
+		Caps:        make([]discount.Cap, len(f.DiscountCaps)),
+		BasketRules: make([]discount.BasketRule, len(f.BasketRules)),
+		Coupons:     make([]discount.Coupon, len(f.Coupons)),
+	}
+
+	for i, c := range f.Categories {
+		fixture.Categories[i] = product.Category{Code: c.Code, Name: c.Name}
+	}
+
+	for i, p := range f.Products {
+		prod := product.Product{Code: p.Code, Price: p.Price, CostPrice: p.CostPrice}
+		if p.Category != "" {
+			prod.Category = &product.Category{Code: p.Category}
+		}
+		for _, v := range p.Variants {
+			variant := product.Variant{SKU: v.SKU, Name: v.Name}
+			if v.Price != nil {
+				variant.Price = *v.Price
+			}
+			prod.Variants = append(prod.Variants, variant)
+			if v.Stock != nil {
+				fixture.Stock = append(fixture.Stock, inventory.Stock{
+					SKU:    v.SKU,
+					Levels: []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: *v.Stock}},
+				})
+			}
+		}
+		fixture.Products[i] = prod
+	}
+
+	for i, r := range f.DiscountRules {
+		fixture.Rules[i] = discount.Rule{
+			Kind:       discount.RuleKind(r.Kind),
+			Target:     r.Target,
+			Expression: r.Expression,
+			Percentage: r.Percentage,
+			Audience: discount.Audience{
+				Segments: r.Segments,
+				Markets:  r.Markets,
+				Channels: r.Channels,
+			},
+		}
+	}
+
+	for i, c := range f.DiscountCaps {
+		fixture.Caps[i] = discount.Cap{Category: c.Category, Percentage: c.Percentage}
+	}
+
+	for i, r := range f.BasketRules {
+		precedence := discount.Precedence(r.Precedence)
+		if precedence == "" {
+			precedence = discount.PrecedenceAfterItems
+		}
+		fixture.BasketRules[i] = discount.BasketRule{
+			Name:       r.Name,
+			Kind:       discount.BasketRuleKind(r.Kind),
+			Categories: r.Categories,
+			Quantity:   r.Quantity,
+			Free:       r.Free,
+			MinSpend:   r.MinSpend,
+			Percentage: r.Percentage,
+			Precedence: precedence,
+			Stop:       r.Stop,
+			CouponOnly: r.CouponOnly,
+		}
+	}
+
+	for i, c := range f.Coupons {
+		fixture.Coupons[i] = discount.Coupon{
+			Code:           c.Code,
+			Rule:           c.Rule,
+			StartsAt:       c.StartsAt,
+			EndsAt:         c.EndsAt,
+			MaxRedemptions: c.MaxRedemptions,
+			MaxPerCustomer: c.MaxPerCustomer,
+		}
+	}
+
+	return fixture
+}
@@ -0,0 +1,227 @@
+package fixture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/seed"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlFixture = `
+categories:
+  - code: boots
+    name: Boots
+products:
+  - code: PROD009
+    price: 89.99
+    costPrice: 41.5
+    category: boots
+    variants:
+      - sku: "000003"
+        name: Standard
+        stock: 4
+      - sku: SKU009B
+        name: Premium
+        price: 99.99
+  - code: PROD010
+    price: 5
+discountRules:
+  - kind: category
+    target: boots
+    percentage: 30
+  - kind: category
+    target: boots
+    percentage: 40
+    segments: [vip]
+    markets: [de]
+  - kind: expression
+    target: premium-boots
+    expression: category == "boots" and price > 50
+    percentage: 10
+discountCaps:
+  - category: boots
+    percentage: 35
+basketRules:
+  - name: boots-b2g1
+    kind: bundle
+    categories: [boots]
+    quantity: 2
+    free: 1
+  - name: spend-500
+    kind: threshold
+    minSpend: 500
+    percentage: 10
+    precedence: exclude_discounted
+    stop: true
+  - name: welcome
+    kind: threshold
+    minSpend: 1
+    percentage: 10
+    couponOnly: true
+coupons:
+  - code: WELCOME10
+    rule: welcome
+    endsAt: 2026-12-31T23:59:59Z
+    maxPerCustomer: 1
+`
+
+const jsonFixture = `{
+  "categories": [{"code": "boots", "name": "Boots"}],
+  "products": [
+    {
+      "code": "PROD009",
+      "price": 89.99,
+      "costPrice": "41.50",
+      "category": "boots",
+      "variants": [
+        {"sku": "000003", "name": "Standard", "stock": 4},
+        {"sku": "SKU009B", "name": "Premium", "price": "99.99"}
+      ]
+    },
+    {"code": "PROD010", "price": 5}
+  ],
+  "discountRules": [
+    {"kind": "category", "target": "boots", "percentage": 30},
+    {"kind": "category", "target": "boots", "percentage": 40, "segments": ["vip"], "markets": ["de"]},
+    {"kind": "expression", "target": "premium-boots", "expression": "category == \"boots\" and price > 50", "percentage": 10}
+  ],
+  "discountCaps": [{"category": "boots", "percentage": 35}],
+  "basketRules": [
+    {"name": "boots-b2g1", "kind": "bundle", "categories": ["boots"], "quantity": 2, "free": 1},
+    {"name": "spend-500", "kind": "threshold", "minSpend": 500, "percentage": 10, "precedence": "exclude_discounted", "stop": true},
+    {"name": "welcome", "kind": "threshold", "minSpend": 1, "percentage": 10, "couponOnly": true}
+  ],
+  "coupons": [{"code": "WELCOME10", "rule": "welcome", "endsAt": "2026-12-31T23:59:59Z", "maxPerCustomer": 1}]
+}`
+
+func assertTestFixture(t *testing.T, fixture seed.Fixture) {
+	t.Helper()
+
+	assert.Equal(t, []product.Category{{Code: "boots", Name: "Boots"}}, fixture.Categories)
+	require.Len(t, fixture.Products, 2)
+
+	prod := fixture.Products[0]
+	assert.Equal(t, "PROD009", prod.Code)
+	assert.True(t, prod.Price.Equal(decimal.RequireFromString("89.99")))
+	assert.True(t, prod.CostPrice.Equal(decimal.RequireFromString("41.50")))
+	assert.True(t, fixture.Products[1].CostPrice.IsZero())
+	assert.Equal(t, &product.Category{Code: "boots"}, prod.Category)
+	require.Len(t, prod.Variants, 2)
+	assert.Equal(t, "000003", prod.Variants[0].SKU)
+	assert.True(t, prod.Variants[0].Price.IsZero(), "variant without price inherits it")
+	assert.True(t, prod.Variants[1].Price.Equal(decimal.RequireFromString("99.99")))
+
+	assert.Nil(t, fixture.Products[1].Category)
+
+	assert.Equal(t, []inventory.Stock{
+		{SKU: "000003", Levels: []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: 4}}},
+	}, fixture.Stock, "only variants listing stock replace it")
+	assert.Empty(t, fixture.Products[1].Variants)
+
+	assert.Equal(t, []discount.Rule{
+		{Kind: discount.RuleCategory, Target: "boots", Percentage: 30},
+		{
+			Kind:       discount.RuleCategory,
+			Target:     "boots",
+			Percentage: 40,
+			Audience:   discount.Audience{Segments: []string{"vip"}, Markets: []string{"de"}},
+		},
+		{Kind: discount.RuleExpression, Target: "premium-boots", Expression: `category == "boots" and price > 50`, Percentage: 10},
+	}, fixture.Rules)
+
+	assert.Equal(t, []discount.Cap{{Category: "boots", Percentage: 35}}, fixture.Caps)
+
+	require.Len(t, fixture.BasketRules, 3)
+	bundle := fixture.BasketRules[0]
+	assert.Equal(t, discount.BasketBundle, bundle.Kind)
+	assert.Equal(t, []string{"boots"}, bundle.Categories)
+	assert.Equal(t, 2, bundle.Quantity)
+	assert.Equal(t, 1, bundle.Free)
+	assert.Equal(t, discount.PrecedenceAfterItems, bundle.Precedence, "precedence defaults to after_items")
+	threshold := fixture.BasketRules[1]
+	assert.Equal(t, "spend-500", threshold.Name)
+	assert.True(t, threshold.MinSpend.Equal(decimal.NewFromInt(500)))
+	assert.Equal(t, 10, threshold.Percentage)
+	assert.Equal(t, discount.PrecedenceExcludeDiscounted, threshold.Precedence)
+	assert.True(t, threshold.Stop)
+	assert.False(t, threshold.CouponOnly)
+	assert.True(t, fixture.BasketRules[2].CouponOnly)
+
+	require.Len(t, fixture.Coupons, 1)
+	coupon := fixture.Coupons[0]
+	assert.Equal(t, "WELCOME10", coupon.Code)
+	assert.Equal(t, "welcome", coupon.Rule)
+	assert.True(t, coupon.StartsAt.IsZero())
+	assert.True(t, coupon.EndsAt.Equal(time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)))
+	assert.Zero(t, coupon.MaxRedemptions)
+	assert.Equal(t, 1, coupon.MaxPerCustomer)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("parses yaml", func(t *testing.T) {
+		fixture, err := Parse([]byte(yamlFixture), FormatYAML)
+
+		require.NoError(t, err)
+		assertTestFixture(t, fixture)
+	})
+
+	t.Run("parses json", func(t *testing.T) {
+		fixture, err := Parse([]byte(jsonFixture), FormatJSON)
+
+		require.NoError(t, err)
+		assertTestFixture(t, fixture)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		_, err := Parse([]byte("products:\n  - code: PROD001\n    prize: 10\n"), FormatYAML)
+		assert.Error(t, err)
+
+		_, err = Parse([]byte(`{"products": [{"code": "PROD001", "prize": 10}]}`), FormatJSON)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects invalid prices", func(t *testing.T) {
+		_, err := Parse([]byte("products:\n  - code: PROD001\n    price: cheap\n"), FormatYAML)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("picks the format from the extension", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "catalog.json")
+		require.NoError(t, os.WriteFile(path, []byte(jsonFixture), 0o600))
+
+		fixture, err := Load(path)
+
+		require.NoError(t, err)
+		assertTestFixture(t, fixture)
+	})
+
+	t.Run("rejects unknown extensions", func(t *testing.T) {
+		_, err := Load("catalog.csv")
+
+		assert.ErrorContains(t, err, "unsupported extension")
+	})
+
+	t.Run("loads the development fixture", func(t *testing.T) {
+		fixture, err := Load("../../../fixtures/catalog.yaml")
+
+		require.NoError(t, err)
+		assert.Len(t, fixture.Categories, 4)
+		assert.Len(t, fixture.Products, 9)
+		assert.Len(t, fixture.Rules, 4)
+		assert.Len(t, fixture.Caps, 1)
+		assert.Len(t, fixture.BasketRules, 4)
+		assert.Len(t, fixture.Coupons, 1)
+	})
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/analysis"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+)
+
+type analyzedRuleResponse struct {
+	Kind       string   `json:"kind"`
+	Target     string   `json:"target"`
+	Expression string   `json:"expression,omitempty"`
+	Percentage int      `json:"percentage"`
+	Segments   []string `json:"segments,omitempty"`
+	Markets    []string `json:"markets,omitempty"`
+	Channels   []string `json:"channels,omitempty"`
+	Position   int      `json:"position"`
+}
+
+type ruleReportResponse struct {
+	analyzedRuleResponse
+	Matches int `json:"matches"`
+	Wins    int `json:"wins"`
+}
+
+type shadowResponse struct {
+	Rule analyzedRuleResponse   `json:"rule"`
+	By   []analyzedRuleResponse `json:"shadowedBy"`
+}
+
+type productRulesResponse struct {
+	ProductCode  string                 `json:"productCode"`
+	Rules        []analyzedRuleResponse `json:"rules"`
+	PriceChanges *bool                  `json:"priceChanges,omitempty"`
+}
+
+type analysisResponse struct {
+	Products       int                    `json:"products"`
+	Rules          []ruleReportResponse   `json:"rules"`
+	Unmatched      []analyzedRuleResponse `json:"unmatched"`
+	Shadowed       []shadowResponse       `json:"shadowed"`
+	Overlaps       []productRulesResponse `json:"overlaps"`
+	OrderSensitive []productRulesResponse `json:"orderSensitive"`
+}
+
+// AnalysisHandler handles HTTP requests for discount rule analysis.
+type AnalysisHandler struct {
+	service analysis.Service
+}
+
+// NewAnalysisHandler creates a new discount rule analysis HTTP handler.
+func NewAnalysisHandler(service analysis.Service) *AnalysisHandler {
+	return &AnalysisHandler{service: service}
+}
+
+// HandleGet handles GET /discount-rules/analysis requests.
+// Runs the stored rules against the catalog and reports rules matching no
+// product, shadowed rules, overlaps and order-sensitive products.
+func (h *AnalysisHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.Analyze(r.Context())
+	if err != nil {
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	response := analysisResponse{
+		Products:       result.Products,
+		Rules:          make([]ruleReportResponse, len(result.Rules)),
+		Unmatched:      toAnalyzedRuleResponses(result.Unmatched),
+		Shadowed:       make([]shadowResponse, len(result.Shadowed)),
+		Overlaps:       make([]productRulesResponse, len(result.Overlaps)),
+		OrderSensitive: make([]productRulesResponse, len(result.OrderSensitive)),
+	}
+	for i, report := range result.Rules {
+		response.Rules[i] = ruleReportResponse{analyzedRuleResponse: toAnalyzedRuleResponse(report.Rule), Matches: report.Matches, Wins: report.Wins}
+	}
+	for i, shadow := range result.Shadowed {
+		response.Shadowed[i] = shadowResponse{Rule: toAnalyzedRuleResponse(shadow.Rule), By: toAnalyzedRuleResponses(shadow.By)}
+	}
+	for i, overlap := range result.Overlaps {
+		response.Overlaps[i] = productRulesResponse{ProductCode: overlap.ProductCode, Rules: toAnalyzedRuleResponses(overlap.Rules)}
+	}
+	for i, sensitive := range result.OrderSensitive {
+		priceChanges := sensitive.PriceChanges
+		response.OrderSensitive[i] = productRulesResponse{ProductCode: sensitive.ProductCode, Rules: toAnalyzedRuleResponses(sensitive.Rules), PriceChanges: &priceChanges}
+	}
+	okResponse(w, response)
+}
+
+func toAnalyzedRuleResponse(rule discount.Rule) analyzedRuleResponse {
+	return analyzedRuleResponse{
+		Kind:       string(rule.Kind),
+		Target:     rule.Target,
+		Expression: rule.Expression,
+		Percentage: rule.Percentage,
+		Segments:   rule.Audience.Segments,
+		Markets:    rule.Audience.Markets,
+		Channels:   rule.Audience.Channels,
+		Position:   rule.Position,
+	}
+}
+
+func toAnalyzedRuleResponses(rules []discount.Rule) []analyzedRuleResponse {
+	responses := make([]analyzedRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = toAnalyzedRuleResponse(rule)
+	}
+	return responses
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAnalysisService struct {
+	analysis discount.Analysis
+	err      error
+}
+
+func (m *mockAnalysisService) Analyze(ctx context.Context) (discount.Analysis, error) {
+	return m.analysis, m.err
+}
+
+func TestAnalysisHandler_HandleGet(t *testing.T) {
+	t.Run("reports the analysis of the rules", func(t *testing.T) {
+		boots := discount.Rule{Kind: discount.RuleCategory, Target: "boots", Percentage: 30, Position: 1}
+		sku := discount.Rule{Kind: discount.RuleSKU, Target: "000003", Percentage: 15, Position: 2}
+		vip := discount.Rule{Kind: discount.RuleExpression, Target: "vip-premium", Expression: "price > 100", Percentage: 20, Audience: discount.Audience{Segments: []string{"vip"}}, Position: 3}
+		handler := NewAnalysisHandler(&mockAnalysisService{analysis: discount.Analysis{
+			Products:       5,
+			Rules:          []discount.RuleReport{{Rule: boots, Matches: 1, Wins: 1}, {Rule: sku, Matches: 1}, {Rule: vip}},
+			Unmatched:      []discount.Rule{vip},
+			Shadowed:       []discount.Shadow{{Rule: sku, By: []discount.Rule{boots}}},
+			Overlaps:       []discount.ProductRules{{ProductCode: "PROD009", Rules: []discount.Rule{boots, sku}}},
+			OrderSensitive: []discount.ProductRules{{ProductCode: "PROD009", Rules: []discount.Rule{boots, sku}, PriceChanges: true}},
+		}})
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, httptest.NewRequest("GET", "/discount-rules/analysis", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, float64(5), response["products"])
+		assert.Equal(t, map[string]any{"kind": "sku", "target": "000003", "percentage": float64(15), "position": float64(2), "matches": float64(1), "wins": float64(0)}, response["rules"].([]any)[1])
+		assert.Equal(t, map[string]any{"kind": "expression", "target": "vip-premium", "expression": "price > 100", "percentage": float64(20), "segments": []any{"vip"}, "position": float64(3)}, response["unmatched"].([]any)[0])
+		assert.Equal(t, "boots", response["shadowed"].([]any)[0].(map[string]any)["shadowedBy"].([]any)[0].(map[string]any)["target"])
+		assert.NotContains(t, response["overlaps"].([]any)[0], "priceChanges")
+		assert.Equal(t, true, response["orderSensitive"].([]any)[0].(map[string]any)["priceChanges"])
+	})
+
+	t.Run("returns empty lists without findings", func(t *testing.T) {
+		handler := NewAnalysisHandler(&mockAnalysisService{})
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, httptest.NewRequest("GET", "/discount-rules/analysis", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"products":0,"rules":[],"unmatched":[],"shadowed":[],"overlaps":[],"orderSensitive":[]}`, w.Body.String())
+	})
+
+	t.Run("returns 500 when the analysis fails", func(t *testing.T) {
+		handler := NewAnalysisHandler(&mockAnalysisService{err: errors.New("database error")})
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, httptest.NewRequest("GET", "/discount-rules/analysis", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/cache"
+)
+
+// CacheStatsSource exposes the counters of a cache.
+type CacheStatsSource interface {
+	Stats() cache.Stats
+}
+
+// CacheHandler handles HTTP requests for cache observability.
+type CacheHandler struct {
+	cache CacheStatsSource
+}
+
+// NewCacheHandler creates a new cache HTTP handler.
+func NewCacheHandler(cache CacheStatsSource) *CacheHandler {
+	return &CacheHandler{cache: cache}
+}
+
+// HandleGetStats handles GET /cache/stats requests.
+// Returns the hit, miss and eviction counters of the query cache.
+func (h *CacheHandler) HandleGetStats(w http.ResponseWriter, r *http.Request) {
+	okResponse(w, h.cache.Stats())
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCacheStats struct {
+	stats cache.Stats
+}
+
+func (m *mockCacheStats) Stats() cache.Stats {
+	return m.stats
+}
+
+func TestCacheHandler_HandleGetStats(t *testing.T) {
+	t.Run("returns query cache counters", func(t *testing.T) {
+		handler := NewCacheHandler(&mockCacheStats{stats: cache.Stats{Hits: 7, Misses: 3, Evictions: 1, Entries: 2}})
+
+		req := httptest.NewRequest("GET", "/cache/stats", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleGetStats(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response cache.Stats
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, cache.Stats{Hits: 7, Misses: 3, Evictions: 1, Entries: 2}, response)
+	})
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Caching adds validators and Cache-Control headers to successful responses
+// and answers conditional requests with 304 Not Modified.
+type Caching struct {
+	rulesUpdatedAt time.Time
+}
+
+// NewCaching creates the caching middleware. Last-Modified values are never
+// earlier than rulesUpdatedAt, since a change of discount rules changes prices
+// without touching any row.
+func NewCaching(rulesUpdatedAt time.Time) *Caching {
+	return &Caching{rulesUpdatedAt: rulesUpdatedAt}
+}
+
+// Wrap buffers the route's response and, when it succeeds, sets a strong ETag
+// computed from the payload and the given Cache-Control value.
+// Handlers may set Last-Modified themselves with setLastModified.
+func (c *Caching) Wrap(cacheControl string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		buf := &bufferedResponse{header: w.Header(), status: http.StatusOK}
+		next(buf, r)
+
+		if buf.status != http.StatusOK {
+			w.WriteHeader(buf.status)
+			_, _ = w.Write(buf.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(buf.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+		header := w.Header()
+		header.Set("ETag", etag)
+		if cacheControl != "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+
+		lastModified, hasLastModified := c.lastModified(header)
+		if hasLastModified {
+			header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		}
+
+		if notModified(r, etag, lastModified, hasLastModified) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf.body.Bytes())
+	}
+}
+
+// lastModified reads the Last-Modified header set by the handler, raised to the rules update time.
+func (c *Caching) lastModified(header http.Header) (time.Time, bool) {
+	value := header.Get("Last-Modified")
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	modified, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if c.rulesUpdatedAt.After(modified) {
+		modified = c.rulesUpdatedAt
+	}
+	return modified, true
+}
+
+// setLastModified records when the response content last changed.
+func setLastModified(w http.ResponseWriter, modified time.Time) {
+	if modified.IsZero() {
+		return
+	}
+	w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since only
+// when no entity tag was sent.
+func notModified(r *http.Request, etag string, lastModified time.Time, hasLastModified bool) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" && hasLastModified {
+		t, err := http.ParseTime(since)
+		if err != nil {
+			return false
+		}
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
+// bufferedResponse captures a handler's response so it can be hashed before it is sent.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedHandler(rulesUpdatedAt, modified time.Time, status int, body string) http.HandlerFunc {
+	return NewCaching(rulesUpdatedAt).Wrap("public, max-age=60", func(w http.ResponseWriter, r *http.Request) {
+		setLastModified(w, modified)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func serve(handler http.HandlerFunc, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/catalog", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+func TestCaching_Wrap(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("sets validators and cache control on successful responses", func(t *testing.T) {
+		handler := newCachedHandler(time.Time{}, modified, http.StatusOK, `{"total":1}`)
+
+		w := serve(handler, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"total":1}`, w.Body.String())
+		assert.Regexp(t, `^"[0-9a-f]{32}"$`, w.Header().Get("ETag"))
+		assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "Wed, 01 May 2024 10:00:00 GMT", w.Header().Get("Last-Modified"))
+	})
+
+	t.Run("derives the ETag from the payload", func(t *testing.T) {
+		first := serve(newCachedHandler(time.Time{}, modified, http.StatusOK, `{"total":1}`), nil)
+		same := serve(newCachedHandler(time.Time{}, modified, http.StatusOK, `{"total":1}`), nil)
+		changed := serve(newCachedHandler(time.Time{}, modified, http.StatusOK, `{"total":2}`), nil)
+
+		assert.Equal(t, first.Header().Get("ETag"), same.Header().Get("ETag"))
+		assert.NotEqual(t, first.Header().Get("ETag"), changed.Header().Get("ETag"))
+	})
+
+	t.Run("returns 304 when If-None-Match matches", func(t *testing.T) {
+		handler := newCachedHandler(time.Time{}, modified, http.StatusOK, `{"total":1}`)
+		etag := serve(handler, nil).Header().Get("ETag")
+
+		for _, match := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+			w := serve(handler, map[string]string{"If-None-Match": match})
+
+			assert.Equal(t, http.StatusNotModified, w.Code, match)
+			assert.Empty(t, w.Body.String(), match)
+			assert.Equal(t, etag, w.Header().Get("ETag"), match)
+			assert.Empty(t, w.Header().Get("Content-Type"), match)
+		}
+	})
+
+	t.Run("returns 200 when If-None-Match does not match", func(t *testing.T) {
+		handler := newCachedHandler(time.Time{}, modified, http.StatusOK, `{"total":1}`)
+
+		w := serve(handler, map[string]string{
+			"If-None-Match":     `"stale"`,
+			"If-Modified-Since": modified.Format(http.TimeFormat),
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"total":1}`, w.Body.String())
+	})
+
+	t.Run("evaluates If-Modified-Since against Last-Modified", func(t *testing.T) {
+		handler := newCachedHandler(time.Time{}, modified.Add(500*time.Millisecond), http.StatusOK, `{}`)
+
+		w := serve(handler, map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+
+		w = serve(handler, map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("ignores If-Modified-Since without Last-Modified", func(t *testing.T) {
+		handler := newCachedHandler(time.Time{}, time.Time{}, http.StatusOK, `{}`)
+
+		w := serve(handler, map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Last-Modified"))
+	})
+
+	t.Run("raises Last-Modified to the rules update time", func(t *testing.T) {
+		rulesUpdatedAt := modified.Add(time.Hour)
+		handler := newCachedHandler(rulesUpdatedAt, modified, http.StatusOK, `{}`)
+
+		w := serve(handler, map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, rulesUpdatedAt.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+	})
+
+	t.Run("passes error responses through without caching headers", func(t *testing.T) {
+		handler := newCachedHandler(time.Time{}, time.Time{}, http.StatusNotFound, `{"error":"not found"}`)
+
+		w := serve(handler, map[string]string{"If-None-Match": "*"})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, `{"error":"not found"}`, w.Body.String())
+		assert.Empty(t, w.Header().Get("ETag"))
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+	})
+}
+
+func TestHandleGetByCode_LastModified(t *testing.T) {
+	t.Run("uses the latest update of the product and its relations", func(t *testing.T) {
+		updated := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		p := newTestProduct(1, "PROD001", 10, categoryClothing)
+		p.UpdatedAt = updated
+		p.Variants = []product.Variant{{SKU: "PROD001-S", Price: p.Price, UpdatedAt: updated.Add(time.Hour)}}
+		handler := NewCatalogHandler(&mockDetailService{product: &p, discountedPrice: 10})
+
+		req := httptest.NewRequest("GET", "/catalog/PROD001", nil)
+		req.SetPathValue("code", "PROD001")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetByCode(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Wed, 01 May 2024 11:00:00 GMT", w.Header().Get("Last-Modified"))
+	})
+}
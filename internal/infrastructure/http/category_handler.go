@@ -2,14 +2,17 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/mytheresa/go-hiring-challenge/internal/application/category"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
 	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
 )
 
 type categoriesResponse struct {
-	Categories []mapper.CategoryResponse `json:"categories"`
+	Categories []mapper.CategorySummaryResponse `json:"categories"`
+	Total      int                              `json:"total"`
 }
 
 // CategoryHandler handles HTTP requests for categories.
@@ -23,15 +26,35 @@ func NewCategoryHandler(service category.Service) *CategoryHandler {
 }
 
 // HandleGet handles GET /categories requests.
+// Supports optional query parameters: offset, limit, sort and order.
+// Without a limit every category from the offset on is returned.
 func (h *CategoryHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.service.GetCategories()
+	offset, limit, err := parsePaginationParams(r)
 	if err != nil {
-		errorResponse(w, http.StatusInternalServerError, err.Error())
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if r.URL.Query().Get("limit") == "" {
+		limit = 0
+	}
+
+	query, err := parseCategorySortParams(r)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query.Offset = offset
+	query.Limit = limit
+
+	categories, total, err := h.service.GetCategories(r.Context(), query)
+	if err != nil {
+		serviceErrorResponse(w, r, err)
 		return
 	}
 
 	response := categoriesResponse{
-		Categories: mapper.ToCategoryResponses(categories),
+		Categories: mapper.ToCategorySummaryResponses(categories),
+		Total:      int(total),
 	}
 
 	okResponse(w, response)
@@ -56,9 +79,9 @@ func (h *CategoryHandler) HandlePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cat, err := h.service.CreateCategory(req.Code, req.Name)
+	cat, err := h.service.CreateCategory(r.Context(), req.Code, req.Name)
 	if err != nil {
-		errorResponse(w, http.StatusInternalServerError, err.Error())
+		serviceErrorResponse(w, r, err)
 		return
 	}
 
@@ -67,3 +90,26 @@ func (h *CategoryHandler) HandlePost(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	okResponse(w, response)
 }
+
+func parseCategorySortParams(r *http.Request) (product.CategoryQuery, error) {
+	query := product.CategoryQuery{Sort: product.CategorySortName}
+
+	switch sort := r.URL.Query().Get("sort"); sort {
+	case "":
+	case product.CategorySortName, product.CategorySortProducts, product.CategorySortOnSale:
+		query.Sort = sort
+	default:
+		return query, fmt.Errorf("invalid sort %q, supported sorts: %s, %s, %s",
+			sort, product.CategorySortName, product.CategorySortProducts, product.CategorySortOnSale)
+	}
+
+	switch order := r.URL.Query().Get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		return query, fmt.Errorf("invalid order %q, supported orders: asc, desc", order)
+	}
+
+	return query, nil
+}
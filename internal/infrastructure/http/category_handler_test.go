@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -14,19 +15,21 @@ import (
 )
 
 type mockCategoryService struct {
-	categories      []product.Category
+	categories      []product.CategorySummary
+	query           product.CategoryQuery
 	createdCategory *product.Category
 	err             error
 }
 
-func (m *mockCategoryService) GetCategories() ([]product.Category, error) {
+func (m *mockCategoryService) GetCategories(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error) {
+	m.query = query
 	if m.err != nil {
-		return nil, m.err
+		return nil, 0, m.err
 	}
-	return m.categories, nil
+	return m.categories, int64(len(m.categories)), nil
 }
 
-func (m *mockCategoryService) CreateCategory(code, name string) (*product.Category, error) {
+func (m *mockCategoryService) CreateCategory(ctx context.Context, code, name string) (*product.Category, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -35,10 +38,10 @@ func (m *mockCategoryService) CreateCategory(code, name string) (*product.Catego
 
 func TestCategoryHandler_HandleGet(t *testing.T) {
 	t.Run("returns all categories", func(t *testing.T) {
-		categories := []product.Category{
-			{ID: 1, Code: "clothing", Name: "Clothing"},
-			{ID: 2, Code: "shoes", Name: "Shoes"},
-			{ID: 3, Code: "accessories", Name: "Accessories"},
+		categories := []product.CategorySummary{
+			{Category: product.Category{ID: 1, Code: "clothing", Name: "Clothing"}, ProductCount: 3},
+			{Category: product.Category{ID: 2, Code: "shoes", Name: "Shoes"}, ProductCount: 2, OnSaleCount: 1},
+			{Category: product.Category{ID: 3, Code: "accessories", Name: "Accessories"}},
 		}
 		service := &mockCategoryService{categories: categories}
 		handler := NewCategoryHandler(service)
@@ -56,10 +59,63 @@ func TestCategoryHandler_HandleGet(t *testing.T) {
 		assert.Len(t, response.Categories, 3)
 		assert.Equal(t, "clothing", response.Categories[0].Code)
 		assert.Equal(t, "Clothing", response.Categories[0].Name)
+		assert.Equal(t, int64(3), response.Categories[0].ProductCount)
+		assert.Equal(t, int64(1), response.Categories[1].OnSaleCount)
+		assert.Equal(t, 3, response.Total)
+	})
+
+	t.Run("returns every category by name without a limit", func(t *testing.T) {
+		service := &mockCategoryService{}
+		handler := NewCategoryHandler(service)
+
+		req := httptest.NewRequest("GET", "/categories", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, product.CategoryQuery{Sort: product.CategorySortName}, service.query)
+	})
+
+	t.Run("passes pagination and sorting to the service", func(t *testing.T) {
+		service := &mockCategoryService{}
+		handler := NewCategoryHandler(service)
+
+		req := httptest.NewRequest("GET", "/categories?offset=20&limit=5&sort=productCount&order=desc", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, product.CategoryQuery{
+			Offset: 20,
+			Limit:  5,
+			Sort:   product.CategorySortProducts,
+			Desc:   true,
+		}, service.query)
+	})
+
+	t.Run("returns 400 for invalid parameters", func(t *testing.T) {
+		tests := map[string]string{
+			"/categories?limit=0":      "limit must be at least 1",
+			"/categories?sort=price":   "invalid sort",
+			"/categories?order=upward": "invalid order",
+		}
+		for url, message := range tests {
+			handler := NewCategoryHandler(&mockCategoryService{})
+
+			req := httptest.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleGet(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, url)
+			assert.Contains(t, w.Body.String(), message, url)
+		}
 	})
 
 	t.Run("returns empty array when no categories exist", func(t *testing.T) {
-		service := &mockCategoryService{categories: []product.Category{}}
+		service := &mockCategoryService{categories: []product.CategorySummary{}}
 		handler := NewCategoryHandler(service)
 
 		req := httptest.NewRequest("GET", "/categories", nil)
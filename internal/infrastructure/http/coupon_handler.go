@@ -0,0 +1,65 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/coupon"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
+)
+
+// CouponHandler handles HTTP requests for coupons.
+type CouponHandler struct {
+	service coupon.Service
+}
+
+// NewCouponHandler creates a new coupon HTTP handler.
+func NewCouponHandler(service coupon.Service) *CouponHandler {
+	return &CouponHandler{service: service}
+}
+
+// HandleGet handles GET /coupons/:code requests.
+func (h *CouponHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	c, usage, err := h.service.Get(r.Context(), r.PathValue("code"))
+	if err != nil {
+		couponErrorResponse(w, r, err)
+		return
+	}
+
+	okResponse(w, mapper.ToCouponResponse(c, usage))
+}
+
+// HandleRedeem handles POST /coupons/:code/redemptions requests.
+// Records one use of the coupon by a customer; 409 is returned when the coupon
+// is not active or its limits are reached.
+func (h *CouponHandler) HandleRedeem(w http.ResponseWriter, r *http.Request) {
+	var req mapper.RedeemCouponRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	redemption, err := h.service.Redeem(r.Context(), r.PathValue("code"), req.CustomerID)
+	if err != nil {
+		couponErrorResponse(w, r, err)
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, mapper.ToRedemptionResponse(redemption))
+}
+
+// couponErrorResponse maps coupon errors to their status codes.
+func couponErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, discount.ErrInvalidRedemption):
+		errorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, discount.ErrCouponNotFound):
+		errorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, discount.ErrCouponInactive), errors.Is(err, discount.ErrCouponExhausted):
+		errorResponse(w, http.StatusConflict, err.Error())
+	default:
+		serviceErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/stretchr/testify/assert"
+)
+
+var redeemedAt = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+type mockCouponService struct {
+	coupon      discount.Coupon
+	redemptions int
+	err         error
+}
+
+func (m *mockCouponService) Get(ctx context.Context, code string) (discount.Coupon, discount.Usage, error) {
+	if code != m.coupon.Code {
+		return discount.Coupon{}, discount.Usage{}, fmt.Errorf("coupon %s: %w", code, discount.ErrCouponNotFound)
+	}
+	return m.coupon, discount.Usage{Total: m.redemptions}, nil
+}
+
+func (m *mockCouponService) Check(ctx context.Context, code, customerID string) (discount.Coupon, error) {
+	c, _, err := m.Get(ctx, code)
+	return c, err
+}
+
+func (m *mockCouponService) Redeem(ctx context.Context, code, customerID string) (discount.Redemption, error) {
+	if m.err != nil {
+		return discount.Redemption{}, m.err
+	}
+	if customerID == "" {
+		return discount.Redemption{}, discount.ErrInvalidRedemption
+	}
+	if _, _, err := m.Get(ctx, code); err != nil {
+		return discount.Redemption{}, err
+	}
+	m.redemptions++
+	return discount.Redemption{Code: code, CustomerID: customerID, RedeemedAt: redeemedAt}, nil
+}
+
+func newCouponRequest(method, path, code, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.SetPathValue("code", code)
+	return req
+}
+
+func TestCouponHandler_HandleGet(t *testing.T) {
+	service := &mockCouponService{
+		coupon:      discount.Coupon{Code: "SPRING", Rule: "spring-10", EndsAt: redeemedAt.AddDate(0, 1, 0), MaxRedemptions: 100, MaxPerCustomer: 1},
+		redemptions: 3,
+	}
+
+	t.Run("returns the coupon with its usage", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		NewCouponHandler(service).HandleGet(w, newCouponRequest("GET", "/coupons/SPRING", "SPRING", ""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"code": "SPRING", "rule": "spring-10", "endsAt": "2026-04-15T12:00:00Z",
+			"maxRedemptions": 100, "maxPerCustomer": 1, "redemptions": 3
+		}`, w.Body.String())
+	})
+
+	t.Run("returns 404 for unknown coupons", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		NewCouponHandler(service).HandleGet(w, newCouponRequest("GET", "/coupons/NOPE", "NOPE", ""))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCouponHandler_HandleRedeem(t *testing.T) {
+	t.Run("records the redemption", func(t *testing.T) {
+		service := &mockCouponService{coupon: discount.Coupon{Code: "SPRING", Rule: "spring-10"}}
+		w := httptest.NewRecorder()
+
+		NewCouponHandler(service).HandleRedeem(w, newCouponRequest("POST", "/coupons/SPRING/redemptions", "SPRING", `{"customerId": "c-1"}`))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"code": "SPRING", "customerId": "c-1", "redeemedAt": "2026-03-15T12:00:00Z"}`, w.Body.String())
+		assert.Equal(t, 1, service.redemptions)
+	})
+
+	t.Run("maps errors to status codes", func(t *testing.T) {
+		tests := map[string]struct {
+			body   string
+			err    error
+			status int
+		}{
+			"invalid json":     {`not json`, nil, http.StatusBadRequest},
+			"missing customer": {`{}`, nil, http.StatusBadRequest},
+			"unknown coupon":   {`{"customerId": "c-1"}`, discount.ErrCouponNotFound, http.StatusNotFound},
+			"inactive coupon":  {`{"customerId": "c-1"}`, discount.ErrCouponInactive, http.StatusConflict},
+			"limit reached":    {`{"customerId": "c-1"}`, discount.ErrCouponExhausted, http.StatusConflict},
+			"failure":          {`{"customerId": "c-1"}`, errors.New("db down"), http.StatusInternalServerError},
+		}
+		for name, tt := range tests {
+			service := &mockCouponService{coupon: discount.Coupon{Code: "SPRING", Rule: "spring-10"}, err: tt.err}
+			w := httptest.NewRecorder()
+
+			NewCouponHandler(service).HandleRedeem(w, newCouponRequest("POST", "/coupons/SPRING/redemptions", "SPRING", tt.body))
+
+			assert.Equal(t, tt.status, w.Code, name)
+		}
+	})
+}
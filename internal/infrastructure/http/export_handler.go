@@ -0,0 +1,54 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/export"
+)
+
+// HandleExport handles GET /catalog/export requests.
+// Streams every product matching the category and priceLessThan filters with
+// variants and final prices, as CSV (format=csv, the default) or JSON Lines
+// (format=jsonl).
+func (h *CatalogHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = string(export.FormatCSV)
+	}
+	format, err := export.ParseFormat(name)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filters, err := parseFilterParams(r)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, format))
+
+	out := &trackingWriter{ResponseWriter: w}
+	writer := export.NewWriter(out, format)
+	err = h.service.ExportProducts(r.Context(), filters, writer.Write)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		return
+	}
+
+	if !out.started {
+		w.Header().Del("Content-Disposition")
+		serviceErrorResponse(w, r, err)
+		return
+	}
+	// The status line is gone: abort so that the client sees a truncated
+	// download instead of a complete-looking one.
+	log.Printf("Catalog export failed after streaming started: %s", err)
+	panic(http.ErrAbortHandler)
+}
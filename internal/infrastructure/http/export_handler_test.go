@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingExportService streams its products and then fails.
+type failingExportService struct {
+	mockService
+	repeat int
+}
+
+func (m *failingExportService) ExportProducts(ctx context.Context, filters product.Filter, fn func(catalog.ProductDetail) error) error {
+	for i := 0; i < m.repeat; i++ {
+		if err := fn(catalog.ProductDetail{Product: m.products[0]}); err != nil {
+			return err
+		}
+	}
+	return errors.New("connection lost")
+}
+
+func TestHandleExport(t *testing.T) {
+	t.Run("streams the catalog as CSV by default", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(createTestProducts(3), nil))
+		req := httptest.NewRequest("GET", "/catalog/export", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleExport(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="catalog.csv"`, w.Header().Get("Content-Disposition"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.True(t, strings.HasPrefix(lines[1], "PROD001,65.76,clothing,"))
+	})
+
+	t.Run("streams JSON Lines with filters applied", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(createTestProducts(8), nil))
+		req := httptest.NewRequest("GET", "/catalog/export?format=jsonl&category=shoes", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleExport(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="catalog.jsonl"`, w.Header().Get("Content-Disposition"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.JSONEq(t, `{"code": "PROD002", "price": 12.54, "category": "shoes", "variants": []}`, lines[0])
+	})
+
+	t.Run("returns 400 for invalid parameters", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(createTestProducts(3), nil))
+		for _, url := range []string{"/catalog/export?format=xml", "/catalog/export?priceLessThan=abc"} {
+			w := httptest.NewRecorder()
+
+			handler.HandleExport(w, httptest.NewRequest("GET", url, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, url)
+		}
+	})
+
+	t.Run("returns a JSON error when the export fails before streaming", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(nil, errors.New("database error")))
+		req := httptest.NewRequest("GET", "/catalog/export", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleExport(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("aborts the response when the export fails while streaming", func(t *testing.T) {
+		service := &failingExportService{mockService: mockService{products: createTestProducts(1)}, repeat: 1000}
+		handler := NewCatalogHandler(service)
+		req := httptest.NewRequest("GET", "/catalog/export", nil)
+		w := httptest.NewRecorder()
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler.HandleExport(w, req)
+		})
+		assert.NotEmpty(t, w.Body.String())
+	})
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/feed"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/merchant"
+)
+
+type skippedItemResponse struct {
+	ProductCode string   `json:"productCode"`
+	SKU         string   `json:"sku,omitempty"`
+	Reasons     []string `json:"reasons"`
+}
+
+type feedReportResponse struct {
+	Items   int                   `json:"items"`
+	Skipped []skippedItemResponse `json:"skipped"`
+}
+
+// FeedHandler handles HTTP requests for product feeds.
+type FeedHandler struct {
+	service feed.Service
+	config  feed.Config
+}
+
+// NewFeedHandler creates a new feed HTTP handler for the channel described by config.
+func NewFeedHandler(service feed.Service, config feed.Config) *FeedHandler {
+	return &FeedHandler{service: service, config: config}
+}
+
+// HandleGetGoogleMerchant handles GET /feeds/google-merchant.xml requests.
+// Streams one Google Merchant item per listable variant.
+func (h *FeedHandler) HandleGetGoogleMerchant(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", merchant.ContentType)
+
+	out := &trackingWriter{ResponseWriter: w}
+	writer := merchant.NewWriter(out, h.config)
+	report, err := h.service.Generate(r.Context(), writer.Write)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		if len(report.Skipped) > 0 {
+			log.Printf("Google Merchant feed: %d items, %d skipped", report.Items, len(report.Skipped))
+		}
+		return
+	}
+
+	if !out.started {
+		serviceErrorResponse(w, r, err)
+		return
+	}
+	log.Printf("Google Merchant feed failed after streaming started: %s", err)
+	panic(http.ErrAbortHandler)
+}
+
+// HandleGetGoogleMerchantReport handles GET /feeds/google-merchant/report requests.
+// Returns how many items the feed has and which variants are skipped and why.
+func (h *FeedHandler) HandleGetGoogleMerchantReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.Generate(r.Context(), func(feed.Item) error { return nil })
+	if err != nil {
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	response := feedReportResponse{
+		Items:   report.Items,
+		Skipped: make([]skippedItemResponse, len(report.Skipped)),
+	}
+	for i, s := range report.Skipped {
+		response.Skipped[i] = skippedItemResponse{ProductCode: s.ProductCode, SKU: s.SKU, Reasons: s.Reasons}
+	}
+	okResponse(w, response)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/feed"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFeedService struct {
+	items  []feed.Item
+	report feed.Report
+	err    error
+}
+
+func (m *mockFeedService) Generate(ctx context.Context, fn func(feed.Item) error) (feed.Report, error) {
+	if m.err != nil {
+		return feed.Report{}, m.err
+	}
+	for _, item := range m.items {
+		if err := fn(item); err != nil {
+			return feed.Report{}, err
+		}
+	}
+	return m.report, nil
+}
+
+func TestFeedHandler_HandleGetGoogleMerchant(t *testing.T) {
+	t.Run("streams the feed as RSS", func(t *testing.T) {
+		service := &mockFeedService{items: []feed.Item{
+			{ID: "000003", ItemGroupID: "PROD009", Price: decimal.NewFromInt(100), Currency: "EUR"},
+		}}
+		handler := NewFeedHandler(service, feed.Config{Title: "Shop"})
+		w := httptest.NewRecorder()
+
+		handler.HandleGetGoogleMerchant(w, httptest.NewRequest("GET", "/feeds/google-merchant.xml", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/rss+xml; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(w.Body.String(), "<?xml"))
+		assert.Contains(t, w.Body.String(), "<g:item_group_id>PROD009</g:item_group_id>")
+		assert.Contains(t, w.Body.String(), "<g:price>100.00 EUR</g:price>")
+	})
+
+	t.Run("returns a JSON error when the catalog fails", func(t *testing.T) {
+		handler := NewFeedHandler(&mockFeedService{err: errors.New("database error")}, feed.Config{})
+		w := httptest.NewRecorder()
+
+		handler.HandleGetGoogleMerchant(w, httptest.NewRequest("GET", "/feeds/google-merchant.xml", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+}
+
+func TestFeedHandler_HandleGetGoogleMerchantReport(t *testing.T) {
+	t.Run("returns the item count and skipped items", func(t *testing.T) {
+		service := &mockFeedService{report: feed.Report{Items: 3, Skipped: []feed.SkippedItem{
+			{ProductCode: "PROD001", Reasons: []string{"product has no variants"}},
+			{ProductCode: "PROD002", SKU: "SKU002A", Reasons: []string{"product has no category"}},
+		}}}
+		handler := NewFeedHandler(service, feed.Config{})
+		w := httptest.NewRecorder()
+
+		handler.HandleGetGoogleMerchantReport(w, httptest.NewRequest("GET", "/feeds/google-merchant/report", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"items": 3,
+			"skipped": [
+				{"productCode": "PROD001", "reasons": ["product has no variants"]},
+				{"productCode": "PROD002", "sku": "SKU002A", "reasons": ["product has no category"]}
+			]
+		}`, w.Body.String())
+	})
+
+	t.Run("returns 500 when the catalog fails", func(t *testing.T) {
+		handler := NewFeedHandler(&mockFeedService{err: errors.New("database error")}, feed.Config{})
+		w := httptest.NewRecorder()
+
+		handler.HandleGetGoogleMerchantReport(w, httptest.NewRequest("GET", "/feeds/google-merchant/report", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
@@ -16,11 +19,26 @@ const (
 	defaultLimit  = 10
 	minLimit      = 1
 	maxLimit      = 100
+
+	maxBatchSize = 50
 )
 
 type catalogResponse struct {
 	Products []mapper.ProductResponse `json:"products"`
 	Total    int                      `json:"total"`
+	Facets   *mapper.FacetsResponse   `json:"facets,omitempty"`
+}
+
+// projectedCatalogResponse is the catalog response when a sparse fieldset or include was requested.
+type projectedCatalogResponse struct {
+	Products []map[string]json.RawMessage `json:"products"`
+	Total    int                          `json:"total"`
+	Facets   *mapper.FacetsResponse       `json:"facets,omitempty"`
+}
+
+type batchResponse struct {
+	Products []mapper.ProductDetailResponse `json:"products"`
+	Missing  []string                       `json:"missing"`
 }
 
 // CatalogHandler handles HTTP requests for the product catalog.
@@ -34,7 +52,8 @@ func NewCatalogHandler(service catalog.Service) *CatalogHandler {
 }
 
 // HandleGet handles GET /catalog requests.
-// Supports optional query parameters: offset, limit, category, and priceLessThan.
+// Supports optional query parameters: offset, limit, category, priceLessThan, facets,
+// fields, include and expand.
 func (h *CatalogHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	offset, limit, err := parsePaginationParams(r)
 	if err != nil {
@@ -48,22 +67,68 @@ func (h *CatalogHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	products, discountedPrices, discountPercentages, total, err := h.service.GetProducts(offset, limit, filters)
+	facetRequest, err := parseFacetParams(r)
 	if err != nil {
-		errorResponse(w, http.StatusInternalServerError, err.Error())
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	shape, err := parseShapeParams(r, product.Relations{Category: true})
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	details, total, err := h.service.GetProducts(r.Context(), offset, limit, filters, shape.projection())
+	if err != nil {
+		serviceErrorResponse(w, r, err)
 		return
 	}
 
 	response := catalogResponse{
-		Products: mapper.ToProductResponses(products, discountedPrices, discountPercentages),
+		Products: make([]mapper.ProductResponse, len(details)),
 		Total:    int(total),
 	}
+	for i, d := range details {
+		response.Products[i] = mapper.ToProductResponse(d.Product, d.DiscountedPrice, d.Percentage)
+		response.Products[i].DiscountLimit = mapper.ToDiscountLimitResponse(d.Limit)
+		if shape.relations.Variants {
+			response.Products[i].Variants = mapper.ToVariantResponses(d.Product.Variants, toVariantDiscountInfo(d.VariantDiscounts))
+		}
+	}
 
-	okResponse(w, response)
+	if facetRequest.Any() {
+		facets, err := h.service.GetFacets(r.Context(), filters, facetRequest)
+		if err != nil {
+			serviceErrorResponse(w, r, err)
+			return
+		}
+		response.Facets = mapper.ToFacetsResponse(facets)
+	}
+
+	if !shape.custom {
+		okResponse(w, response)
+		return
+	}
+
+	projected := projectedCatalogResponse{
+		Products: make([]map[string]json.RawMessage, len(response.Products)),
+		Total:    response.Total,
+		Facets:   response.Facets,
+	}
+	for i, p := range response.Products {
+		if projected.Products[i], err = shape.project(p, details[i].Product.Category); err != nil {
+			errorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	okResponse(w, projected)
 }
 
 // HandleGetByCode handles GET /catalog/:code requests.
 // Returns product information including variants.
+// Supports optional query parameters: fields, include and expand.
 func (h *CatalogHandler) HandleGetByCode(w http.ResponseWriter, r *http.Request) {
 	code := r.PathValue("code")
 	if code == "" {
@@ -71,23 +136,96 @@ func (h *CatalogHandler) HandleGetByCode(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	product, discountedPrice, discountPercentage, variantDiscounts, err := h.service.GetProductByCode(code)
+	shape, err := parseShapeParams(r, product.AllRelations())
 	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	detail, err := h.service.GetProductByCode(r.Context(), code, shape.projection())
+	if errors.Is(err, product.ErrNotFound) {
 		errorResponse(w, http.StatusNotFound, fmt.Sprintf("product with code %s not found", code))
 		return
 	}
+	if err != nil {
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	setLastModified(w, detail.Product.LastModified())
+
+	response := mapper.ToProductDetailResponse(detail.Product, detail.DiscountedPrice, detail.Percentage, toVariantDiscountInfo(detail.VariantDiscounts))
+	response.DiscountLimit = mapper.ToDiscountLimitResponse(detail.Limit)
+	if !shape.custom {
+		okResponse(w, response)
+		return
+	}
 
-	// Convert service VariantDiscount to mapper VariantDiscountInfo
-	mapperVariantDiscounts := make(map[string]mapper.VariantDiscountInfo)
+	projected, err := shape.project(response, detail.Product.Category)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	okResponse(w, projected)
+}
+
+// HandlePostBatch handles POST /catalog/batch requests.
+// Returns the requested products in request order and lists unknown codes separately.
+func (h *CatalogHandler) HandlePostBatch(w http.ResponseWriter, r *http.Request) {
+	var req mapper.BatchProductsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(req.Codes) == 0 {
+		errorResponse(w, http.StatusBadRequest, "codes is required")
+		return
+	}
+
+	if len(req.Codes) > maxBatchSize {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("codes must not exceed %d items", maxBatchSize))
+		return
+	}
+
+	for _, code := range req.Codes {
+		if code == "" {
+			errorResponse(w, http.StatusBadRequest, "codes must not contain empty values")
+			return
+		}
+	}
+
+	details, missing, err := h.service.GetProductsByCodes(r.Context(), req.Codes)
+	if err != nil {
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	response := batchResponse{
+		Products: make([]mapper.ProductDetailResponse, len(details)),
+		Missing:  missing,
+	}
+	for i, d := range details {
+		response.Products[i] = mapper.ToProductDetailResponse(d.Product, d.DiscountedPrice, d.Percentage, toVariantDiscountInfo(d.VariantDiscounts))
+		response.Products[i].DiscountLimit = mapper.ToDiscountLimitResponse(d.Limit)
+	}
+
+	okResponse(w, response)
+}
+
+// toVariantDiscountInfo converts service variant discounts to mapper variant discounts.
+func toVariantDiscountInfo(variantDiscounts map[string]catalog.VariantDiscount) map[string]mapper.VariantDiscountInfo {
+	info := make(map[string]mapper.VariantDiscountInfo, len(variantDiscounts))
 	for sku, discount := range variantDiscounts {
-		mapperVariantDiscounts[sku] = mapper.VariantDiscountInfo{
+		info[sku] = mapper.VariantDiscountInfo{
 			DiscountedPrice: discount.DiscountedPrice,
 			Percentage:      discount.Percentage,
+			Limit:           discount.Limit,
 		}
 	}
-
-	response := mapper.ToProductDetailResponse(*product, discountedPrice, discountPercentage, mapperVariantDiscounts)
-	okResponse(w, response)
+	return info
 }
 
 func parsePaginationParams(r *http.Request) (offset, limit int, err error) {
@@ -138,5 +276,38 @@ func parseFilterParams(r *http.Request) (product.Filter, error) {
 		filters.PriceLessThan = &price
 	}
 
+	if inStockStr := r.URL.Query().Get("inStock"); inStockStr != "" {
+		inStock, err := strconv.ParseBool(inStockStr)
+		if err != nil {
+			return filters, fmt.Errorf("invalid inStock parameter")
+		}
+		filters.InStock = inStock
+	}
+
 	return filters, nil
 }
+
+func parseFacetParams(r *http.Request) (product.FacetRequest, error) {
+	var req product.FacetRequest
+
+	facetsStr := r.URL.Query().Get("facets")
+	if facetsStr == "" {
+		return req, nil
+	}
+
+	for _, name := range strings.Split(facetsStr, ",") {
+		switch strings.TrimSpace(name) {
+		case product.FacetCategory:
+			req.Category = true
+		case product.FacetPrice:
+			req.Price = true
+		case product.FacetOnSale:
+			req.OnSale = true
+		default:
+			return req, fmt.Errorf("invalid facet %q, supported facets: %s, %s, %s",
+				name, product.FacetCategory, product.FacetPrice, product.FacetOnSale)
+		}
+	}
+
+	return req, nil
+}
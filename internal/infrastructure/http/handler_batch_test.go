@@ -0,0 +1,99 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeBatchRequest(handler *CatalogHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/catalog/batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.HandlePostBatch(w, req)
+	return w
+}
+
+func TestHandlePostBatch(t *testing.T) {
+	t.Run("returns found products in request order and missing codes", func(t *testing.T) {
+		service := newMockService(createTestProducts(3), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeBatchRequest(handler, `{"codes":["PROD003","UNKNOWN","PROD001"]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response batchResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Products, 2)
+		assert.Equal(t, "PROD003", response.Products[0].Code)
+		assert.Equal(t, "PROD001", response.Products[1].Code)
+		assert.Equal(t, []string{"UNKNOWN"}, response.Missing)
+	})
+
+	t.Run("returns empty missing list when every code exists", func(t *testing.T) {
+		service := newMockService(createTestProducts(2), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeBatchRequest(handler, `{"codes":["PROD001"]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"missing":[]`)
+	})
+
+	t.Run("returns 400 when request body is invalid", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(nil, nil))
+
+		w := makeBatchRequest(handler, "invalid json")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid request body")
+	})
+
+	t.Run("returns 400 when codes is empty", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(nil, nil))
+
+		w := makeBatchRequest(handler, `{"codes":[]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "codes is required")
+	})
+
+	t.Run("returns 400 when codes contains an empty value", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(nil, nil))
+
+		w := makeBatchRequest(handler, `{"codes":["PROD001",""]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns 400 when batch is too large", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(nil, nil))
+
+		codes := make([]string, maxBatchSize+1)
+		for i := range codes {
+			codes[i] = fmt.Sprintf("%q", fmt.Sprintf("PROD%03d", i))
+		}
+
+		w := makeBatchRequest(handler, `{"codes":[`+strings.Join(codes, ",")+`]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "must not exceed")
+	})
+
+	t.Run("returns 500 when service fails", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(nil, errors.New("database error")))
+
+		w := makeBatchRequest(handler, `{"codes":["PROD001"]}`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -21,11 +23,17 @@ type mockDetailService struct {
 	err              error
 }
 
-func (m *mockDetailService) GetProductByCode(code string) (*product.Product, float64, int, map[string]catalog.VariantDiscount, error) {
+func (m *mockDetailService) GetProductByCode(ctx context.Context, code string, projection catalog.Projection) (*catalog.ProductDetail, error) {
+	m.projection = projection
 	if m.err != nil {
-		return nil, 0, 0, nil, m.err
+		return nil, m.err
 	}
-	return m.product, m.discountedPrice, m.percentage, m.variantDiscounts, nil
+	return &catalog.ProductDetail{
+		Product:          *m.product,
+		DiscountedPrice:  m.discountedPrice,
+		Percentage:       m.percentage,
+		VariantDiscounts: m.variantDiscounts,
+	}, nil
 }
 
 func TestHandleGetByCode_Success(t *testing.T) {
@@ -133,7 +141,7 @@ func TestHandleGetByCode_Error(t *testing.T) {
 	})
 
 	t.Run("returns 404 when product not found", func(t *testing.T) {
-		service := &mockDetailService{err: errors.New("not found")}
+		service := &mockDetailService{err: product.ErrNotFound}
 		handler := NewCatalogHandler(service)
 
 		req := httptest.NewRequest("GET", "/catalog/NONEXISTENT", nil)
@@ -143,6 +151,33 @@ func TestHandleGetByCode_Error(t *testing.T) {
 		handler.HandleGetByCode(w, req)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
-		assert.Contains(t, w.Body.String(), "not found")
+		assert.Contains(t, w.Body.String(), "product with code NONEXISTENT not found")
+	})
+
+	t.Run("returns 500 when the lookup fails", func(t *testing.T) {
+		service := &mockDetailService{err: errors.New("connection refused")}
+		handler := NewCatalogHandler(service)
+
+		req := httptest.NewRequest("GET", "/catalog/PROD001", nil)
+		req.SetPathValue("code", "PROD001")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetByCode(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "connection refused")
+	})
+
+	t.Run("returns 504 when the lookup times out", func(t *testing.T) {
+		service := &mockDetailService{err: fmt.Errorf("query: %w", context.DeadlineExceeded)}
+		handler := NewCatalogHandler(service)
+
+		req := httptest.NewRequest("GET", "/catalog/PROD001", nil)
+		req.SetPathValue("code", "PROD001")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetByCode(w, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
 	})
 }
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGet_Facets(t *testing.T) {
+	t.Run("omits facets when not requested", func(t *testing.T) {
+		service := newMockService(createTestProducts(2), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "facets")
+	})
+
+	t.Run("returns requested facets next to products", func(t *testing.T) {
+		service := newMockService(createTestProducts(2), nil)
+		service.facets = product.Facets{
+			Categories: []product.CategoryCount{
+				{Code: "clothing", Count: 3},
+				{Code: "shoes", Count: 1},
+			},
+			OnSale: &product.OnSaleCount{OnSale: 1, NotOnSale: 3},
+		}
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog?category=clothing&facets=category,onSale")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, product.FacetRequest{Category: true, OnSale: true}, service.facetRequest)
+
+		response := parseResponse(t, w)
+		require.NotNil(t, response.Facets)
+		assert.Len(t, response.Facets.Category, 2)
+		assert.Equal(t, "clothing", response.Facets.Category[0].Code)
+		assert.Equal(t, int64(3), response.Facets.Category[0].Count)
+		require.NotNil(t, response.Facets.OnSale)
+		assert.Equal(t, int64(1), response.Facets.OnSale.OnSale)
+		assert.Equal(t, int64(3), response.Facets.OnSale.NotOnSale)
+		assert.Nil(t, response.Facets.Price)
+	})
+
+	t.Run("returns 400 for unknown facet", func(t *testing.T) {
+		service := newMockService(createTestProducts(2), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog?facets=category,color")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid facet")
+	})
+
+	t.Run("returns 500 when facet computation fails", func(t *testing.T) {
+		service := newMockService(createTestProducts(2), nil)
+		service.facetsErr = errors.New("database error")
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog?facets=price")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
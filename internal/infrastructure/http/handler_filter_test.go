@@ -226,6 +226,35 @@ func TestHandleGet_Filters(t *testing.T) {
 	}
 }
 
+func TestHandleGet_InStockFilter(t *testing.T) {
+	inStock := newTestProduct(1, "PROD001", 65.76, categoryClothing)
+	inStock.Variants = []product.Variant{{SKU: "SKU001A", Quantity: 0}, {SKU: "SKU001B", Quantity: 2}}
+	soldOut := newTestProduct(2, "PROD002", 12.54, categoryShoes)
+	soldOut.Variants = []product.Variant{{SKU: "SKU002A", Quantity: 0}}
+	noVariants := newTestProduct(3, "PROD003", 9.99, categoryAccessories)
+	testProducts := []product.Product{inStock, soldOut, noVariants}
+
+	t.Run("returns only products with an available variant", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(testProducts, nil))
+
+		w := makeRequest(handler, "/catalog?inStock=true")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := parseResponse(t, w)
+		assert.Equal(t, 1, response.Total)
+		assert.Equal(t, "PROD001", response.Products[0].Code)
+	})
+
+	t.Run("returns every product when inStock is false", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(testProducts, nil))
+
+		w := makeRequest(handler, "/catalog?inStock=false")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 3, parseResponse(t, w).Total)
+	})
+}
+
 func TestHandleGet_FilterValidation(t *testing.T) {
 	testProducts := setupFilterTestProducts()
 
@@ -254,6 +283,11 @@ func TestHandleGet_FilterValidation(t *testing.T) {
 			queryParams:       "priceLessThan=10.5.5",
 			expectedErrorText: "priceLessThan",
 		},
+		{
+			name:              "invalid inStock",
+			queryParams:       "inStock=maybe",
+			expectedErrorText: "inStock",
+		},
 	}
 
 	for _, tt := range tests {
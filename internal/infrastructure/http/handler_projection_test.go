@@ -0,0 +1,230 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseProjectedResponse(t *testing.T, w *httptest.ResponseRecorder) projectedCatalogResponse {
+	var response projectedCatalogResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), "Failed to parse response JSON")
+	return response
+}
+
+func TestHandleGet_Projection(t *testing.T) {
+	t.Run("uses default shape without fields or include", func(t *testing.T) {
+		service := newMockService(createTestProducts(2), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, product.Relations{Category: true}, service.projection.Relations)
+		assert.True(t, service.projection.Pricing)
+		assert.NotContains(t, w.Body.String(), "variants")
+	})
+
+	t.Run("returns only requested fields", func(t *testing.T) {
+		service := newMockService(createTestProducts(2), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog?fields=code,final_price")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, service.projection.Pricing)
+
+		response := parseProjectedResponse(t, w)
+		require.Len(t, response.Products, 2)
+		assert.Equal(t, `"PROD001"`, string(response.Products[0]["code"]))
+		assert.NotContains(t, response.Products[0], "price")
+		assert.NotContains(t, response.Products[0], "category")
+		assert.Equal(t, 2, response.Total)
+	})
+
+	t.Run("skips relations and pricing when only plain fields are requested", func(t *testing.T) {
+		service := newMockService(createTestProducts(2), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog?fields=code,price&include=")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, product.Relations{}, service.projection.Relations)
+		assert.False(t, service.projection.Pricing)
+	})
+
+	t.Run("includes variants in the listing when requested", func(t *testing.T) {
+		p := newTestProduct(1, "PROD001", 10, categoryClothing)
+		p.Variants = []product.Variant{{SKU: "SKU001A", Price: decimal.NewFromFloat(11)}}
+		service := newMockService([]product.Product{p}, nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog?include=variants")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, product.Relations{Variants: true}, service.projection.Relations)
+
+		response := parseProjectedResponse(t, w)
+		require.Len(t, response.Products, 1)
+		assert.Contains(t, string(response.Products[0]["variants"]), "SKU001A")
+		assert.NotContains(t, response.Products[0], "category")
+	})
+
+	t.Run("returns 400 for unknown field", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(createTestProducts(2), nil))
+
+		w := makeRequest(handler, "/catalog?fields=code,color")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid field")
+	})
+
+	t.Run("returns 400 for unknown include", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(createTestProducts(2), nil))
+
+		w := makeRequest(handler, "/catalog?include=reviews")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid include")
+	})
+}
+
+func TestHandleGetByCode_Projection(t *testing.T) {
+	p := product.Product{
+		ID:       1,
+		Code:     "PROD001",
+		Price:    decimal.NewFromFloat(100.00),
+		Category: &product.Category{Code: "shoes"},
+		Variants: []product.Variant{{ID: 1, SKU: "VAR001", Price: decimal.NewFromFloat(100.00)}},
+	}
+
+	t.Run("loads every relation by default", func(t *testing.T) {
+		service := &mockDetailService{product: &p, discountedPrice: 100.00}
+		handler := NewCatalogHandler(service)
+
+		req := httptest.NewRequest("GET", "/catalog/PROD001", nil)
+		req.SetPathValue("code", "PROD001")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetByCode(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, product.AllRelations(), service.projection.Relations)
+		assert.Contains(t, w.Body.String(), "VAR001")
+	})
+
+	t.Run("omits variants when only the category is included", func(t *testing.T) {
+		service := &mockDetailService{product: &p, discountedPrice: 100.00}
+		handler := NewCatalogHandler(service)
+
+		req := httptest.NewRequest("GET", "/catalog/PROD001?include=category", nil)
+		req.SetPathValue("code", "PROD001")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetByCode(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, product.Relations{Category: true}, service.projection.Relations)
+
+		var response map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.NotContains(t, response, "variants")
+		assert.Equal(t, `"shoes"`, string(response["category"]))
+	})
+
+	t.Run("returns only requested fields", func(t *testing.T) {
+		service := &mockDetailService{product: &p, discountedPrice: 100.00}
+		handler := NewCatalogHandler(service)
+
+		req := httptest.NewRequest("GET", "/catalog/PROD001?fields=code", nil)
+		req.SetPathValue("code", "PROD001")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetByCode(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"code":"PROD001"}`, w.Body.String())
+		assert.False(t, service.projection.Pricing)
+	})
+}
+
+func TestHandleGet_ExpandCategory(t *testing.T) {
+	t.Run("returns category objects instead of codes", func(t *testing.T) {
+		products := []product.Product{
+			newTestProduct(1, "PROD001", 10, categoryClothing),
+			newTestProduct(2, "PROD002", 20, nil),
+		}
+		service := newMockService(products, nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog?expand=category")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, service.projection.Relations.Category)
+
+		response := parseProjectedResponse(t, w)
+		require.Len(t, response.Products, 2)
+		assert.JSONEq(t, `{"code":"clothing","name":"Clothing"}`, string(response.Products[0]["category"]))
+		assert.Equal(t, "null", string(response.Products[1]["category"]))
+		assert.Contains(t, response.Products[0], "price")
+	})
+
+	t.Run("expands category within a sparse fieldset", func(t *testing.T) {
+		service := newMockService(createTestProducts(1), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog?fields=code&expand=category")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		response := parseProjectedResponse(t, w)
+		require.Len(t, response.Products, 1)
+		assert.NotContains(t, response.Products[0], "category")
+	})
+
+	t.Run("keeps category codes without expand", func(t *testing.T) {
+		service := newMockService(createTestProducts(1), nil)
+		handler := NewCatalogHandler(service)
+
+		w := makeRequest(handler, "/catalog")
+
+		response := parseResponse(t, w)
+		assert.Equal(t, "clothing", response.Products[0].Category)
+	})
+
+	t.Run("returns 400 for unknown expansion", func(t *testing.T) {
+		handler := NewCatalogHandler(newMockService(createTestProducts(1), nil))
+
+		w := makeRequest(handler, "/catalog?expand=variants")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid expand")
+	})
+}
+
+func TestHandleGetByCode_ExpandCategory(t *testing.T) {
+	t.Run("returns category object in product detail", func(t *testing.T) {
+		p := newTestProduct(1, "PROD001", 10, categoryShoes)
+		service := &mockDetailService{product: &p, discountedPrice: 10}
+		handler := NewCatalogHandler(service)
+
+		req := httptest.NewRequest("GET", "/catalog/PROD001?expand=category", nil)
+		req.SetPathValue("code", "PROD001")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetByCode(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.JSONEq(t, `{"code":"shoes","name":"Shoes"}`, string(response["category"]))
+		assert.Contains(t, response, "variants")
+	})
+}
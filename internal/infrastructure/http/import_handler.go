@@ -0,0 +1,118 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/importer"
+)
+
+// maxImportSize bounds the CSV accepted by the import endpoint.
+const maxImportSize = 10 << 20
+
+type importCountsResponse struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+type importRowResponse struct {
+	Line        int      `json:"line"`
+	ProductCode string   `json:"productCode"`
+	VariantSKU  string   `json:"variantSku,omitempty"`
+	Product     string   `json:"product,omitempty"`
+	Variant     string   `json:"variant,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+type importReportResponse struct {
+	DryRun    bool                 `json:"dryRun"`
+	Committed bool                 `json:"committed"`
+	Failed    int                  `json:"failed"`
+	Products  importCountsResponse `json:"products"`
+	Variants  importCountsResponse `json:"variants"`
+	Rows      []importRowResponse  `json:"rows"`
+}
+
+// ImportHandler handles HTTP requests for bulk product imports.
+type ImportHandler struct {
+	service importer.Service
+}
+
+// NewImportHandler creates a new import HTTP handler.
+func NewImportHandler(service importer.Service) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// HandlePost handles POST /catalog/import requests.
+// The body is a CSV with the columns product_code, price, category_code,
+// variant_sku, variant_name and variant_price. Returns the per-row report,
+// with 422 when any row failed and nothing was written.
+func (h *ImportHandler) HandlePost(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "dryRun must be true or false")
+			return
+		}
+		dryRun = parsed
+	}
+
+	rows, err := importer.ParseCSV(http.MaxBytesReader(w, r.Body, maxImportSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		errorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body must not exceed %d bytes", maxImportSize))
+		return
+	}
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(rows) == 0 {
+		errorResponse(w, http.StatusBadRequest, "no rows to import")
+		return
+	}
+
+	report, err := h.service.Import(r.Context(), rows, dryRun)
+	if err != nil {
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	jsonResponse(w, status, toImportReportResponse(report))
+}
+
+func toImportReportResponse(report importer.Report) importReportResponse {
+	response := importReportResponse{
+		DryRun:    report.DryRun,
+		Committed: report.Committed,
+		Failed:    report.Failed,
+		Products:  importCountsResponse(report.Products),
+		Variants:  importCountsResponse(report.Variants),
+		Rows:      make([]importRowResponse, len(report.Rows)),
+	}
+
+	for i, row := range report.Rows {
+		response.Rows[i] = importRowResponse{
+			Line:        row.Line,
+			ProductCode: row.ProductCode,
+			VariantSKU:  row.VariantSKU,
+			Errors:      row.Errors,
+		}
+		if len(row.Errors) > 0 || report.Failed > 0 {
+			continue
+		}
+		response.Rows[i].Product = row.Product.String()
+		if row.VariantSKU != "" {
+			response.Rows[i].Variant = row.Variant.String()
+		}
+	}
+	return response
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/importer"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const importHeader = "product_code,price,category_code,variant_sku,variant_name,variant_price\n"
+
+type mockImportService struct {
+	rows   []importer.Row
+	dryRun bool
+	report importer.Report
+	err    error
+}
+
+func (m *mockImportService) Import(ctx context.Context, rows []importer.Row, dryRun bool) (importer.Report, error) {
+	m.rows = rows
+	m.dryRun = dryRun
+	return m.report, m.err
+}
+
+func TestImportHandler_HandlePost(t *testing.T) {
+	t.Run("imports the rows and returns the report", func(t *testing.T) {
+		service := &mockImportService{report: importer.Report{
+			Committed: true,
+			Products:  importer.Counts{Created: 1},
+			Variants:  importer.Counts{Updated: 1},
+			Rows: []importer.RowResult{
+				{Line: 2, ProductCode: "PROD010", VariantSKU: "SKU010A", Product: product.Created, Variant: product.Updated},
+				{Line: 3, ProductCode: "PROD010", Product: product.Created},
+			},
+		}}
+		handler := NewImportHandler(service)
+
+		body := importHeader + "PROD010,25.00,shoes,SKU010A,Variant A,\nPROD010,25.00,shoes,,,\n"
+		req := httptest.NewRequest("POST", "/catalog/import", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.HandlePost(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, service.dryRun)
+		require.Len(t, service.rows, 2)
+		assert.Equal(t, "SKU010A", service.rows[0].VariantSKU)
+		assert.JSONEq(t, `{
+			"dryRun": false,
+			"committed": true,
+			"failed": 0,
+			"products": {"created": 1, "updated": 0, "unchanged": 0},
+			"variants": {"created": 0, "updated": 1, "unchanged": 0},
+			"rows": [
+				{"line": 2, "productCode": "PROD010", "variantSku": "SKU010A", "product": "created", "variant": "updated"},
+				{"line": 3, "productCode": "PROD010", "product": "created"}
+			]
+		}`, w.Body.String())
+	})
+
+	t.Run("passes dry runs to the service", func(t *testing.T) {
+		service := &mockImportService{report: importer.Report{DryRun: true}}
+		handler := NewImportHandler(service)
+
+		req := httptest.NewRequest("POST", "/catalog/import?dryRun=true", strings.NewReader(importHeader+"PROD010,25.00,,,,\n"))
+		w := httptest.NewRecorder()
+
+		handler.HandlePost(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, service.dryRun)
+		assert.Contains(t, w.Body.String(), `"dryRun":true`)
+	})
+
+	t.Run("returns 422 with row errors when any row fails", func(t *testing.T) {
+		service := &mockImportService{report: importer.Report{
+			Failed: 1,
+			Rows: []importer.RowResult{
+				{Line: 2, ProductCode: "PROD010", Product: product.Created},
+				{Line: 3, Errors: []string{"product_code is required"}},
+			},
+		}}
+		handler := NewImportHandler(service)
+
+		req := httptest.NewRequest("POST", "/catalog/import", strings.NewReader(importHeader+"PROD010,25.00,,,,\n,1,,,,\n"))
+		w := httptest.NewRecorder()
+
+		handler.HandlePost(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `{"line":2,"productCode":"PROD010"}`)
+		assert.Contains(t, w.Body.String(), `"errors":["product_code is required"]`)
+	})
+
+	t.Run("returns 400 for invalid requests", func(t *testing.T) {
+		tests := map[string]struct {
+			target string
+			body   string
+			error  string
+		}{
+			"invalid dryRun": {"/catalog/import?dryRun=maybe", importHeader, "dryRun must be true or false"},
+			"missing column": {"/catalog/import", "product_code,price\nPROD010,25.00\n", "invalid CSV: missing column category_code"},
+			"no rows":        {"/catalog/import", importHeader, "no rows to import"},
+		}
+		for name, tt := range tests {
+			handler := NewImportHandler(&mockImportService{})
+			w := httptest.NewRecorder()
+
+			handler.HandlePost(w, httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, name)
+			assert.Contains(t, w.Body.String(), tt.error, name)
+		}
+	})
+
+	t.Run("returns 413 for oversized bodies", func(t *testing.T) {
+		handler := NewImportHandler(&mockImportService{})
+		body := importHeader + strings.Repeat("PROD010,25.00,,,,\n", maxImportSize/18+1)
+		w := httptest.NewRecorder()
+
+		handler.HandlePost(w, httptest.NewRequest("POST", "/catalog/import", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("returns 500 when the import fails", func(t *testing.T) {
+		handler := NewImportHandler(&mockImportService{err: errors.New("connection refused")})
+		w := httptest.NewRecorder()
+
+		handler.HandlePost(w, httptest.NewRequest("POST", "/catalog/import", strings.NewReader(importHeader+"PROD010,25.00,,,,\n")))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
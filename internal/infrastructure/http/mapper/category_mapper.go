@@ -8,6 +8,14 @@ type CategoryResponse struct {
 	Name string `json:"name"`
 }
 
+// CategorySummaryResponse is a category with its product counts in the API.
+type CategorySummaryResponse struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	ProductCount int64  `json:"productCount"`
+	OnSaleCount  int64  `json:"onSaleCount"`
+}
+
 // CreateCategoryRequest represents the request body for creating a category.
 type CreateCategoryRequest struct {
 	Code string `json:"code"`
@@ -30,3 +38,27 @@ func ToCategoryResponses(categories []product.Category) []CategoryResponse {
 	}
 	return responses
 }
+
+// ToCategoryRef converts an optional domain category to a response DTO.
+// Returns nil for products without a category.
+func ToCategoryRef(cat *product.Category) *CategoryResponse {
+	if cat == nil {
+		return nil
+	}
+	response := ToCategoryResponse(*cat)
+	return &response
+}
+
+// ToCategorySummaryResponses converts domain category summaries to response DTOs.
+func ToCategorySummaryResponses(summaries []product.CategorySummary) []CategorySummaryResponse {
+	responses := make([]CategorySummaryResponse, len(summaries))
+	for i, s := range summaries {
+		responses[i] = CategorySummaryResponse{
+			Code:         s.Code,
+			Name:         s.Name,
+			ProductCount: s.ProductCount,
+			OnSaleCount:  s.OnSaleCount,
+		}
+	}
+	return responses
+}
@@ -50,3 +50,31 @@ func TestToCategoryResponses(t *testing.T) {
 		assert.NotNil(t, responses)
 	})
 }
+
+func TestToCategoryRef(t *testing.T) {
+	t.Run("converts category to response DTO", func(t *testing.T) {
+		response := ToCategoryRef(&product.Category{ID: 1, Code: "boots", Name: "Boots"})
+
+		assert.Equal(t, &CategoryResponse{Code: "boots", Name: "Boots"}, response)
+	})
+
+	t.Run("returns nil without category", func(t *testing.T) {
+		assert.Nil(t, ToCategoryRef(nil))
+	})
+}
+
+func TestToCategorySummaryResponses(t *testing.T) {
+	t.Run("converts summaries with counts", func(t *testing.T) {
+		summaries := []product.CategorySummary{
+			{Category: product.Category{ID: 1, Code: "boots", Name: "Boots"}, ProductCount: 4, OnSaleCount: 4},
+			{Category: product.Category{ID: 2, Code: "kids", Name: "Kids"}},
+		}
+
+		responses := ToCategorySummaryResponses(summaries)
+
+		assert.Equal(t, []CategorySummaryResponse{
+			{Code: "boots", Name: "Boots", ProductCount: 4, OnSaleCount: 4},
+			{Code: "kids", Name: "Kids"},
+		}, responses)
+	})
+}
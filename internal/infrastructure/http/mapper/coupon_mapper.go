@@ -0,0 +1,57 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+)
+
+// CouponResponse represents a coupon and its usage in API responses. Open
+// window bounds are omitted and zero limits mean unlimited.
+type CouponResponse struct {
+	Code           string     `json:"code"`
+	Rule           string     `json:"rule"`
+	StartsAt       *time.Time `json:"startsAt,omitempty"`
+	EndsAt         *time.Time `json:"endsAt,omitempty"`
+	MaxRedemptions int        `json:"maxRedemptions"`
+	MaxPerCustomer int        `json:"maxPerCustomer"`
+	Redemptions    int        `json:"redemptions"`
+}
+
+// ToCouponResponse converts a coupon and its usage to a DTO.
+func ToCouponResponse(c discount.Coupon, usage discount.Usage) CouponResponse {
+	return CouponResponse{
+		Code:           c.Code,
+		Rule:           c.Rule,
+		StartsAt:       optionalTime(c.StartsAt),
+		EndsAt:         optionalTime(c.EndsAt),
+		MaxRedemptions: c.MaxRedemptions,
+		MaxPerCustomer: c.MaxPerCustomer,
+		Redemptions:    usage.Total,
+	}
+}
+
+// RedeemCouponRequest names the customer redeeming a coupon.
+type RedeemCouponRequest struct {
+	CustomerID string `json:"customerId"`
+}
+
+// RedemptionResponse represents a coupon redemption in API responses.
+type RedemptionResponse struct {
+	Code       string    `json:"code"`
+	CustomerID string    `json:"customerId"`
+	RedeemedAt time.Time `json:"redeemedAt"`
+}
+
+// ToRedemptionResponse converts a redemption to a DTO.
+func ToRedemptionResponse(r discount.Redemption) RedemptionResponse {
+	return RedemptionResponse{Code: r.Code, CustomerID: r.CustomerID, RedeemedAt: r.RedeemedAt.UTC()}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,66 @@
+package mapper
+
+import "github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+
+// FacetsResponse holds the facet counts requested through the facets parameter.
+type FacetsResponse struct {
+	Category []CategoryFacetResponse `json:"category,omitempty"`
+	Price    []PriceFacetResponse    `json:"price,omitempty"`
+	OnSale   *OnSaleFacetResponse    `json:"onSale,omitempty"`
+}
+
+// CategoryFacetResponse is the number of products in a category.
+type CategoryFacetResponse struct {
+	Code  string `json:"code"`
+	Count int64  `json:"count"`
+}
+
+// PriceFacetResponse is the number of products in a price bucket.
+// Max is omitted for the last, unbounded bucket.
+type PriceFacetResponse struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// OnSaleFacetResponse splits products by whether a discount applies.
+type OnSaleFacetResponse struct {
+	OnSale    int64 `json:"true"`
+	NotOnSale int64 `json:"false"`
+}
+
+// ToFacetsResponse converts domain facets to a response DTO.
+func ToFacetsResponse(f product.Facets) *FacetsResponse {
+	response := &FacetsResponse{}
+
+	if f.Categories != nil {
+		response.Category = make([]CategoryFacetResponse, len(f.Categories))
+		for i, c := range f.Categories {
+			response.Category[i] = CategoryFacetResponse{Code: c.Code, Count: c.Count}
+		}
+	}
+
+	if f.PriceBuckets != nil {
+		response.Price = make([]PriceFacetResponse, len(f.PriceBuckets))
+		for i, b := range f.PriceBuckets {
+			bucket := PriceFacetResponse{
+				Min:   b.Bucket.Min.InexactFloat64(),
+				Count: b.Count,
+			}
+			if b.Bucket.Max != nil {
+				upper := b.Bucket.Max.InexactFloat64()
+				bucket.Max = &upper
+			}
+			response.Price[i] = bucket
+		}
+	}
+
+	if f.OnSale != nil {
+		response.OnSale = &OnSaleFacetResponse{
+			OnSale:    f.OnSale.OnSale,
+			NotOnSale: f.OnSale.NotOnSale,
+		}
+	}
+
+	return response
+}
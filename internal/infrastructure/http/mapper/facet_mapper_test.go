@@ -0,0 +1,47 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToFacetsResponse(t *testing.T) {
+	t.Run("maps price buckets with open upper bound", func(t *testing.T) {
+		buckets := product.DefaultPriceBuckets()
+		facets := product.Facets{
+			PriceBuckets: []product.PriceBucketCount{
+				{Bucket: buckets[0], Count: 4},
+				{Bucket: buckets[len(buckets)-1], Count: 1},
+			},
+		}
+
+		response := ToFacetsResponse(facets)
+
+		require.Len(t, response.Price, 2)
+		assert.Equal(t, 0.0, response.Price[0].Min)
+		require.NotNil(t, response.Price[0].Max)
+		assert.Equal(t, 25.0, *response.Price[0].Max)
+		assert.Equal(t, int64(4), response.Price[0].Count)
+		assert.Equal(t, 250.0, response.Price[1].Min)
+		assert.Nil(t, response.Price[1].Max)
+		assert.Nil(t, response.Category)
+		assert.Nil(t, response.OnSale)
+	})
+
+	t.Run("maps category and on sale counts", func(t *testing.T) {
+		facets := product.Facets{
+			Categories: []product.CategoryCount{{Code: "boots", Count: 2}},
+			OnSale:     &product.OnSaleCount{OnSale: 2, NotOnSale: 5},
+		}
+
+		response := ToFacetsResponse(facets)
+
+		assert.Equal(t, []CategoryFacetResponse{{Code: "boots", Count: 2}}, response.Category)
+		require.NotNil(t, response.OnSale)
+		assert.Equal(t, int64(2), response.OnSale.OnSale)
+		assert.Equal(t, int64(5), response.OnSale.NotOnSale)
+	})
+}
@@ -3,16 +3,35 @@ package mapper
 import (
 	"fmt"
 
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
 )
 
 // ProductResponse is a product in the catalog API response.
 type ProductResponse struct {
-	Code       string   `json:"code"`
-	Price      float64  `json:"price"`
-	Category   string   `json:"category"`
-	Discount   *string  `json:"discount,omitempty"`
-	FinalPrice *float64 `json:"final_price,omitempty"`
+	Code          string                 `json:"code"`
+	Price         float64                `json:"price"`
+	Category      string                 `json:"category"`
+	Discount      *string                `json:"discount,omitempty"`
+	FinalPrice    *float64               `json:"final_price,omitempty"`
+	DiscountLimit *DiscountLimitResponse `json:"discount_limit,omitempty"`
+	Variants      []VariantResponse      `json:"variants,omitempty"`
+}
+
+// DiscountLimitResponse explains how a guardrail changed a discount.
+// Requested is the percentage of the matching rule.
+type DiscountLimitResponse struct {
+	Kind      string `json:"kind"`
+	Requested int    `json:"requested"`
+	Reason    string `json:"reason"`
+}
+
+// ToDiscountLimitResponse converts a guardrail limit to a DTO, nil without one.
+func ToDiscountLimitResponse(limit *discount.Limit) *DiscountLimitResponse {
+	if limit == nil {
+		return nil
+	}
+	return &DiscountLimitResponse{Kind: string(limit.Kind), Requested: limit.Requested, Reason: limit.Reason}
 }
 
 // ToProductResponse converts a domain product to a DTO.
@@ -50,52 +69,75 @@ func ToProductResponses(products []product.Product, discountedPrices []float64,
 
 // VariantResponse represents a product variant in the response.
 type VariantResponse struct {
-	Code       string   `json:"code"`
-	Price      float64  `json:"price"`
-	Discount   *string  `json:"discount,omitempty"`
-	FinalPrice *float64 `json:"final_price,omitempty"`
+	Code          string                 `json:"code"`
+	Price         float64                `json:"price"`
+	Discount      *string                `json:"discount,omitempty"`
+	FinalPrice    *float64               `json:"final_price,omitempty"`
+	DiscountLimit *DiscountLimitResponse `json:"discount_limit,omitempty"`
+	Available     bool                   `json:"available"`
+	Quantity      int                    `json:"quantity"`
 }
 
 // ProductDetailResponse represents product information with variants.
 type ProductDetailResponse struct {
-	Code       string            `json:"code"`
-	Price      float64           `json:"price"`
-	Category   string            `json:"category"`
-	Discount   *string           `json:"discount,omitempty"`
-	FinalPrice *float64          `json:"final_price,omitempty"`
-	Variants   []VariantResponse `json:"variants"`
+	Code          string                 `json:"code"`
+	Price         float64                `json:"price"`
+	Category      string                 `json:"category"`
+	Discount      *string                `json:"discount,omitempty"`
+	FinalPrice    *float64               `json:"final_price,omitempty"`
+	DiscountLimit *DiscountLimitResponse `json:"discount_limit,omitempty"`
+	Variants      []VariantResponse      `json:"variants"`
+}
+
+// BatchProductsRequest represents the request body for a batch product lookup.
+type BatchProductsRequest struct {
+	Codes []string `json:"codes"`
 }
 
 // VariantDiscountInfo holds discount information for a variant.
 type VariantDiscountInfo struct {
 	DiscountedPrice float64
 	Percentage      int
+	Limit           *discount.Limit
 }
 
-// ToProductDetailResponse converts a domain product with variants to DTO.
-func ToProductDetailResponse(p product.Product, discountedPrice float64, discountPercentage int, variantDiscounts map[string]VariantDiscountInfo) ProductDetailResponse {
-	categoryCode := ""
-	if p.Category != nil {
-		categoryCode = p.Category.Code
-	}
-
-	variants := make([]VariantResponse, len(p.Variants))
-	for i, v := range p.Variants {
+// ToVariantResponses converts domain variants to DTOs, applying the variant-specific
+// discount when available.
+func ToVariantResponses(variants []product.Variant, variantDiscounts map[string]VariantDiscountInfo) []VariantResponse {
+	responses := make([]VariantResponse, len(variants))
+	for i, v := range variants {
 		variant := VariantResponse{
-			Code:  v.SKU,
-			Price: v.Price.InexactFloat64(),
+			Code:      v.SKU,
+			Price:     v.Price.InexactFloat64(),
+			Available: v.Available(),
+			Quantity:  v.Quantity,
 		}
 
 		// Apply variant-specific discount if available
-		if discountInfo, ok := variantDiscounts[v.SKU]; ok && discountInfo.Percentage > 0 {
+		discountInfo, ok := variantDiscounts[v.SKU]
+		if ok && discountInfo.Percentage > 0 {
 			discountStr := fmt.Sprintf("%d%%", discountInfo.Percentage)
 			variant.Discount = &discountStr
 			variant.FinalPrice = &discountInfo.DiscountedPrice
 		}
+		if ok {
+			variant.DiscountLimit = ToDiscountLimitResponse(discountInfo.Limit)
+		}
+
+		responses[i] = variant
+	}
+	return responses
+}
 
-		variants[i] = variant
+// ToProductDetailResponse converts a domain product with variants to DTO.
+func ToProductDetailResponse(p product.Product, discountedPrice float64, discountPercentage int, variantDiscounts map[string]VariantDiscountInfo) ProductDetailResponse {
+	categoryCode := ""
+	if p.Category != nil {
+		categoryCode = p.Category.Code
 	}
 
+	variants := ToVariantResponses(p.Variants, variantDiscounts)
+
 	response := ProductDetailResponse{
 		Code:     p.Code,
 		Price:    p.Price.InexactFloat64(),
@@ -3,9 +3,11 @@ package mapper
 import (
 	"testing"
 
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestToProductResponse(t *testing.T) {
@@ -188,3 +190,23 @@ func TestToProductResponses(t *testing.T) {
 		assert.Equal(t, "accessories", responses[1].Category)
 	})
 }
+
+func TestToVariantResponses(t *testing.T) {
+	t.Run("explains discounts limited by a guardrail", func(t *testing.T) {
+		variants := []product.Variant{
+			{SKU: "SKU004A", Price: decimal.NewFromFloat(15.50)},
+			{SKU: "SKU004B", Price: decimal.NewFromFloat(16.00)},
+		}
+		limit := &discount.Limit{Kind: discount.LimitMargin, Requested: 20, Reason: "discounts keep a 0% margin over cost"}
+
+		responses := ToVariantResponses(variants, map[string]VariantDiscountInfo{
+			"SKU004A": {DiscountedPrice: 13.00, Percentage: 16, Limit: limit},
+			"SKU004B": {DiscountedPrice: 12.80, Percentage: 20},
+		})
+
+		require.NotNil(t, responses[0].DiscountLimit)
+		assert.Equal(t, DiscountLimitResponse{Kind: "margin", Requested: 20, Reason: "discounts keep a 0% margin over cost"}, *responses[0].DiscountLimit)
+		assert.Equal(t, 13.00, *responses[0].FinalPrice)
+		assert.Nil(t, responses[1].DiscountLimit)
+	})
+}
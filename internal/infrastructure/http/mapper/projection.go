@@ -0,0 +1,25 @@
+package mapper
+
+import "encoding/json"
+
+// Project re-encodes a response DTO as a JSON object that only contains the
+// top-level fields for which keep returns true.
+func Project(v any, keep func(field string) bool) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for name := range fields {
+		if !keep(name) {
+			delete(fields, name)
+		}
+	}
+
+	return fields, nil
+}
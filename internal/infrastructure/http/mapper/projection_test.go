@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProject(t *testing.T) {
+	t.Run("keeps only selected fields", func(t *testing.T) {
+		finalPrice := 70.0
+		response := ProductResponse{Code: "PROD001", Price: 100, Category: "boots", FinalPrice: &finalPrice}
+
+		projected, err := Project(response, func(field string) bool {
+			return field == "code" || field == "final_price"
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, projected, 2)
+		assert.Equal(t, `"PROD001"`, string(projected["code"]))
+		assert.Equal(t, `70`, string(projected["final_price"]))
+	})
+
+	t.Run("does not add fields omitted by the DTO", func(t *testing.T) {
+		response := ProductResponse{Code: "PROD001", Price: 100}
+
+		projected, err := Project(response, func(string) bool { return true })
+
+		require.NoError(t, err)
+		assert.NotContains(t, projected, "final_price")
+		assert.NotContains(t, projected, "variants")
+	})
+}
@@ -0,0 +1,98 @@
+package mapper
+
+import (
+	"github.com/mytheresa/go-hiring-challenge/internal/application/quote"
+	"github.com/shopspring/decimal"
+)
+
+// QuoteItem is a quantity of one variant to price.
+type QuoteItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// QuoteRequest lists the items of a cart, with the coupons supplied for it.
+type QuoteRequest struct {
+	Items      []QuoteItem `json:"items"`
+	Coupons    []string    `json:"coupons"`
+	CustomerID string      `json:"customerId"`
+}
+
+// ToCart converts the request to a quote cart.
+func (r QuoteRequest) ToCart() quote.Cart {
+	items := make([]quote.Item, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = quote.Item{SKU: item.SKU, Quantity: item.Quantity}
+	}
+	return quote.Cart{Items: items, Coupons: r.Coupons, CustomerID: r.CustomerID}
+}
+
+// QuoteLineResponse is the price of one cart item. Amounts are decimal strings
+// with two decimals, so that they add up exactly.
+type QuoteLineResponse struct {
+	SKU                string                 `json:"sku"`
+	ProductCode        string                 `json:"productCode"`
+	Name               string                 `json:"name"`
+	Quantity           int                    `json:"quantity"`
+	UnitPrice          string                 `json:"unitPrice"`
+	DiscountPercentage int                    `json:"discountPercentage"`
+	Subtotal           string                 `json:"subtotal"`
+	Discount           string                 `json:"discount"`
+	PromotionDiscount  string                 `json:"promotionDiscount"`
+	Total              string                 `json:"total"`
+	DiscountLimit      *DiscountLimitResponse `json:"discountLimit,omitempty"`
+}
+
+// PromotionResponse is a basket promotion applied to a quote, with the coupon
+// that unlocked it.
+type PromotionResponse struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Coupon   string `json:"coupon,omitempty"`
+	Discount string `json:"discount"`
+}
+
+// QuoteResponse is the price of a cart.
+type QuoteResponse struct {
+	Lines             []QuoteLineResponse `json:"lines"`
+	Promotions        []PromotionResponse `json:"promotions"`
+	Subtotal          string              `json:"subtotal"`
+	Discount          string              `json:"discount"`
+	PromotionDiscount string              `json:"promotionDiscount"`
+	Total             string              `json:"total"`
+}
+
+// ToQuoteResponse converts a quote to a DTO.
+func ToQuoteResponse(q quote.Quote) QuoteResponse {
+	response := QuoteResponse{
+		Lines:             make([]QuoteLineResponse, len(q.Lines)),
+		Promotions:        make([]PromotionResponse, len(q.Promotions)),
+		Subtotal:          amount(q.Subtotal),
+		Discount:          amount(q.Discount),
+		PromotionDiscount: amount(q.PromotionDiscount),
+		Total:             amount(q.Total),
+	}
+	for i, l := range q.Lines {
+		response.Lines[i] = QuoteLineResponse{
+			SKU:                l.SKU,
+			ProductCode:        l.ProductCode,
+			Name:               l.Name,
+			Quantity:           l.Quantity,
+			UnitPrice:          amount(l.UnitPrice),
+			DiscountPercentage: l.Percentage,
+			Subtotal:           amount(l.Subtotal),
+			Discount:           amount(l.Discount),
+			PromotionDiscount:  amount(l.PromotionDiscount),
+			Total:              amount(l.Total),
+			DiscountLimit:      ToDiscountLimitResponse(l.Limit),
+		}
+	}
+	for i, p := range q.Promotions {
+		response.Promotions[i] = PromotionResponse{Name: p.Name, Kind: string(p.Kind), Coupon: p.Coupon, Discount: amount(p.Discount)}
+	}
+	return response
+}
+
+func amount(d decimal.Decimal) string {
+	return d.StringFixed(2)
+}
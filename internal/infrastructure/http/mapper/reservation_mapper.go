@@ -0,0 +1,60 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+)
+
+// ReservationItem is a quantity of one variant to reserve.
+type ReservationItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// CreateReservationRequest lists the variants to reserve together.
+type CreateReservationRequest struct {
+	Items []ReservationItem `json:"items"`
+}
+
+// ToLines converts the request to domain reservation lines.
+func (r CreateReservationRequest) ToLines() []inventory.Line {
+	lines := make([]inventory.Line, len(r.Items))
+	for i, item := range r.Items {
+		lines[i] = inventory.Line{SKU: item.SKU, Quantity: item.Quantity}
+	}
+	return lines
+}
+
+// ReservationItemResponse is a reserved quantity with the warehouses it was taken from.
+type ReservationItemResponse struct {
+	SKU        string           `json:"sku"`
+	Quantity   int              `json:"quantity"`
+	Warehouses []WarehouseStock `json:"warehouses"`
+}
+
+// ReservationResponse represents a reservation in API responses.
+type ReservationResponse struct {
+	ID        uint                      `json:"id"`
+	Status    string                    `json:"status"`
+	ExpiresAt time.Time                 `json:"expiresAt"`
+	Items     []ReservationItemResponse `json:"items"`
+}
+
+// ToReservationResponse converts a domain reservation to a DTO.
+func ToReservationResponse(r inventory.Reservation) ReservationResponse {
+	response := ReservationResponse{
+		ID:        r.ID,
+		Status:    string(r.Status),
+		ExpiresAt: r.ExpiresAt.UTC(),
+		Items:     make([]ReservationItemResponse, len(r.Lines)),
+	}
+	for i, l := range r.Lines {
+		item := ReservationItemResponse{SKU: l.SKU, Quantity: l.Quantity, Warehouses: make([]WarehouseStock, len(l.Levels))}
+		for j, level := range l.Levels {
+			item.Warehouses[j] = WarehouseStock{Warehouse: level.Warehouse, Quantity: level.Quantity}
+		}
+		response.Items[i] = item
+	}
+	return response
+}
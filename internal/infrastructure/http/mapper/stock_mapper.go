@@ -0,0 +1,51 @@
+package mapper
+
+import "github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+
+// WarehouseStock is the quantity of a variant in one warehouse.
+type WarehouseStock struct {
+	Warehouse string `json:"warehouse"`
+	Quantity  int    `json:"quantity"`
+}
+
+// StockResponse is the stock of a variant across warehouses.
+type StockResponse struct {
+	SKU        string           `json:"sku"`
+	Quantity   int              `json:"quantity"`
+	Available  bool             `json:"available"`
+	Warehouses []WarehouseStock `json:"warehouses"`
+}
+
+// SetStockRequest replaces the stock of a variant. Quantity sets the default
+// warehouse; Warehouses lists every warehouse instead. Exactly one is required.
+type SetStockRequest struct {
+	Quantity   *int             `json:"quantity"`
+	Warehouses []WarehouseStock `json:"warehouses"`
+}
+
+// ToStock converts the request to domain stock for the variant.
+func (r SetStockRequest) ToStock(sku string) inventory.Stock {
+	if r.Quantity != nil {
+		return inventory.Stock{SKU: sku, Levels: []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: *r.Quantity}}}
+	}
+
+	stock := inventory.Stock{SKU: sku, Levels: make([]inventory.Level, len(r.Warehouses))}
+	for i, w := range r.Warehouses {
+		stock.Levels[i] = inventory.Level{Warehouse: w.Warehouse, Quantity: w.Quantity}
+	}
+	return stock
+}
+
+// ToStockResponse converts domain stock to a DTO.
+func ToStockResponse(stock inventory.Stock) StockResponse {
+	response := StockResponse{
+		SKU:        stock.SKU,
+		Quantity:   stock.Quantity(),
+		Available:  stock.Available(),
+		Warehouses: make([]WarehouseStock, len(stock.Levels)),
+	}
+	for i, l := range stock.Levels {
+		response.Warehouses[i] = WarehouseStock{Warehouse: l.Warehouse, Quantity: l.Quantity}
+	}
+	return response
+}
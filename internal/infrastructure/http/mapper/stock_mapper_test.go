@@ -0,0 +1,49 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetStockRequest_ToStock(t *testing.T) {
+	t.Run("puts a plain quantity in the default warehouse", func(t *testing.T) {
+		quantity := 4
+
+		stock := SetStockRequest{Quantity: &quantity}.ToStock("000003")
+
+		assert.Equal(t, inventory.Stock{SKU: "000003", Levels: []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: 4}}}, stock)
+	})
+
+	t.Run("keeps warehouse levels", func(t *testing.T) {
+		stock := SetStockRequest{Warehouses: []WarehouseStock{{Warehouse: "berlin", Quantity: 2}}}.ToStock("000003")
+
+		assert.Equal(t, inventory.Stock{SKU: "000003", Levels: []inventory.Level{{Warehouse: "berlin", Quantity: 2}}}, stock)
+	})
+}
+
+func TestToStockResponse(t *testing.T) {
+	t.Run("sums warehouses", func(t *testing.T) {
+		response := ToStockResponse(inventory.Stock{SKU: "000003", Levels: []inventory.Level{
+			{Warehouse: "berlin", Quantity: 2}, {Warehouse: "madrid", Quantity: 0},
+		}})
+
+		assert.Equal(t, StockResponse{
+			SKU:       "000003",
+			Quantity:  2,
+			Available: true,
+			Warehouses: []WarehouseStock{
+				{Warehouse: "berlin", Quantity: 2}, {Warehouse: "madrid", Quantity: 0},
+			},
+		}, response)
+	})
+
+	t.Run("reports variants without levels as unavailable", func(t *testing.T) {
+		response := ToStockResponse(inventory.Stock{SKU: "000003"})
+
+		assert.False(t, response.Available)
+		assert.Empty(t, response.Warehouses)
+		assert.NotNil(t, response.Warehouses)
+	})
+}
@@ -0,0 +1,43 @@
+package mapper
+
+import (
+	"fmt"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+)
+
+// VariantDetailResponse represents a variant looked up by SKU, with its parent product.
+type VariantDetailResponse struct {
+	SKU           string                 `json:"sku"`
+	Name          string                 `json:"name"`
+	Price         float64                `json:"price"`
+	Discount      *string                `json:"discount,omitempty"`
+	FinalPrice    *float64               `json:"final_price,omitempty"`
+	DiscountLimit *DiscountLimitResponse `json:"discount_limit,omitempty"`
+	Available     bool                   `json:"available"`
+	Quantity      int                    `json:"quantity"`
+	Product       ProductResponse        `json:"product"`
+	Category      *CategoryResponse      `json:"category"`
+}
+
+// ToVariantDetailResponse converts a domain variant and its parent product to a DTO.
+// The variant price is already inherited from the product when the variant has none.
+func ToVariantDetailResponse(v product.Variant, discountedPrice float64, discountPercentage int, parent product.Product, parentDiscountedPrice float64, parentDiscountPercentage int) VariantDetailResponse {
+	response := VariantDetailResponse{
+		SKU:       v.SKU,
+		Name:      v.Name,
+		Price:     v.Price.InexactFloat64(),
+		Available: v.Available(),
+		Quantity:  v.Quantity,
+		Product:   ToProductResponse(parent, parentDiscountedPrice, parentDiscountPercentage),
+		Category:  ToCategoryRef(parent.Category),
+	}
+
+	if discountPercentage > 0 {
+		discountStr := fmt.Sprintf("%d%%", discountPercentage)
+		response.Discount = &discountStr
+		response.FinalPrice = &discountedPrice
+	}
+
+	return response
+}
@@ -0,0 +1,44 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToVariantDetailResponse(t *testing.T) {
+	t.Run("maps discounted variant with category", func(t *testing.T) {
+		parent := product.Product{
+			Code:     "PROD009",
+			Price:    decimal.NewFromFloat(100.0),
+			Category: &product.Category{Code: "boots", Name: "Boots"},
+		}
+		variant := product.Variant{SKU: "000003", Name: "Standard", Price: decimal.NewFromFloat(100.0)}
+
+		response := ToVariantDetailResponse(variant, 85.0, 15, parent, 70.0, 30)
+
+		assert.Equal(t, "000003", response.SKU)
+		require.NotNil(t, response.Discount)
+		assert.Equal(t, "15%", *response.Discount)
+		require.NotNil(t, response.FinalPrice)
+		assert.Equal(t, 85.0, *response.FinalPrice)
+		assert.Equal(t, "30%", *response.Product.Discount)
+		require.NotNil(t, response.Category)
+		assert.Equal(t, "boots", response.Category.Code)
+	})
+
+	t.Run("maps variant without discount or category", func(t *testing.T) {
+		parent := product.Product{Code: "PROD001", Price: decimal.NewFromFloat(10.0)}
+		variant := product.Variant{SKU: "SKU001B", Name: "Variant B", Price: decimal.NewFromFloat(10.0)}
+
+		response := ToVariantDetailResponse(variant, 10.0, 0, parent, 10.0, 0)
+
+		assert.Nil(t, response.Discount)
+		assert.Nil(t, response.FinalPrice)
+		assert.Nil(t, response.Category)
+		assert.Equal(t, "", response.Product.Category)
+	})
+}
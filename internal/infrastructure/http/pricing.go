@@ -0,0 +1,33 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+)
+
+// Headers carrying the pricing context. They are meant to be set by the
+// gateway that authenticates customers, which must drop any sent by clients.
+const (
+	SegmentHeader = "X-Customer-Segment"
+	MarketHeader  = "X-Market"
+	ChannelHeader = "X-Channel"
+)
+
+// WithPricingContext puts the pricing context read from the request headers
+// on the request context, so that services price for the customer asking.
+// Responses vary by those headers, so shared caches keep one copy per context.
+func WithPricingContext(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pc := discount.NewPricingContext(
+			r.Header.Get(SegmentHeader),
+			r.Header.Get(MarketHeader),
+			r.Header.Get(ChannelHeader),
+		)
+		for _, header := range []string{SegmentHeader, MarketHeader, ChannelHeader} {
+			w.Header().Add("Vary", header)
+		}
+
+		next(w, r.WithContext(discount.WithPricingContext(r.Context(), pc)))
+	}
+}
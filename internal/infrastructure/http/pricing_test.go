@@ -0,0 +1,40 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPricingContext(t *testing.T) {
+	t.Run("reads the pricing context from the headers", func(t *testing.T) {
+		var pc discount.PricingContext
+		handler := WithPricingContext(func(w http.ResponseWriter, r *http.Request) {
+			pc = discount.PricingContextFrom(r.Context())
+		})
+		req := httptest.NewRequest("GET", "/catalog", nil)
+		req.Header.Set(SegmentHeader, "VIP")
+		req.Header.Set(MarketHeader, "de")
+		req.Header.Set(ChannelHeader, "app")
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		assert.Equal(t, discount.PricingContext{Segment: "vip", Market: "de", Channel: "app"}, pc)
+		assert.Equal(t, []string{SegmentHeader, MarketHeader, ChannelHeader}, w.Header().Values("Vary"))
+	})
+
+	t.Run("prices anonymously without headers", func(t *testing.T) {
+		var pc discount.PricingContext
+		handler := WithPricingContext(func(w http.ResponseWriter, r *http.Request) {
+			pc = discount.PricingContextFrom(r.Context())
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/catalog", nil))
+
+		assert.Equal(t, discount.PricingContext{}, pc)
+	})
+}
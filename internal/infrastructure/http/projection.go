@@ -0,0 +1,148 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
+)
+
+const (
+	fieldCode       = "code"
+	fieldPrice      = "price"
+	fieldCategory   = "category"
+	fieldDiscount   = "discount"
+	fieldFinalPrice = "final_price"
+	fieldLimit      = "discount_limit"
+	fieldVariants   = "variants"
+)
+
+// productFields lists the fields accepted by the fields parameter.
+var productFields = []string{fieldCode, fieldPrice, fieldCategory, fieldDiscount, fieldFinalPrice, fieldLimit, fieldVariants}
+
+// responseShape holds the sparse fieldset, relations and expansions requested
+// by a client through the fields, include and expand query parameters.
+type responseShape struct {
+	// fields is nil when every field was requested.
+	fields    map[string]bool
+	relations product.Relations
+	// expandCategory replaces the category code with the full category object.
+	expandCategory bool
+	// custom reports whether the client asked for anything but the default shape.
+	custom bool
+}
+
+// parseShapeParams parses the fields, include and expand parameters. Without include,
+// the endpoint's default relations are used. Requesting a relation as a field
+// or expanding it implies including it.
+func parseShapeParams(r *http.Request, defaults product.Relations) (responseShape, error) {
+	shape := responseShape{relations: defaults}
+
+	if includeStr, ok := r.URL.Query()["include"]; ok {
+		shape.custom = true
+		shape.relations = product.Relations{}
+		for _, name := range splitList(strings.Join(includeStr, ",")) {
+			switch name {
+			case fieldCategory:
+				shape.relations.Category = true
+			case fieldVariants:
+				shape.relations.Variants = true
+			default:
+				return shape, fmt.Errorf("invalid include %q, supported relations: %s, %s", name, fieldCategory, fieldVariants)
+			}
+		}
+	}
+
+	if fieldsStr := r.URL.Query().Get("fields"); fieldsStr != "" {
+		shape.custom = true
+		shape.fields = make(map[string]bool)
+		for _, name := range splitList(fieldsStr) {
+			if !isProductField(name) {
+				return shape, fmt.Errorf("invalid field %q, supported fields: %s", name, strings.Join(productFields, ", "))
+			}
+			shape.fields[name] = true
+		}
+		if shape.fields[fieldCategory] {
+			shape.relations.Category = true
+		}
+		if shape.fields[fieldVariants] {
+			shape.relations.Variants = true
+		}
+	}
+
+	if expandStr := r.URL.Query().Get("expand"); expandStr != "" {
+		shape.custom = true
+		for _, name := range splitList(expandStr) {
+			if name != fieldCategory {
+				return shape, fmt.Errorf("invalid expand %q, supported expansions: %s", name, fieldCategory)
+			}
+			shape.expandCategory = true
+			shape.relations.Category = true
+		}
+	}
+
+	return shape, nil
+}
+
+// project applies the shape to a product response DTO, expanding the
+// category of the product when requested.
+func (s responseShape) project(response any, category *product.Category) (map[string]json.RawMessage, error) {
+	projected, err := mapper.Project(response, s.keep)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.expandCategory && s.keep(fieldCategory) {
+		expanded, err := json.Marshal(mapper.ToCategoryRef(category))
+		if err != nil {
+			return nil, err
+		}
+		projected[fieldCategory] = expanded
+	}
+
+	return projected, nil
+}
+
+// projection returns what the catalog service has to load for this shape.
+// Discounts are only calculated when a field depending on them is returned.
+func (s responseShape) projection() catalog.Projection {
+	pricing := s.fields == nil ||
+		s.fields[fieldDiscount] || s.fields[fieldFinalPrice] || s.fields[fieldLimit] ||
+		(s.fields[fieldVariants] && s.relations.Variants)
+
+	return catalog.Projection{Relations: s.relations, Pricing: pricing}
+}
+
+// keep reports whether a response field is part of the shape.
+func (s responseShape) keep(field string) bool {
+	if field == fieldCategory && !s.relations.Category {
+		return false
+	}
+	if field == fieldVariants && !s.relations.Variants {
+		return false
+	}
+	return s.fields == nil || s.fields[field]
+}
+
+func isProductField(name string) bool {
+	for _, f := range productFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
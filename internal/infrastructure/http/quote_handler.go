@@ -0,0 +1,65 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/quote"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
+)
+
+// maxQuoteItems bounds the number of items priced in one quote.
+const maxQuoteItems = 100
+
+// QuoteHandler handles HTTP requests for cart quotes.
+type QuoteHandler struct {
+	service quote.Service
+}
+
+// NewQuoteHandler creates a new quote HTTP handler.
+func NewQuoteHandler(service quote.Service) *QuoteHandler {
+	return &QuoteHandler{service: service}
+}
+
+type unknownSKUsResponse struct {
+	Error       string   `json:"error"`
+	UnknownSKUs []string `json:"unknownSkus"`
+}
+
+// HandlePost handles POST /quote requests.
+// Prices every item with its discount; unknown SKUs are all listed in a 422,
+// and so are coupons that cannot be redeemed.
+func (h *QuoteHandler) HandlePost(w http.ResponseWriter, r *http.Request) {
+	var req mapper.QuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Items) > maxQuoteItems {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("at most %d items can be quoted at once", maxQuoteItems))
+		return
+	}
+
+	q, err := h.service.Quote(r.Context(), req.ToCart())
+	var unknown *quote.UnknownSKUsError
+	switch {
+	case errors.Is(err, quote.ErrInvalidQuote):
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, discount.ErrCouponNotFound), errors.Is(err, discount.ErrCouponInactive),
+		errors.Is(err, discount.ErrCouponExhausted):
+		errorResponse(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	case errors.As(err, &unknown):
+		jsonResponse(w, http.StatusUnprocessableEntity, unknownSKUsResponse{Error: err.Error(), UnknownSKUs: unknown.SKUs})
+		return
+	case err != nil:
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	okResponse(w, mapper.ToQuoteResponse(q))
+}
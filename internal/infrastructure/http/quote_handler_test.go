@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/quote"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQuoteService struct {
+	quote quote.Quote
+	err   error
+	cart  quote.Cart
+}
+
+func (m *mockQuoteService) Quote(ctx context.Context, cart quote.Cart) (quote.Quote, error) {
+	m.cart = cart
+	return m.quote, m.err
+}
+
+func newQuoteRequest(body string) *http.Request {
+	return httptest.NewRequest("POST", "/quote", strings.NewReader(body))
+}
+
+func TestQuoteHandler_HandlePost(t *testing.T) {
+	t.Run("returns the quote with decimal amounts", func(t *testing.T) {
+		service := &mockQuoteService{quote: quote.Quote{
+			Lines: []quote.Line{{
+				SKU:               "000003",
+				ProductCode:       "PROD009",
+				Name:              "Size 40",
+				Quantity:          2,
+				UnitPrice:         decimal.RequireFromString("99.99"),
+				Percentage:        15,
+				Subtotal:          decimal.RequireFromString("199.98"),
+				Discount:          decimal.RequireFromString("30"),
+				PromotionDiscount: decimal.RequireFromString("17"),
+				Total:             decimal.RequireFromString("152.98"),
+			}},
+			Promotions:        []quote.Promotion{{Name: "spend100", Kind: discount.BasketThreshold, Coupon: "SPEND100", Discount: decimal.RequireFromString("17")}},
+			Subtotal:          decimal.RequireFromString("199.98"),
+			Discount:          decimal.RequireFromString("30"),
+			PromotionDiscount: decimal.RequireFromString("17"),
+			Total:             decimal.RequireFromString("152.98"),
+		}}
+		w := httptest.NewRecorder()
+
+		NewQuoteHandler(service).HandlePost(w, newQuoteRequest(`{"items": [{"sku": "000003", "quantity": 2}], "coupons": ["SPEND100"], "customerId": "c-1"}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, quote.Cart{
+			Items:      []quote.Item{{SKU: "000003", Quantity: 2}},
+			Coupons:    []string{"SPEND100"},
+			CustomerID: "c-1",
+		}, service.cart)
+		assert.JSONEq(t, `{
+			"lines": [{
+				"sku": "000003", "productCode": "PROD009", "name": "Size 40", "quantity": 2,
+				"unitPrice": "99.99", "discountPercentage": 15,
+				"subtotal": "199.98", "discount": "30.00", "promotionDiscount": "17.00", "total": "152.98"
+			}],
+			"promotions": [{"name": "spend100", "kind": "threshold", "coupon": "SPEND100", "discount": "17.00"}],
+			"subtotal": "199.98", "discount": "30.00", "promotionDiscount": "17.00", "total": "152.98"
+		}`, w.Body.String())
+	})
+
+	t.Run("lists unknown skus", func(t *testing.T) {
+		service := &mockQuoteService{err: &quote.UnknownSKUsError{SKUs: []string{"NOPE", "GONE"}}}
+		w := httptest.NewRecorder()
+
+		NewQuoteHandler(service).HandlePost(w, newQuoteRequest(`{"items": [{"sku": "NOPE", "quantity": 1}, {"sku": "GONE", "quantity": 1}]}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"error": "unknown skus: NOPE, GONE", "unknownSkus": ["NOPE", "GONE"]}`, w.Body.String())
+	})
+
+	t.Run("returns 422 for coupons that cannot be redeemed", func(t *testing.T) {
+		for _, err := range []error{discount.ErrCouponNotFound, discount.ErrCouponInactive, discount.ErrCouponExhausted} {
+			service := &mockQuoteService{err: fmt.Errorf("%w: SPRING", err)}
+			w := httptest.NewRecorder()
+
+			NewQuoteHandler(service).HandlePost(w, newQuoteRequest(`{"items": [{"sku": "000003", "quantity": 1}], "coupons": ["SPRING"]}`))
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code, err.Error())
+		}
+	})
+
+	t.Run("returns 400 for invalid requests", func(t *testing.T) {
+		tooMany := `{"items": [` + strings.Repeat(`{"sku": "000003", "quantity": 1},`, maxQuoteItems) + `{"sku": "X", "quantity": 1}]}`
+		tests := map[string]struct {
+			body string
+			err  error
+		}{
+			"invalid json":  {`not json`, nil},
+			"too many":      {tooMany, nil},
+			"invalid items": {`{"items": []}`, fmt.Errorf("%w: at least one item is required", quote.ErrInvalidQuote)},
+		}
+		for name, tt := range tests {
+			w := httptest.NewRecorder()
+
+			NewQuoteHandler(&mockQuoteService{err: tt.err}).HandlePost(w, newQuoteRequest(tt.body))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		}
+	})
+
+	t.Run("returns 500 when pricing fails", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		NewQuoteHandler(&mockQuoteService{err: errors.New("db down")}).HandlePost(w, newQuoteRequest(`{"items": [{"sku": "000003", "quantity": 1}]}`))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
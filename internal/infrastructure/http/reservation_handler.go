@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/reservation"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
+)
+
+// ReservationHandler handles HTTP requests for stock reservations.
+type ReservationHandler struct {
+	service reservation.Service
+}
+
+// NewReservationHandler creates a new reservation HTTP handler.
+func NewReservationHandler(service reservation.Service) *ReservationHandler {
+	return &ReservationHandler{service: service}
+}
+
+// HandlePost handles POST /reservations requests.
+// Either every item is reserved or none is; 409 is returned when stock runs short.
+func (h *ReservationHandler) HandlePost(w http.ResponseWriter, r *http.Request) {
+	var req mapper.CreateReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	res, err := h.service.Reserve(r.Context(), req.ToLines())
+	if err != nil {
+		reservationErrorResponse(w, r, err)
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, mapper.ToReservationResponse(res))
+}
+
+// HandleGet handles GET /reservations/:id requests.
+func (h *ReservationHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.Get)
+}
+
+// HandleConfirm handles POST /reservations/:id/confirm requests.
+func (h *ReservationHandler) HandleConfirm(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.Confirm)
+}
+
+// HandleCancel handles POST /reservations/:id/cancel requests.
+func (h *ReservationHandler) HandleCancel(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.Cancel)
+}
+
+// handle runs op on the reservation named by the id path parameter.
+func (h *ReservationHandler) handle(w http.ResponseWriter, r *http.Request, op func(ctx context.Context, id uint) (inventory.Reservation, error)) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 0)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "invalid reservation id")
+		return
+	}
+
+	res, err := op(r.Context(), uint(id))
+	if err != nil {
+		reservationErrorResponse(w, r, err)
+		return
+	}
+
+	okResponse(w, mapper.ToReservationResponse(res))
+}
+
+// reservationErrorResponse maps reservation errors to their status codes.
+func reservationErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, inventory.ErrInvalidReservation):
+		errorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, inventory.ErrReservationNotFound), errors.Is(err, product.ErrNotFound):
+		errorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, inventory.ErrInsufficientStock), errors.Is(err, inventory.ErrReservationClosed):
+		errorResponse(w, http.StatusConflict, err.Error())
+	default:
+		serviceErrorResponse(w, r, err)
+	}
+}
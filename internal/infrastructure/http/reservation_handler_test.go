@@ -0,0 +1,181 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+)
+
+var reservationExpiry = time.Date(2024, 1, 1, 12, 15, 0, 0, time.UTC)
+
+type mockReservationService struct {
+	stock        map[string]int
+	reservations map[uint]inventory.Reservation
+}
+
+func newMockReservationService() *mockReservationService {
+	return &mockReservationService{
+		stock:        map[string]int{"000003": 2, "SKU009B": 5},
+		reservations: make(map[uint]inventory.Reservation),
+	}
+}
+
+func (m *mockReservationService) Reserve(ctx context.Context, lines []inventory.Line) (inventory.Reservation, error) {
+	if err := inventory.ValidateLines(lines); err != nil {
+		return inventory.Reservation{}, err
+	}
+	for i, l := range lines {
+		available, ok := m.stock[l.SKU]
+		if !ok {
+			return inventory.Reservation{}, fmt.Errorf("variant %s: %w", l.SKU, product.ErrNotFound)
+		}
+		levels, err := inventory.Allocate(l.SKU, []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: available}}, l.Quantity)
+		if err != nil {
+			return inventory.Reservation{}, err
+		}
+		lines[i].Levels = levels
+	}
+	r := inventory.Reservation{ID: uint(len(m.reservations) + 1), Status: inventory.StatusPending, Lines: lines, ExpiresAt: reservationExpiry}
+	m.reservations[r.ID] = r
+	return r, nil
+}
+
+func (m *mockReservationService) Get(ctx context.Context, id uint) (inventory.Reservation, error) {
+	r, ok := m.reservations[id]
+	if !ok {
+		return inventory.Reservation{}, fmt.Errorf("reservation %d: %w", id, inventory.ErrReservationNotFound)
+	}
+	return r, nil
+}
+
+func (m *mockReservationService) Confirm(ctx context.Context, id uint) (inventory.Reservation, error) {
+	return m.close(id, inventory.StatusConfirmed)
+}
+
+func (m *mockReservationService) Cancel(ctx context.Context, id uint) (inventory.Reservation, error) {
+	return m.close(id, inventory.StatusCanceled)
+}
+
+func (m *mockReservationService) close(id uint, status inventory.Status) (inventory.Reservation, error) {
+	r, err := m.Get(context.Background(), id)
+	if err != nil {
+		return r, err
+	}
+	if r.Status != inventory.StatusPending {
+		return inventory.Reservation{}, fmt.Errorf("%w: reservation %d is %s", inventory.ErrReservationClosed, id, r.Status)
+	}
+	r.Status = status
+	m.reservations[id] = r
+	return r, nil
+}
+
+func (m *mockReservationService) ReleaseExpired(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (m *mockReservationService) Run(ctx context.Context, interval time.Duration) {}
+
+func newReservationRequest(method, id, action, body string) *http.Request {
+	path := "/reservations"
+	if id != "" {
+		path += "/" + id
+	}
+	if action != "" {
+		path += "/" + action
+	}
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.SetPathValue("id", id)
+	return req
+}
+
+func TestReservationHandler_HandlePost(t *testing.T) {
+	t.Run("reserves every item", func(t *testing.T) {
+		handler := NewReservationHandler(newMockReservationService())
+		w := httptest.NewRecorder()
+
+		handler.HandlePost(w, newReservationRequest("POST", "", "", `{"items": [{"sku": "000003", "quantity": 2}, {"sku": "SKU009B", "quantity": 1}]}`))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{
+			"id": 1, "status": "pending", "expiresAt": "2024-01-01T12:15:00Z",
+			"items": [
+				{"sku": "000003", "quantity": 2, "warehouses": [{"warehouse": "default", "quantity": 2}]},
+				{"sku": "SKU009B", "quantity": 1, "warehouses": [{"warehouse": "default", "quantity": 1}]}
+			]
+		}`, w.Body.String())
+	})
+
+	t.Run("maps errors to status codes", func(t *testing.T) {
+		tests := map[string]struct {
+			body   string
+			status int
+		}{
+			"invalid json":       {`not json`, http.StatusBadRequest},
+			"no items":           {`{"items": []}`, http.StatusBadRequest},
+			"zero quantity":      {`{"items": [{"sku": "000003", "quantity": 0}]}`, http.StatusBadRequest},
+			"unknown sku":        {`{"items": [{"sku": "NOPE", "quantity": 1}]}`, http.StatusNotFound},
+			"insufficient stock": {`{"items": [{"sku": "SKU009B", "quantity": 1}, {"sku": "000003", "quantity": 3}]}`, http.StatusConflict},
+		}
+		for name, tt := range tests {
+			service := newMockReservationService()
+			w := httptest.NewRecorder()
+
+			NewReservationHandler(service).HandlePost(w, newReservationRequest("POST", "", "", tt.body))
+
+			assert.Equal(t, tt.status, w.Code, name)
+			assert.Empty(t, service.reservations, name)
+		}
+	})
+}
+
+func TestReservationHandler_ConfirmAndCancel(t *testing.T) {
+	reserve := func(t *testing.T, service *mockReservationService) {
+		t.Helper()
+		_, err := service.Reserve(context.Background(), []inventory.Line{{SKU: "000003", Quantity: 1}})
+		assert.NoError(t, err)
+	}
+
+	t.Run("confirms a pending reservation", func(t *testing.T) {
+		service := newMockReservationService()
+		reserve(t, service)
+		w := httptest.NewRecorder()
+
+		NewReservationHandler(service).HandleConfirm(w, newReservationRequest("POST", "1", "confirm", ""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"confirmed"`)
+	})
+
+	t.Run("returns 409 for closed reservations", func(t *testing.T) {
+		service := newMockReservationService()
+		reserve(t, service)
+		handler := NewReservationHandler(service)
+		handler.HandleCancel(httptest.NewRecorder(), newReservationRequest("POST", "1", "cancel", ""))
+		w := httptest.NewRecorder()
+
+		handler.HandleConfirm(w, newReservationRequest("POST", "1", "confirm", ""))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "reservation 1 is canceled")
+	})
+
+	t.Run("returns 404 for unknown reservations and 400 for invalid ids", func(t *testing.T) {
+		handler := NewReservationHandler(newMockReservationService())
+
+		w := httptest.NewRecorder()
+		handler.HandleCancel(w, newReservationRequest("POST", "7", "cancel", ""))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		handler.HandleGet(w, newReservationRequest("GET", "abc", "", ""))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
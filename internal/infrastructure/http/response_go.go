@@ -1,13 +1,19 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
 func okResponse(w http.ResponseWriter, data any) {
+	jsonResponse(w, http.StatusOK, data)
+}
+
+func jsonResponse(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(data)
 }
 
@@ -16,3 +22,31 @@ func errorResponse(w http.ResponseWriter, status int, message string) {
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
+
+// serviceErrorResponse reports a failed service call: 504 when the request ran
+// out of time, 503 when it was canceled, for instance on shutdown, and 500 otherwise.
+func serviceErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	if ctxErr := r.Context().Err(); ctxErr != nil {
+		err = ctxErr
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		errorResponse(w, http.StatusGatewayTimeout, "request timed out")
+	case errors.Is(err, context.Canceled):
+		errorResponse(w, http.StatusServiceUnavailable, "request canceled")
+	default:
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// trackingWriter records whether anything was written to the response.
+type trackingWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.started = true
+	return t.ResponseWriter.Write(p)
+}
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -39,3 +42,35 @@ func TestErrorResponse(t *testing.T) {
 		assert.JSONEq(t, expected, recorder.Body.String())
 	})
 }
+
+func TestServiceErrorResponse(t *testing.T) {
+	t.Run("maps service errors to statuses", func(t *testing.T) {
+		tests := map[string]struct {
+			err    error
+			status int
+		}{
+			"timeout":  {fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+			"canceled": {fmt.Errorf("query: %w", context.Canceled), http.StatusServiceUnavailable},
+			"other":    {errors.New("connection refused"), http.StatusInternalServerError},
+		}
+		for name, tt := range tests {
+			recorder := httptest.NewRecorder()
+
+			serviceErrorResponse(recorder, httptest.NewRequest("GET", "/catalog", nil), tt.err)
+
+			assert.Equal(t, tt.status, recorder.Code, name)
+		}
+	})
+
+	t.Run("uses the request context error when the request is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequest("GET", "/catalog", nil).WithContext(ctx)
+		recorder := httptest.NewRecorder()
+
+		serviceErrorResponse(recorder, req, errors.New("driver: bad connection"))
+
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.JSONEq(t, `{"error":"request canceled"}`, recorder.Body.String())
+	})
+}
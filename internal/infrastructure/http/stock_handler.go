@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/stock"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
+)
+
+// StockHandler handles HTTP requests for variant stock.
+type StockHandler struct {
+	service stock.Service
+}
+
+// NewStockHandler creates a new stock HTTP handler.
+func NewStockHandler(service stock.Service) *StockHandler {
+	return &StockHandler{service: service}
+}
+
+// HandleGet handles GET /variants/:sku/stock requests.
+// Returns the quantity of the variant per warehouse and in total.
+func (h *StockHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+	if sku == "" {
+		errorResponse(w, http.StatusBadRequest, "variant sku is required")
+		return
+	}
+
+	s, err := h.service.GetStock(r.Context(), sku)
+	if errors.Is(err, product.ErrNotFound) {
+		errorResponse(w, http.StatusNotFound, fmt.Sprintf("variant with sku %s not found", sku))
+		return
+	}
+	if err != nil {
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	okResponse(w, mapper.ToStockResponse(s))
+}
+
+// HandlePut handles PUT /variants/:sku/stock requests.
+// The body sets either the on-hand quantity of the default warehouse or the
+// on-hand quantity of every warehouse; warehouses left out no longer hold the
+// variant. Quantities below the units held by pending reservations conflict.
+func (h *StockHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+	if sku == "" {
+		errorResponse(w, http.StatusBadRequest, "variant sku is required")
+		return
+	}
+
+	var req mapper.SetStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if (req.Quantity == nil) == (req.Warehouses == nil) {
+		errorResponse(w, http.StatusBadRequest, "either quantity or warehouses is required")
+		return
+	}
+
+	s, err := h.service.SetStock(r.Context(), req.ToStock(sku))
+	switch {
+	case errors.Is(err, inventory.ErrInvalidStock):
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, inventory.ErrReservedStock):
+		errorResponse(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, product.ErrNotFound):
+		errorResponse(w, http.StatusNotFound, fmt.Sprintf("variant with sku %s not found", sku))
+		return
+	case err != nil:
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	okResponse(w, mapper.ToStockResponse(s))
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStockService struct {
+	stock map[string]inventory.Stock
+	set   []inventory.Stock
+	err   error
+}
+
+func (m *mockStockService) GetStock(ctx context.Context, sku string) (inventory.Stock, error) {
+	if m.err != nil {
+		return inventory.Stock{}, m.err
+	}
+	s, ok := m.stock[sku]
+	if !ok {
+		return inventory.Stock{}, fmt.Errorf("variant %s: %w", sku, product.ErrNotFound)
+	}
+	return s, nil
+}
+
+func (m *mockStockService) SetStock(ctx context.Context, s inventory.Stock) (inventory.Stock, error) {
+	if m.err != nil {
+		return inventory.Stock{}, m.err
+	}
+	if _, ok := m.stock[s.SKU]; !ok {
+		return inventory.Stock{}, fmt.Errorf("variant %s: %w", s.SKU, product.ErrNotFound)
+	}
+	if err := s.Validate(); err != nil {
+		return inventory.Stock{}, err
+	}
+	m.set = append(m.set, s)
+	return s.Sorted(), nil
+}
+
+func newStockRequest(method, sku, body string) *http.Request {
+	req := httptest.NewRequest(method, "/variants/"+sku+"/stock", strings.NewReader(body))
+	req.SetPathValue("sku", sku)
+	return req
+}
+
+func TestStockHandler_HandleGet(t *testing.T) {
+	t.Run("returns the stock per warehouse", func(t *testing.T) {
+		handler := NewStockHandler(&mockStockService{stock: map[string]inventory.Stock{
+			"000003": {SKU: "000003", Levels: []inventory.Level{{Warehouse: "berlin", Quantity: 2}, {Warehouse: "madrid", Quantity: 1}}},
+		}})
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, newStockRequest("GET", "000003", ""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"sku": "000003", "quantity": 3, "available": true,
+			"warehouses": [{"warehouse": "berlin", "quantity": 2}, {"warehouse": "madrid", "quantity": 1}]
+		}`, w.Body.String())
+	})
+
+	t.Run("returns 404 for unknown variants", func(t *testing.T) {
+		handler := NewStockHandler(&mockStockService{})
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, newStockRequest("GET", "NOPE", ""))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestStockHandler_HandlePut(t *testing.T) {
+	newService := func() *mockStockService {
+		return &mockStockService{stock: map[string]inventory.Stock{"000003": {SKU: "000003"}}}
+	}
+
+	t.Run("sets the default warehouse quantity", func(t *testing.T) {
+		service := newService()
+		handler := NewStockHandler(service)
+		w := httptest.NewRecorder()
+
+		handler.HandlePut(w, newStockRequest("PUT", "000003", `{"quantity": 7}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, service.set, 1)
+		assert.Equal(t, []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: 7}}, service.set[0].Levels)
+		assert.JSONEq(t, `{
+			"sku": "000003", "quantity": 7, "available": true,
+			"warehouses": [{"warehouse": "default", "quantity": 7}]
+		}`, w.Body.String())
+	})
+
+	t.Run("sets every warehouse", func(t *testing.T) {
+		service := newService()
+		handler := NewStockHandler(service)
+		w := httptest.NewRecorder()
+
+		handler.HandlePut(w, newStockRequest("PUT", "000003", `{"warehouses": [{"warehouse": "madrid", "quantity": 0}, {"warehouse": "berlin", "quantity": 2}]}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"sku": "000003", "quantity": 2, "available": true,
+			"warehouses": [{"warehouse": "berlin", "quantity": 2}, {"warehouse": "madrid", "quantity": 0}]
+		}`, w.Body.String())
+	})
+
+	t.Run("clears the stock with an empty warehouse list", func(t *testing.T) {
+		service := newService()
+		handler := NewStockHandler(service)
+		w := httptest.NewRecorder()
+
+		handler.HandlePut(w, newStockRequest("PUT", "000003", `{"warehouses": []}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"sku": "000003", "quantity": 0, "available": false, "warehouses": []}`, w.Body.String())
+	})
+
+	t.Run("returns 400 for invalid bodies", func(t *testing.T) {
+		bodies := []string{
+			`not json`,
+			`{}`,
+			`{"quantity": 1, "warehouses": []}`,
+			`{"quantity": -1}`,
+			`{"warehouses": [{"warehouse": "berlin", "quantity": 1}, {"warehouse": "berlin", "quantity": 2}]}`,
+		}
+		for _, body := range bodies {
+			service := newService()
+			w := httptest.NewRecorder()
+
+			NewStockHandler(service).HandlePut(w, newStockRequest("PUT", "000003", body))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+			assert.Empty(t, service.set, body)
+		}
+	})
+
+	t.Run("returns 404 for unknown variants", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		NewStockHandler(newService()).HandlePut(w, newStockRequest("PUT", "NOPE", `{"quantity": 1}`))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("returns 409 below the reserved units", func(t *testing.T) {
+		service := newService()
+		service.err = fmt.Errorf("%w: 000003 has 2 units reserved in warehouse default, 1 on hand", inventory.ErrReservedStock)
+		w := httptest.NewRecorder()
+
+		NewStockHandler(service).HandlePut(w, newStockRequest("PUT", "000003", `{"quantity": 1}`))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "2 units reserved")
+	})
+}
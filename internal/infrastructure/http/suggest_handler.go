@@ -0,0 +1,62 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/suggest"
+)
+
+const (
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 20
+)
+
+type suggestResponse struct {
+	Products   []string `json:"products"`
+	Variants   []string `json:"variants"`
+	Categories []string `json:"categories"`
+}
+
+// SuggestHandler handles HTTP requests for typeahead suggestions.
+type SuggestHandler struct {
+	service suggest.Service
+}
+
+// NewSuggestHandler creates a new suggestion HTTP handler.
+func NewSuggestHandler(service suggest.Service) *SuggestHandler {
+	return &SuggestHandler{service: service}
+}
+
+// HandleGet handles GET /catalog/suggest requests.
+// Requires the q query parameter and supports an optional limit per suggestion kind.
+func (h *SuggestHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		errorResponse(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	limit := defaultSuggestLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "invalid limit parameter")
+			return
+		}
+		if limit < minLimit || limit > maxSuggestLimit {
+			errorResponse(w, http.StatusBadRequest, fmt.Sprintf("limit must be between %d and %d", minLimit, maxSuggestLimit))
+			return
+		}
+	}
+
+	suggestions := h.service.Suggest(q, limit)
+
+	okResponse(w, suggestResponse{
+		Products:   suggestions.Products,
+		Variants:   suggestions.Variants,
+		Categories: suggestions.Categories,
+	})
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/suggest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSuggestService struct {
+	suggestions suggest.Suggestions
+	prefix      string
+	limit       int
+}
+
+func (m *mockSuggestService) Suggest(prefix string, limit int) suggest.Suggestions {
+	m.prefix = prefix
+	m.limit = limit
+	return m.suggestions
+}
+
+func (m *mockSuggestService) Refresh(ctx context.Context) error {
+	return nil
+}
+
+func (m *mockSuggestService) Invalidate() {}
+
+func (m *mockSuggestService) Run(ctx context.Context, interval time.Duration) {}
+
+func TestSuggestHandler_HandleGet(t *testing.T) {
+	t.Run("returns suggestions grouped by kind", func(t *testing.T) {
+		service := &mockSuggestService{suggestions: suggest.Suggestions{
+			Products:   []string{"PROD001"},
+			Variants:   []string{"Premium"},
+			Categories: []string{},
+		}}
+		handler := NewSuggestHandler(service)
+
+		req := httptest.NewRequest("GET", "/catalog/suggest?q=pr", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "pr", service.prefix)
+		assert.Equal(t, defaultSuggestLimit, service.limit)
+
+		var response suggestResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []string{"PROD001"}, response.Products)
+		assert.Equal(t, []string{"Premium"}, response.Variants)
+		assert.Empty(t, response.Categories)
+	})
+
+	t.Run("passes custom limit", func(t *testing.T) {
+		service := &mockSuggestService{}
+		handler := NewSuggestHandler(service)
+
+		req := httptest.NewRequest("GET", "/catalog/suggest?q=pr&limit=3", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 3, service.limit)
+	})
+
+	t.Run("returns 400 when q is missing", func(t *testing.T) {
+		handler := NewSuggestHandler(&mockSuggestService{})
+
+		req := httptest.NewRequest("GET", "/catalog/suggest", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "q is required")
+	})
+
+	t.Run("returns 400 when limit is out of range", func(t *testing.T) {
+		handler := NewSuggestHandler(&mockSuggestService{})
+
+		req := httptest.NewRequest("GET", "/catalog/suggest?q=pr&limit=500", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleGet(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
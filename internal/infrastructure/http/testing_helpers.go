@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http/httptest"
@@ -13,14 +14,19 @@ import (
 )
 
 type mockService struct {
-	products []product.Product
-	total    int64
-	err      error
+	products     []product.Product
+	total        int64
+	err          error
+	facets       product.Facets
+	facetsErr    error
+	facetRequest product.FacetRequest
+	projection   catalog.Projection
 }
 
-func (m *mockService) GetProducts(offset, limit int, filters product.Filter) ([]product.Product, []float64, []int, int64, error) {
+func (m *mockService) GetProducts(ctx context.Context, offset, limit int, filters product.Filter, projection catalog.Projection) ([]catalog.ProductDetail, int64, error) {
+	m.projection = projection
 	if m.err != nil {
-		return nil, nil, nil, 0, m.err
+		return nil, 0, m.err
 	}
 
 	filtered := make([]product.Product, 0)
@@ -37,13 +43,17 @@ func (m *mockService) GetProducts(offset, limit int, filters product.Filter) ([]
 			}
 		}
 
+		if filters.InStock && !hasAvailableVariant(p) {
+			continue
+		}
+
 		filtered = append(filtered, p)
 	}
 
 	total := int64(len(filtered))
 
 	if offset >= len(filtered) {
-		return []product.Product{}, []float64{}, []int{}, total, nil
+		return []catalog.ProductDetail{}, total, nil
 	}
 
 	end := offset + limit
@@ -53,20 +63,19 @@ func (m *mockService) GetProducts(offset, limit int, filters product.Filter) ([]
 
 	result := filtered[offset:end]
 
-	discountedPrices := make([]float64, len(result))
-	percentages := make([]int, len(result))
+	details := make([]catalog.ProductDetail, len(result))
 	for i, p := range result {
 		price, _ := p.Price.Float64()
-		discountedPrices[i] = price
-		percentages[i] = 0
+		details[i] = catalog.ProductDetail{Product: p, DiscountedPrice: price}
 	}
 
-	return result, discountedPrices, percentages, total, nil
+	return details, total, nil
 }
 
-func (m *mockService) GetProductByCode(code string) (*product.Product, float64, int, map[string]catalog.VariantDiscount, error) {
+func (m *mockService) GetProductByCode(ctx context.Context, code string, projection catalog.Projection) (*catalog.ProductDetail, error) {
+	m.projection = projection
 	if m.err != nil {
-		return nil, 0, 0, nil, m.err
+		return nil, m.err
 	}
 
 	for _, p := range m.products {
@@ -74,11 +83,78 @@ func (m *mockService) GetProductByCode(code string) (*product.Product, float64,
 			price, _ := p.Price.Float64()
 			// Create empty variant discounts map
 			variantDiscounts := make(map[string]catalog.VariantDiscount)
-			return &p, price, 0, variantDiscounts, nil
+			return &catalog.ProductDetail{Product: p, DiscountedPrice: price, VariantDiscounts: variantDiscounts}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("product not found")
+}
+
+func (m *mockService) GetProductsByCodes(ctx context.Context, codes []string) ([]catalog.ProductDetail, []string, error) {
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	details := make([]catalog.ProductDetail, 0)
+	missing := make([]string, 0)
+	for _, code := range codes {
+		found := false
+		for _, p := range m.products {
+			if p.Code == code {
+				price, _ := p.Price.Float64()
+				details = append(details, catalog.ProductDetail{Product: p, DiscountedPrice: price})
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, code)
+		}
+	}
+	return details, missing, nil
+}
+
+func (m *mockService) GetVariantBySKU(ctx context.Context, sku string) (*catalog.VariantDetail, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, p := range m.products {
+		for _, v := range p.Variants {
+			if v.SKU == sku {
+				price, _ := v.Price.Float64()
+				parentPrice, _ := p.Price.Float64()
+				return &catalog.VariantDetail{
+					Variant:         v,
+					DiscountedPrice: price,
+					Parent:          catalog.ProductDetail{Product: p, DiscountedPrice: parentPrice},
+				}, nil
+			}
 		}
 	}
+	return nil, fmt.Errorf("variant %s: %w", sku, product.ErrNotFound)
+}
+
+func (m *mockService) GetFacets(ctx context.Context, filters product.Filter, req product.FacetRequest) (product.Facets, error) {
+	if m.err != nil {
+		return product.Facets{}, m.err
+	}
+	if m.facetsErr != nil {
+		return product.Facets{}, m.facetsErr
+	}
+	m.facetRequest = req
+	return m.facets, nil
+}
 
-	return nil, 0, 0, nil, fmt.Errorf("product not found")
+func (m *mockService) ExportProducts(ctx context.Context, filters product.Filter, fn func(catalog.ProductDetail) error) error {
+	details, _, err := m.GetProducts(ctx, 0, len(m.products), filters, catalog.FullProjection())
+	if err != nil {
+		return err
+	}
+	for _, d := range details {
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 var (
@@ -108,6 +184,15 @@ func newTestProduct(id uint, code string, price float64, category *product.Categ
 	}
 }
 
+func hasAvailableVariant(p product.Product) bool {
+	for _, v := range p.Variants {
+		if v.Available() {
+			return true
+		}
+	}
+	return false
+}
+
 func newMockService(products []product.Product, err error) *mockService {
 	return &mockService{
 		products: products,
@@ -0,0 +1,18 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WithTimeout bounds the time the route's queries may take. Handlers report
+// queries cut short by the deadline with 504 Gateway Timeout.
+func WithTimeout(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next(w, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingService blocks until the request context is done, like a query cut short by it.
+type blockingService struct {
+	mockService
+}
+
+func (m *blockingService) GetProducts(ctx context.Context, offset, limit int, filters product.Filter, projection catalog.Projection) ([]catalog.ProductDetail, int64, error) {
+	<-ctx.Done()
+	return nil, 0, ctx.Err()
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Run("sets a deadline on the request context", func(t *testing.T) {
+		var deadline time.Time
+		var ok bool
+		handler := WithTimeout(time.Minute, func(w http.ResponseWriter, r *http.Request) {
+			deadline, ok = r.Context().Deadline()
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/catalog", nil))
+
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	})
+
+	t.Run("returns 504 when the query outlives the timeout", func(t *testing.T) {
+		handler := WithTimeout(10*time.Millisecond, NewCatalogHandler(&blockingService{}).HandleGet)
+		w := httptest.NewRecorder()
+
+		handler(w, httptest.NewRequest("GET", "/catalog", nil))
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Contains(t, w.Body.String(), "request timed out")
+	})
+
+	t.Run("returns 503 when the request is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		handler := WithTimeout(time.Minute, NewCatalogHandler(&blockingService{}).HandleGet)
+		w := httptest.NewRecorder()
+
+		handler(w, httptest.NewRequest("GET", "/catalog", nil).WithContext(ctx))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
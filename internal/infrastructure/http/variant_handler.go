@@ -0,0 +1,50 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/catalog"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
+)
+
+// VariantHandler handles HTTP requests for product variants.
+type VariantHandler struct {
+	service catalog.Service
+}
+
+// NewVariantHandler creates a new variant HTTP handler.
+func NewVariantHandler(service catalog.Service) *VariantHandler {
+	return &VariantHandler{service: service}
+}
+
+// HandleGetBySKU handles GET /variants/:sku requests.
+// Returns the variant with its discount, parent product and category.
+func (h *VariantHandler) HandleGetBySKU(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+	if sku == "" {
+		errorResponse(w, http.StatusBadRequest, "variant sku is required")
+		return
+	}
+
+	detail, err := h.service.GetVariantBySKU(r.Context(), sku)
+	if errors.Is(err, product.ErrNotFound) {
+		errorResponse(w, http.StatusNotFound, fmt.Sprintf("variant with sku %s not found", sku))
+		return
+	}
+	if err != nil {
+		serviceErrorResponse(w, r, err)
+		return
+	}
+
+	response := mapper.ToVariantDetailResponse(
+		detail.Variant, detail.DiscountedPrice, detail.Percentage,
+		detail.Parent.Product, detail.Parent.DiscountedPrice, detail.Parent.Percentage,
+	)
+	response.DiscountLimit = mapper.ToDiscountLimitResponse(detail.Limit)
+	response.Product.DiscountLimit = mapper.ToDiscountLimitResponse(detail.Parent.Limit)
+
+	okResponse(w, response)
+}
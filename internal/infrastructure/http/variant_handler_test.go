@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/http/mapper"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantHandler_HandleGetBySKU(t *testing.T) {
+	boots := &product.Category{ID: 4, Code: "boots", Name: "Boots"}
+	products := []product.Product{
+		{
+			ID:       9,
+			Code:     "PROD009",
+			Price:    decimal.NewFromFloat(89.99),
+			Category: boots,
+			Variants: []product.Variant{
+				{ID: 1, SKU: "000003", Name: "Standard", Price: decimal.NewFromFloat(89.99)},
+			},
+		},
+	}
+
+	t.Run("returns variant with parent product and category", func(t *testing.T) {
+		handler := NewVariantHandler(newMockService(products, nil))
+
+		req := httptest.NewRequest("GET", "/variants/000003", nil)
+		req.SetPathValue("sku", "000003")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetBySKU(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response mapper.VariantDetailResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "000003", response.SKU)
+		assert.Equal(t, "Standard", response.Name)
+		assert.Equal(t, 89.99, response.Price)
+		assert.Equal(t, "PROD009", response.Product.Code)
+		require.NotNil(t, response.Category)
+		assert.Equal(t, "Boots", response.Category.Name)
+	})
+
+	t.Run("returns 400 when sku is missing", func(t *testing.T) {
+		handler := NewVariantHandler(newMockService(products, nil))
+
+		req := httptest.NewRequest("GET", "/variants/", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleGetBySKU(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns 404 when variant not found", func(t *testing.T) {
+		handler := NewVariantHandler(newMockService(products, nil))
+
+		req := httptest.NewRequest("GET", "/variants/NOPE", nil)
+		req.SetPathValue("sku", "NOPE")
+		w := httptest.NewRecorder()
+
+		handler.HandleGetBySKU(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "variant with sku NOPE not found")
+	})
+}
@@ -0,0 +1,64 @@
+package merchant
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/feed"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultCurrency is used when the config does not name one.
+const defaultCurrency = "EUR"
+
+type configFile struct {
+	Title       string            `yaml:"title"`
+	Link        string            `yaml:"link"`
+	Description string            `yaml:"description"`
+	Currency    string            `yaml:"currency"`
+	ProductURL  string            `yaml:"productUrl"`
+	ImageURL    string            `yaml:"imageUrl"`
+	Categories  map[string]string `yaml:"categories"`
+}
+
+// LoadConfig reads and validates the feed config at path.
+func LoadConfig(path string) (feed.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return feed.Config{}, fmt.Errorf("reading feed config: %w", err)
+	}
+
+	config, err := ParseConfig(data)
+	if err != nil {
+		return feed.Config{}, fmt.Errorf("feed config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig decodes and validates a YAML feed config. Unknown fields are rejected.
+func ParseConfig(data []byte) (feed.Config, error) {
+	var f configFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&f); err != nil {
+		return feed.Config{}, err
+	}
+
+	config := feed.Config{
+		Title:       f.Title,
+		Link:        f.Link,
+		Description: f.Description,
+		Currency:    f.Currency,
+		ProductURL:  f.ProductURL,
+		ImageURL:    f.ImageURL,
+		Categories:  f.Categories,
+	}
+	if config.Currency == "" {
+		config.Currency = defaultCurrency
+	}
+	if err := config.Validate(); err != nil {
+		return feed.Config{}, err
+	}
+	return config, nil
+}
@@ -0,0 +1,130 @@
+// Package merchant renders product feeds as Google Merchant RSS 2.0 XML.
+package merchant
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/feed"
+)
+
+// ContentType is the media type of the feed.
+const ContentType = "application/rss+xml; charset=utf-8"
+
+const namespace = "http://base.google.com/ns/1.0"
+
+type itemElement struct {
+	XMLName               xml.Name `xml:"item"`
+	ID                    string   `xml:"g:id"`
+	Title                 string   `xml:"g:title"`
+	Description           string   `xml:"g:description"`
+	Link                  string   `xml:"g:link"`
+	ImageLink             string   `xml:"g:image_link"`
+	Availability          string   `xml:"g:availability"`
+	Price                 string   `xml:"g:price"`
+	SalePrice             string   `xml:"g:sale_price,omitempty"`
+	ItemGroupID           string   `xml:"g:item_group_id"`
+	GoogleProductCategory string   `xml:"g:google_product_category"`
+	ProductType           string   `xml:"g:product_type,omitempty"`
+	Condition             string   `xml:"g:condition"`
+}
+
+// Writer writes a feed channel item by item. Output is buffered until Close.
+type Writer struct {
+	buf     *bufio.Writer
+	enc     *xml.Encoder
+	config  feed.Config
+	started bool
+}
+
+// NewWriter creates a writer for a channel described by config.
+func NewWriter(w io.Writer, config feed.Config) *Writer {
+	buf := bufio.NewWriter(w)
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	return &Writer{buf: buf, enc: enc, config: config}
+}
+
+// Write adds an item to the channel.
+func (w *Writer) Write(item feed.Item) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	element := itemElement{
+		ID:                    item.ID,
+		Title:                 item.Title,
+		Description:           item.Description,
+		Link:                  item.Link,
+		ImageLink:             item.ImageLink,
+		Availability:          item.Availability,
+		Price:                 amount(item.Price.StringFixed(2), item.Currency),
+		ItemGroupID:           item.ItemGroupID,
+		GoogleProductCategory: item.GoogleProductCategory,
+		ProductType:           item.ProductType,
+		Condition:             "new",
+	}
+	if item.SalePrice != nil {
+		element.SalePrice = amount(item.SalePrice.StringFixed(2), item.Currency)
+	}
+	return w.enc.Encode(element)
+}
+
+// Close ends the channel and flushes the output. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	for _, name := range []string{"channel", "rss"} {
+		if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	if err := w.enc.Flush(); err != nil {
+		return err
+	}
+	if _, err := w.buf.WriteString("\n"); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+// start writes the XML declaration, the rss element and the channel description once.
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	if _, err := w.buf.WriteString(xml.Header); err != nil {
+		return err
+	}
+	rss := xml.StartElement{
+		Name: xml.Name{Local: "rss"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "version"}, Value: "2.0"},
+			{Name: xml.Name{Local: "xmlns:g"}, Value: namespace},
+		},
+	}
+	channel := xml.StartElement{Name: xml.Name{Local: "channel"}}
+	for _, token := range []xml.Token{rss, channel} {
+		if err := w.enc.EncodeToken(token); err != nil {
+			return err
+		}
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"title", w.config.Title}, {"link", w.config.Link}, {"description", w.config.Description},
+	} {
+		if err := w.enc.EncodeElement(field.value, xml.StartElement{Name: xml.Name{Local: field.name}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func amount(value, currency string) string {
+	return fmt.Sprintf("%s %s", value, currency)
+}
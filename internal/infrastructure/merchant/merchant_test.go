@@ -0,0 +1,111 @@
+package merchant
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/application/feed"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type parsedFeed struct {
+	Version string `xml:"version,attr"`
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			ID          string `xml:"http://base.google.com/ns/1.0 id"`
+			Title       string `xml:"http://base.google.com/ns/1.0 title"`
+			Price       string `xml:"http://base.google.com/ns/1.0 price"`
+			SalePrice   string `xml:"http://base.google.com/ns/1.0 sale_price"`
+			ItemGroupID string `xml:"http://base.google.com/ns/1.0 item_group_id"`
+			Category    string `xml:"http://base.google.com/ns/1.0 google_product_category"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func TestWriter(t *testing.T) {
+	t.Run("renders a namespaced RSS 2.0 channel", func(t *testing.T) {
+		sale := decimal.RequireFromString("59.5")
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, feed.Config{Title: "Shop & Co", Link: "https://shop.example.com"})
+
+		require.NoError(t, writer.Write(feed.Item{
+			ID:                    "000003",
+			Title:                 "Boots <PROD009>",
+			Price:                 decimal.NewFromInt(100),
+			SalePrice:             &sale,
+			Currency:              "EUR",
+			ItemGroupID:           "PROD009",
+			GoogleProductCategory: "Apparel & Accessories > Shoes",
+		}))
+		require.NoError(t, writer.Write(feed.Item{ID: "000004", Price: decimal.NewFromInt(120), Currency: "EUR"}))
+		require.NoError(t, writer.Close())
+
+		assert.Contains(t, buf.String(), `<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0">`)
+		assert.NotContains(t, buf.String(), "<g:sale_price></g:sale_price>")
+
+		var parsed parsedFeed
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &parsed))
+		assert.Equal(t, "2.0", parsed.Version)
+		assert.Equal(t, "Shop & Co", parsed.Channel.Title)
+		require.Len(t, parsed.Channel.Items, 2)
+		item := parsed.Channel.Items[0]
+		assert.Equal(t, "000003", item.ID)
+		assert.Equal(t, "Boots <PROD009>", item.Title)
+		assert.Equal(t, "100.00 EUR", item.Price)
+		assert.Equal(t, "59.50 EUR", item.SalePrice)
+		assert.Equal(t, "PROD009", item.ItemGroupID)
+		assert.Equal(t, "Apparel & Accessories > Shoes", item.Category)
+		assert.Empty(t, parsed.Channel.Items[1].SalePrice)
+	})
+
+	t.Run("renders an empty channel", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		require.NoError(t, NewWriter(&buf, feed.Config{Title: "Shop"}).Close())
+
+		var parsed parsedFeed
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &parsed))
+		assert.Equal(t, "Shop", parsed.Channel.Title)
+		assert.Empty(t, parsed.Channel.Items)
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Run("reads the config and defaults the currency", func(t *testing.T) {
+		config, err := ParseConfig([]byte(`
+title: Shop
+link: https://shop.example.com
+productUrl: https://shop.example.com/p/{code}
+imageUrl: https://img.example.com/{sku}.jpg
+categories:
+  boots: Apparel & Accessories > Shoes
+`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "EUR", config.Currency)
+		assert.Equal(t, "Apparel & Accessories > Shoes", config.Categories["boots"])
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		_, err := ParseConfig([]byte("title: Shop\nproductURL: https://shop.example.com\n"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects incomplete configs", func(t *testing.T) {
+		_, err := ParseConfig([]byte("title: Shop\n"))
+
+		assert.ErrorIs(t, err, feed.ErrInvalidConfig)
+	})
+
+	t.Run("loads the repository config", func(t *testing.T) {
+		config, err := LoadConfig("../../../config/merchant_feed.yaml")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, config.Categories)
+	})
+}
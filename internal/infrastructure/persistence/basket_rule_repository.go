@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type basketRuleModel struct {
+	ID         uint            `gorm:"primaryKey"`
+	Name       string          `gorm:"not null;size:64;uniqueIndex"`
+	Kind       string          `gorm:"not null;size:16"`
+	Categories string          `gorm:"not null;size:255;default:''"`
+	Quantity   int             `gorm:"not null;default:0"`
+	Free       int             `gorm:"not null;default:0"`
+	MinSpend   decimal.Decimal `gorm:"type:decimal(10,2);not null;default:0"`
+	Percentage int             `gorm:"not null;default:0"`
+	Precedence string          `gorm:"not null;size:32"`
+	Stop       bool            `gorm:"not null;default:false"`
+	CouponOnly bool            `gorm:"not null;default:false"`
+	Position   int             `gorm:"not null;default:0"`
+	UpdatedAt  time.Time
+}
+
+func (basketRuleModel) TableName() string {
+	return "basket_rules"
+}
+
+// BasketRuleRepository stores basket rules using GORM.
+type BasketRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewBasketRuleRepository creates a new GORM basket rule repository.
+func NewBasketRuleRepository(db *gorm.DB) *BasketRuleRepository {
+	return &BasketRuleRepository{db: db}
+}
+
+// GetAll retrieves every basket rule in evaluation order.
+func (r *BasketRuleRepository) GetAll(ctx context.Context) ([]discount.BasketRule, error) {
+	var models []basketRuleModel
+
+	err := conn(ctx, r.db).
+		Order("position, id").
+		Find(&models).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]discount.BasketRule, len(models))
+	for i, m := range models {
+		rules[i] = toDomainBasketRule(m)
+	}
+	return rules, nil
+}
+
+// Upsert creates the rule or updates the settings of the one with the same name.
+func (r *BasketRuleRepository) Upsert(ctx context.Context, rule discount.BasketRule) (product.Change, error) {
+	db := conn(ctx, r.db)
+	values := toBasketRuleModel(rule)
+
+	var model basketRuleModel
+	err := db.Where("name = ?", rule.Name).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := db.Create(&values).Error; err != nil {
+			return product.Unchanged, err
+		}
+		return product.Created, nil
+	}
+	if err != nil {
+		return product.Unchanged, err
+	}
+
+	values.ID = model.ID
+	values.UpdatedAt = model.UpdatedAt
+	if basketRuleEqual(model, values) {
+		return product.Unchanged, nil
+	}
+	if err := db.Save(&values).Error; err != nil {
+		return product.Unchanged, err
+	}
+	return product.Updated, nil
+}
+
+func basketRuleEqual(a, b basketRuleModel) bool {
+	return a.Kind == b.Kind && a.Categories == b.Categories &&
+		a.Quantity == b.Quantity && a.Free == b.Free &&
+		a.MinSpend.Equal(b.MinSpend) && a.Percentage == b.Percentage &&
+		a.Precedence == b.Precedence && a.Stop == b.Stop && a.CouponOnly == b.CouponOnly && a.Position == b.Position
+}
+
+func toBasketRuleModel(rule discount.BasketRule) basketRuleModel {
+	return basketRuleModel{
+		Name:       rule.Name,
+		Kind:       string(rule.Kind),
+		Categories: strings.Join(rule.Categories, ","),
+		Quantity:   rule.Quantity,
+		Free:       rule.Free,
+		MinSpend:   rule.MinSpend,
+		Percentage: rule.Percentage,
+		Precedence: string(rule.Precedence),
+		Stop:       rule.Stop,
+		CouponOnly: rule.CouponOnly,
+		Position:   rule.Position,
+	}
+}
+
+func toDomainBasketRule(m basketRuleModel) discount.BasketRule {
+	return discount.BasketRule{
+		Name:       m.Name,
+		Kind:       discount.BasketRuleKind(m.Kind),
+		Categories: splitList(m.Categories),
+		Quantity:   m.Quantity,
+		Free:       m.Free,
+		MinSpend:   m.MinSpend,
+		Percentage: m.Percentage,
+		Precedence: discount.Precedence(m.Precedence),
+		Stop:       m.Stop,
+		CouponOnly: m.CouponOnly,
+		Position:   m.Position,
+	}
+}
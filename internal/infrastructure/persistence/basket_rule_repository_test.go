@@ -0,0 +1,65 @@
+//go:build integration
+// +build integration
+
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasketRuleRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBasketRuleRepository(db)
+	ctx := context.Background()
+
+	bundle := discount.BasketRule{
+		Name: "boots-b2g1", Kind: discount.BasketBundle, Categories: []string{"boots"},
+		Quantity: 2, Free: 1, MinSpend: decimal.Zero, Precedence: discount.PrecedenceAfterItems, Position: 2,
+	}
+	threshold := discount.BasketRule{
+		Name: "spend-500", Kind: discount.BasketThreshold, MinSpend: decimal.NewFromInt(500),
+		Percentage: 10, Precedence: discount.PrecedenceExcludeDiscounted, Stop: true, Position: 1,
+	}
+
+	t.Run("returns rules by position", func(t *testing.T) {
+		for _, rule := range []discount.BasketRule{bundle, threshold} {
+			change, err := repo.Upsert(ctx, rule)
+			require.NoError(t, err)
+			assert.Equal(t, product.Created, change)
+		}
+
+		rules, err := repo.GetAll(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, "spend-500", rules[0].Name)
+		assert.Nil(t, rules[0].Categories)
+		assert.True(t, rules[0].MinSpend.Equal(decimal.NewFromInt(500)))
+		assert.True(t, rules[0].Stop)
+		assert.Equal(t, "boots-b2g1", rules[1].Name)
+		assert.Equal(t, []string{"boots"}, rules[1].Categories)
+	})
+
+	t.Run("upserts by name", func(t *testing.T) {
+		change, err := repo.Upsert(ctx, bundle)
+		require.NoError(t, err)
+		assert.Equal(t, product.Unchanged, change)
+
+		bundle.Quantity = 3
+		change, err = repo.Upsert(ctx, bundle)
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		rules, err := repo.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, 3, rules[1].Quantity)
+	})
+}
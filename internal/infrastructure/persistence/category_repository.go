@@ -1,10 +1,22 @@
 package persistence
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
 	"gorm.io/gorm"
 )
 
+// categorySortColumns maps category sort keys to the aggregate query columns.
+var categorySortColumns = map[string]string{
+	product.CategorySortName:     "categories.name",
+	product.CategorySortProducts: "product_count",
+	product.CategorySortOnSale:   "on_sale_count",
+}
+
 // CategoryRepository implements category ops using GORM.
 type CategoryRepository struct {
 	db *gorm.DB
@@ -15,42 +27,119 @@ func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
-// GetAll retrieves all categories.
-func (r *CategoryRepository) GetAll() ([]product.Category, error) {
-	var models []categoryModel
+// GetAll retrieves a page of categories with their product and on-sale counts,
+// computed in a single aggregate query. Returns the page and the total number of categories.
+func (r *CategoryRepository) GetAll(ctx context.Context, query product.CategoryQuery) ([]product.CategorySummary, int64, error) {
+	db := conn(ctx, r.db)
 
-	err := r.db.Find(&models).Error
-	if err != nil {
-		return nil, err
+	var total int64
+	if err := db.Model(&categoryModel{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortColumn, ok := categorySortColumns[query.Sort]
+	if !ok {
+		sortColumn = categorySortColumns[product.CategorySortName]
+	}
+	direction := "ASC"
+	if query.Desc {
+		direction = "DESC"
 	}
 
-	categories := make([]product.Category, len(models))
-	for i, m := range models {
-		categories[i] = product.Category{
-			ID:   m.ID,
-			Code: m.Code,
-			Name: m.Name,
+	onSaleCount := "0"
+	var args []any
+	if !query.Sale.IsEmpty() {
+		condition, conditionArgs, err := onSaleClause(query.Sale)
+		if err != nil {
+			return nil, 0, err
 		}
+		onSaleCount = "COUNT(products.id) FILTER (WHERE " + condition + ")"
+		args = conditionArgs
+	}
+
+	var rows []struct {
+		ID           uint
+		Code         string
+		Name         string
+		UpdatedAt    time.Time
+		ProductCount int64
+		OnSaleCount  int64
+	}
+
+	page := db.Model(&categoryModel{}).
+		Select("categories.id, categories.code, categories.name, categories.updated_at, COUNT(products.id) AS product_count, "+onSaleCount+" AS on_sale_count", args...).
+		Joins("LEFT JOIN products ON products.category_id = categories.id").
+		Group("categories.id").
+		Order(fmt.Sprintf("%s %s, categories.code", sortColumn, direction)).
+		Offset(query.Offset)
+	if query.Limit > 0 {
+		page = page.Limit(query.Limit)
+	}
+
+	if err := page.Scan(&rows).Error; err != nil {
+		return nil, 0, err
 	}
 
-	return categories, nil
+	summaries := make([]product.CategorySummary, len(rows))
+	for i, row := range rows {
+		summaries[i] = product.CategorySummary{
+			Category: product.Category{
+				ID:        row.ID,
+				Code:      row.Code,
+				Name:      row.Name,
+				UpdatedAt: row.UpdatedAt,
+			},
+			ProductCount: row.ProductCount,
+			OnSaleCount:  row.OnSaleCount,
+		}
+	}
+
+	return summaries, total, nil
 }
 
 // Create creates a new category.
-func (r *CategoryRepository) Create(cat product.Category) (*product.Category, error) {
+func (r *CategoryRepository) Create(ctx context.Context, cat product.Category) (*product.Category, error) {
 	model := categoryModel{
 		Code: cat.Code,
 		Name: cat.Name,
 	}
 
-	err := r.db.Create(&model).Error
+	err := conn(ctx, r.db).Create(&model).Error
 	if err != nil {
 		return nil, err
 	}
 
 	return &product.Category{
-		ID:   model.ID,
-		Code: model.Code,
-		Name: model.Name,
+		ID:        model.ID,
+		Code:      model.Code,
+		Name:      model.Name,
+		UpdatedAt: model.UpdatedAt,
 	}, nil
 }
+
+// Upsert creates the category or renames the existing one with the same code.
+func (r *CategoryRepository) Upsert(ctx context.Context, cat product.Category) (product.Change, error) {
+	db := conn(ctx, r.db)
+
+	var model categoryModel
+	err := db.Where("code = ?", cat.Code).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		model = categoryModel{Code: cat.Code, Name: cat.Name}
+		if err := db.Create(&model).Error; err != nil {
+			return product.Unchanged, err
+		}
+		return product.Created, nil
+	}
+	if err != nil {
+		return product.Unchanged, err
+	}
+
+	if model.Name == cat.Name {
+		return product.Unchanged, nil
+	}
+	model.Name = cat.Name
+	if err := db.Save(&model).Error; err != nil {
+		return product.Unchanged, err
+	}
+	return product.Updated, nil
+}
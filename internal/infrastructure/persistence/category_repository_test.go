@@ -0,0 +1,113 @@
+//go:build integration
+// +build integration
+
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryRepository_GetAll(t *testing.T) {
+	db := setupTestDB(t)
+	seedTestData(t, db)
+	require.NoError(t, db.Create(&categoryModel{Code: "kids", Name: "Kids"}).Error)
+	repo := NewCategoryRepository(db)
+
+	t.Run("returns every category with product counts ordered by name", func(t *testing.T) {
+		summaries, total, err := repo.GetAll(context.Background(), product.CategoryQuery{})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), total)
+		require.Len(t, summaries, 5)
+		assert.Equal(t, []string{"accessories", "boots", "clothing", "kids", "shoes"}, summaryCodes(summaries))
+		assert.Equal(t, int64(3), summaries[2].ProductCount)
+		assert.Equal(t, int64(0), summaries[3].ProductCount)
+		assert.Equal(t, "Clothing", summaries[2].Name)
+	})
+
+	t.Run("counts on-sale products with the sale criteria", func(t *testing.T) {
+		summaries, _, err := repo.GetAll(context.Background(), product.CategoryQuery{
+			Sale: product.SaleCriteria{CategoryCodes: []string{"shoes"}, SKUs: []string{"SKU001A"}},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, summaries, 5)
+		onSale := make([]int64, len(summaries))
+		for i, s := range summaries {
+			onSale[i] = s.OnSaleCount
+		}
+		assert.Equal(t, []int64{0, 0, 1, 0, 2}, onSale)
+	})
+
+	t.Run("counts on-sale products matching the sale conditions", func(t *testing.T) {
+		summaries, _, err := repo.GetAll(context.Background(), product.CategoryQuery{
+			Sale: product.SaleCriteria{Conditions: []product.Condition{saleCondition(t, `category == "accessories" and price > 20`)}},
+		})
+
+		require.NoError(t, err)
+		onSale := make([]int64, len(summaries))
+		for i, s := range summaries {
+			onSale[i] = s.OnSaleCount
+		}
+		assert.Equal(t, []int64{1, 0, 0, 0, 0}, onSale)
+	})
+
+	t.Run("sorts by product count descending with code as tie-breaker", func(t *testing.T) {
+		summaries, _, err := repo.GetAll(context.Background(), product.CategoryQuery{Sort: product.CategorySortProducts, Desc: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"accessories", "clothing", "shoes", "boots", "kids"}, summaryCodes(summaries))
+	})
+
+	t.Run("paginates while reporting the total", func(t *testing.T) {
+		summaries, total, err := repo.GetAll(context.Background(), product.CategoryQuery{Offset: 1, Limit: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), total)
+		assert.Equal(t, []string{"boots", "clothing"}, summaryCodes(summaries))
+	})
+}
+
+func TestCategoryRepository_Upsert(t *testing.T) {
+	db := setupTestDB(t)
+	seedTestData(t, db)
+	repo := NewCategoryRepository(db)
+
+	t.Run("creates missing categories", func(t *testing.T) {
+		change, err := repo.Upsert(context.Background(), product.Category{Code: "kids", Name: "Kids"})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Created, change)
+	})
+
+	t.Run("leaves identical categories unchanged", func(t *testing.T) {
+		change, err := repo.Upsert(context.Background(), product.Category{Code: "shoes", Name: "Shoes"})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Unchanged, change)
+	})
+
+	t.Run("renames categories by code", func(t *testing.T) {
+		change, err := repo.Upsert(context.Background(), product.Category{Code: "shoes", Name: "Sneakers"})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		summaries, _, err := repo.GetAll(context.Background(), product.CategoryQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, "Sneakers", summaries[4].Name)
+	})
+}
+
+func summaryCodes(summaries []product.CategorySummary) []string {
+	codes := make([]string, len(summaries))
+	for i, s := range summaries {
+		codes[i] = s.Code
+	}
+	return codes
+}
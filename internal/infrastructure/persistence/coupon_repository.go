@@ -0,0 +1,213 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type couponModel struct {
+	ID             uint            `gorm:"primaryKey"`
+	Code           string          `gorm:"not null;size:32;uniqueIndex"`
+	BasketRuleID   uint            `gorm:"not null"`
+	BasketRule     basketRuleModel `gorm:"foreignKey:BasketRuleID"`
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+	MaxRedemptions int `gorm:"not null;default:0"`
+	MaxPerCustomer int `gorm:"not null;default:0"`
+	UpdatedAt      time.Time
+}
+
+func (couponModel) TableName() string {
+	return "coupons"
+}
+
+type couponRedemptionModel struct {
+	ID         uint   `gorm:"primaryKey"`
+	CouponID   uint   `gorm:"not null;index:idx_coupon_redemptions_coupon_customer"`
+	CustomerID string `gorm:"not null;size:64;index:idx_coupon_redemptions_coupon_customer"`
+	CreatedAt  time.Time
+}
+
+func (couponRedemptionModel) TableName() string {
+	return "coupon_redemptions"
+}
+
+// CouponRepository stores coupons and their redemptions using GORM.
+type CouponRepository struct {
+	db *gorm.DB
+}
+
+// NewCouponRepository creates a new GORM coupon repository.
+func NewCouponRepository(db *gorm.DB) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+// Get returns a coupon with how many times it was redeemed, in total and by
+// customerID.
+func (r *CouponRepository) Get(ctx context.Context, code, customerID string) (discount.Coupon, discount.Usage, error) {
+	db := conn(ctx, r.db)
+
+	model, err := findCoupon(db, code, false)
+	if err != nil {
+		return discount.Coupon{}, discount.Usage{}, err
+	}
+	usage, err := couponUsage(db, model.ID, customerID)
+	if err != nil {
+		return discount.Coupon{}, discount.Usage{}, err
+	}
+	return toDomainCoupon(model), usage, nil
+}
+
+// Redeem records a redemption of the coupon by customerID at now. The coupon
+// row is locked while its usage is counted and the redemption written, so
+// concurrent redemptions of the same coupon run one at a time and cannot
+// exceed its limits.
+func (r *CouponRepository) Redeem(ctx context.Context, code, customerID string, now time.Time) (discount.Redemption, error) {
+	var redemption discount.Redemption
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		model, err := findCoupon(tx, code, true)
+		if err != nil {
+			return err
+		}
+		usage, err := couponUsage(tx, model.ID, customerID)
+		if err != nil {
+			return err
+		}
+		if err := toDomainCoupon(model).Check(now, usage); err != nil {
+			return err
+		}
+
+		record := couponRedemptionModel{CouponID: model.ID, CustomerID: customerID, CreatedAt: now}
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+		redemption = discount.Redemption{Code: model.Code, CustomerID: customerID, RedeemedAt: record.CreatedAt}
+		return nil
+	})
+	if err != nil {
+		return discount.Redemption{}, err
+	}
+	return redemption, nil
+}
+
+// Upsert creates the coupon or updates the rule, window and limits of the one
+// with the same code. The basket rule is looked up by name.
+func (r *CouponRepository) Upsert(ctx context.Context, coupon discount.Coupon) (product.Change, error) {
+	db := conn(ctx, r.db)
+
+	var rule basketRuleModel
+	if err := db.Select("id").Where("name = ?", coupon.Rule).Take(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return product.Unchanged, fmt.Errorf("basket rule %s: %w", coupon.Rule, product.ErrNotFound)
+		}
+		return product.Unchanged, err
+	}
+
+	values := couponModel{
+		Code:           coupon.Code,
+		BasketRuleID:   rule.ID,
+		StartsAt:       optionalTime(coupon.StartsAt),
+		EndsAt:         optionalTime(coupon.EndsAt),
+		MaxRedemptions: coupon.MaxRedemptions,
+		MaxPerCustomer: coupon.MaxPerCustomer,
+	}
+
+	var model couponModel
+	err := db.Where("code = ?", coupon.Code).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := db.Omit("BasketRule").Create(&values).Error; err != nil {
+			return product.Unchanged, err
+		}
+		return product.Created, nil
+	}
+	if err != nil {
+		return product.Unchanged, err
+	}
+
+	if model.BasketRuleID == values.BasketRuleID && sameTime(model.StartsAt, values.StartsAt) &&
+		sameTime(model.EndsAt, values.EndsAt) && model.MaxRedemptions == values.MaxRedemptions &&
+		model.MaxPerCustomer == values.MaxPerCustomer {
+		return product.Unchanged, nil
+	}
+	values.ID = model.ID
+	if err := db.Omit("BasketRule").Save(&values).Error; err != nil {
+		return product.Unchanged, err
+	}
+	return product.Updated, nil
+}
+
+// findCoupon loads a coupon with the name of its rule, optionally locking its row.
+func findCoupon(db *gorm.DB, code string, lock bool) (couponModel, error) {
+	query := db.Preload("BasketRule", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name")
+	})
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var model couponModel
+	if err := query.Where("code = ?", code).Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model, fmt.Errorf("coupon %s: %w", code, discount.ErrCouponNotFound)
+		}
+		return model, err
+	}
+	return model, nil
+}
+
+// couponUsage counts the redemptions of a coupon, and those of customerID when given.
+func couponUsage(db *gorm.DB, couponID uint, customerID string) (discount.Usage, error) {
+	var usage struct {
+		Total    int
+		Customer int
+	}
+	err := db.Model(&couponRedemptionModel{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE customer_id = ?) AS customer", customerID).
+		Where("coupon_id = ?", couponID).
+		Scan(&usage).Error
+	if err != nil {
+		return discount.Usage{}, err
+	}
+	if customerID == "" {
+		usage.Customer = 0
+	}
+	return discount.Usage{Total: usage.Total, Customer: usage.Customer}, nil
+}
+
+func toDomainCoupon(model couponModel) discount.Coupon {
+	coupon := discount.Coupon{
+		Code:           model.Code,
+		Rule:           model.BasketRule.Name,
+		MaxRedemptions: model.MaxRedemptions,
+		MaxPerCustomer: model.MaxPerCustomer,
+	}
+	if model.StartsAt != nil {
+		coupon.StartsAt = *model.StartsAt
+	}
+	if model.EndsAt != nil {
+		coupon.EndsAt = *model.EndsAt
+	}
+	return coupon
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,140 @@
+//go:build integration
+// +build integration
+
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCouponTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := setupTestDB(t)
+	_, err := NewBasketRuleRepository(db).Upsert(context.Background(), discount.BasketRule{
+		Name: "welcome", Kind: discount.BasketThreshold, MinSpend: decimal.NewFromInt(1), Percentage: 10,
+		Precedence: discount.PrecedenceAfterItems, CouponOnly: true,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestCouponRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("upserts coupons by code", func(t *testing.T) {
+		repo := NewCouponRepository(setupCouponTestDB(t))
+		coupon := discount.Coupon{Code: "WELCOME", Rule: "welcome", EndsAt: now.AddDate(0, 1, 0), MaxPerCustomer: 1}
+
+		change, err := repo.Upsert(ctx, coupon)
+		require.NoError(t, err)
+		assert.Equal(t, product.Created, change)
+
+		change, err = repo.Upsert(ctx, coupon)
+		require.NoError(t, err)
+		assert.Equal(t, product.Unchanged, change)
+
+		coupon.MaxRedemptions = 50
+		change, err = repo.Upsert(ctx, coupon)
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		stored, usage, err := repo.Get(ctx, "WELCOME", "")
+		require.NoError(t, err)
+		assert.Equal(t, "welcome", stored.Rule)
+		assert.True(t, stored.StartsAt.IsZero())
+		assert.True(t, stored.EndsAt.Equal(coupon.EndsAt))
+		assert.Equal(t, 50, stored.MaxRedemptions)
+		assert.Equal(t, discount.Usage{}, usage)
+	})
+
+	t.Run("rejects coupons of unknown rules", func(t *testing.T) {
+		repo := NewCouponRepository(setupCouponTestDB(t))
+
+		_, err := repo.Upsert(ctx, discount.Coupon{Code: "NOPE", Rule: "nope"})
+
+		assert.ErrorIs(t, err, product.ErrNotFound)
+	})
+
+	t.Run("counts redemptions in total and per customer", func(t *testing.T) {
+		repo := NewCouponRepository(setupCouponTestDB(t))
+		_, err := repo.Upsert(ctx, discount.Coupon{Code: "WELCOME", Rule: "welcome", MaxPerCustomer: 1})
+		require.NoError(t, err)
+
+		redemption, err := repo.Redeem(ctx, "WELCOME", "c-1", now)
+		require.NoError(t, err)
+		assert.Equal(t, "c-1", redemption.CustomerID)
+		assert.True(t, redemption.RedeemedAt.Equal(now))
+		_, err = repo.Redeem(ctx, "WELCOME", "c-2", now)
+		require.NoError(t, err)
+
+		_, err = repo.Redeem(ctx, "WELCOME", "c-1", now)
+		assert.ErrorIs(t, err, discount.ErrCouponExhausted)
+
+		_, usage, err := repo.Get(ctx, "WELCOME", "c-1")
+		require.NoError(t, err)
+		assert.Equal(t, discount.Usage{Total: 2, Customer: 1}, usage)
+	})
+
+	t.Run("rejects redemptions outside the window", func(t *testing.T) {
+		repo := NewCouponRepository(setupCouponTestDB(t))
+		_, err := repo.Upsert(ctx, discount.Coupon{Code: "WELCOME", Rule: "welcome", StartsAt: now.Add(time.Hour)})
+		require.NoError(t, err)
+
+		_, err = repo.Redeem(ctx, "WELCOME", "c-1", now)
+
+		assert.ErrorIs(t, err, discount.ErrCouponInactive)
+	})
+
+	t.Run("does not exceed the limit under concurrent redemptions", func(t *testing.T) {
+		repo := NewCouponRepository(setupCouponTestDB(t))
+		_, err := repo.Upsert(ctx, discount.Coupon{Code: "WELCOME", Rule: "welcome", MaxRedemptions: 3})
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		succeeded, exhausted := 0, 0
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := repo.Redeem(ctx, "WELCOME", fmt.Sprintf("c-%d", i), now)
+				mu.Lock()
+				defer mu.Unlock()
+				if err == nil {
+					succeeded++
+				} else if assert.ErrorIs(t, err, discount.ErrCouponExhausted) {
+					exhausted++
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, 3, succeeded)
+		assert.Equal(t, 7, exhausted)
+		_, usage, err := repo.Get(ctx, "WELCOME", "")
+		require.NoError(t, err)
+		assert.Equal(t, 3, usage.Total)
+	})
+
+	t.Run("returns not found for unknown coupons", func(t *testing.T) {
+		repo := NewCouponRepository(setupCouponTestDB(t))
+
+		_, _, err := repo.Get(ctx, "NOPE", "")
+		assert.ErrorIs(t, err, discount.ErrCouponNotFound)
+
+		_, err = repo.Redeem(ctx, "NOPE", "c-1", now)
+		assert.ErrorIs(t, err, discount.ErrCouponNotFound)
+	})
+}
@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"gorm.io/gorm"
+)
+
+type discountCapModel struct {
+	ID           uint   `gorm:"primaryKey"`
+	CategoryCode string `gorm:"uniqueIndex;not null;size:32"`
+	Percentage   int    `gorm:"not null"`
+	UpdatedAt    time.Time
+}
+
+func (discountCapModel) TableName() string {
+	return "discount_caps"
+}
+
+// DiscountCapRepository stores category discount caps using GORM.
+type DiscountCapRepository struct {
+	db *gorm.DB
+}
+
+// NewDiscountCapRepository creates a new GORM discount cap repository.
+func NewDiscountCapRepository(db *gorm.DB) *DiscountCapRepository {
+	return &DiscountCapRepository{db: db}
+}
+
+// GetAll retrieves every cap ordered by category code.
+func (r *DiscountCapRepository) GetAll(ctx context.Context) ([]discount.Cap, error) {
+	var models []discountCapModel
+	if err := conn(ctx, r.db).Order("category_code").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	caps := make([]discount.Cap, len(models))
+	for i, m := range models {
+		caps[i] = discount.Cap{Category: m.CategoryCode, Percentage: m.Percentage}
+	}
+	return caps, nil
+}
+
+// Upsert creates the cap or updates the percentage of the one of the same category.
+func (r *DiscountCapRepository) Upsert(ctx context.Context, c discount.Cap) (product.Change, error) {
+	db := conn(ctx, r.db)
+
+	var model discountCapModel
+	err := db.Where("category_code = ?", c.Category).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		model = discountCapModel{CategoryCode: c.Category, Percentage: c.Percentage}
+		if err := db.Create(&model).Error; err != nil {
+			return product.Unchanged, err
+		}
+		return product.Created, nil
+	}
+	if err != nil {
+		return product.Unchanged, err
+	}
+
+	if model.Percentage == c.Percentage {
+		return product.Unchanged, nil
+	}
+	model.Percentage = c.Percentage
+	if err := db.Save(&model).Error; err != nil {
+		return product.Unchanged, err
+	}
+	return product.Updated, nil
+}
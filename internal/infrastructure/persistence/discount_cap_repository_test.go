@@ -0,0 +1,47 @@
+//go:build integration
+// +build integration
+
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscountCapRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewDiscountCapRepository(db)
+	ctx := context.Background()
+
+	t.Run("returns caps by category", func(t *testing.T) {
+		for _, c := range []discount.Cap{{Category: "clothing", Percentage: 40}, {Category: "boots", Percentage: 25}} {
+			change, err := repo.Upsert(ctx, c)
+			require.NoError(t, err)
+			assert.Equal(t, product.Created, change)
+		}
+
+		caps, err := repo.GetAll(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []discount.Cap{{Category: "boots", Percentage: 25}, {Category: "clothing", Percentage: 40}}, caps)
+	})
+
+	t.Run("upserts by category", func(t *testing.T) {
+		change, err := repo.Upsert(ctx, discount.Cap{Category: "boots", Percentage: 25})
+		require.NoError(t, err)
+		assert.Equal(t, product.Unchanged, change)
+
+		change, err = repo.Upsert(ctx, discount.Cap{Category: "boots", Percentage: 20})
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		caps, err := repo.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 20, caps[0].Percentage)
+	})
+}
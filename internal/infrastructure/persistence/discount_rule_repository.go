@@ -0,0 +1,120 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"gorm.io/gorm"
+)
+
+type discountRuleModel struct {
+	ID         uint   `gorm:"primaryKey"`
+	Kind       string `gorm:"not null;size:16;uniqueIndex:idx_discount_rules_kind_target_audience"`
+	Target     string `gorm:"not null;size:32;uniqueIndex:idx_discount_rules_kind_target_audience"`
+	Segments   string `gorm:"not null;size:255;default:'';uniqueIndex:idx_discount_rules_kind_target_audience"`
+	Markets    string `gorm:"not null;size:255;default:'';uniqueIndex:idx_discount_rules_kind_target_audience"`
+	Channels   string `gorm:"not null;size:255;default:'';uniqueIndex:idx_discount_rules_kind_target_audience"`
+	Expression string `gorm:"not null;type:text;default:''"`
+	Percentage int    `gorm:"not null"`
+	Position   int    `gorm:"not null;default:0"`
+	UpdatedAt  time.Time
+}
+
+func (discountRuleModel) TableName() string {
+	return "discount_rules"
+}
+
+// DiscountRuleRepository stores discount rules using GORM.
+type DiscountRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewDiscountRuleRepository creates a new GORM discount rule repository.
+func NewDiscountRuleRepository(db *gorm.DB) *DiscountRuleRepository {
+	return &DiscountRuleRepository{db: db}
+}
+
+// GetAll retrieves every rule in evaluation order.
+func (r *DiscountRuleRepository) GetAll(ctx context.Context) ([]discount.Rule, error) {
+	var models []discountRuleModel
+
+	err := conn(ctx, r.db).
+		Order("position, id").
+		Find(&models).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]discount.Rule, len(models))
+	for i, m := range models {
+		rules[i] = discount.Rule{
+			Kind:       discount.RuleKind(m.Kind),
+			Target:     m.Target,
+			Expression: m.Expression,
+			Percentage: m.Percentage,
+			Audience: discount.Audience{
+				Segments: splitList(m.Segments),
+				Markets:  splitList(m.Markets),
+				Channels: splitList(m.Channels),
+			},
+			Position: m.Position,
+		}
+	}
+	return rules, nil
+}
+
+// Upsert creates the rule or updates the expression, percentage and position
+// of the one with the same kind, target and audience.
+func (r *DiscountRuleRepository) Upsert(ctx context.Context, rule discount.Rule) (product.Change, error) {
+	db := conn(ctx, r.db)
+	segments := strings.Join(rule.Audience.Segments, ",")
+	markets := strings.Join(rule.Audience.Markets, ",")
+	channels := strings.Join(rule.Audience.Channels, ",")
+
+	var model discountRuleModel
+	err := db.Where("kind = ? AND target = ? AND segments = ? AND markets = ? AND channels = ?",
+		string(rule.Kind), rule.Target, segments, markets, channels).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		model = discountRuleModel{
+			Kind:       string(rule.Kind),
+			Target:     rule.Target,
+			Segments:   segments,
+			Markets:    markets,
+			Channels:   channels,
+			Expression: rule.Expression,
+			Percentage: rule.Percentage,
+			Position:   rule.Position,
+		}
+		if err := db.Create(&model).Error; err != nil {
+			return product.Unchanged, err
+		}
+		return product.Created, nil
+	}
+	if err != nil {
+		return product.Unchanged, err
+	}
+
+	if model.Expression == rule.Expression && model.Percentage == rule.Percentage && model.Position == rule.Position {
+		return product.Unchanged, nil
+	}
+	model.Expression = rule.Expression
+	model.Percentage = rule.Percentage
+	model.Position = rule.Position
+	if err := db.Save(&model).Error; err != nil {
+		return product.Unchanged, err
+	}
+	return product.Updated, nil
+}
+
+// splitList splits a stored comma-separated list; an empty string is an empty list.
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
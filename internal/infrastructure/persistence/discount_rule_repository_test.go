@@ -0,0 +1,90 @@
+//go:build integration
+// +build integration
+
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscountRuleRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewDiscountRuleRepository(db)
+	ctx := context.Background()
+
+	t.Run("returns rules by position", func(t *testing.T) {
+		for _, rule := range []discount.Rule{
+			{Kind: discount.RuleSKU, Target: "000003", Percentage: 15, Position: 2},
+			{Kind: discount.RuleCategory, Target: "boots", Percentage: 30, Position: 1},
+		} {
+			change, err := repo.Upsert(ctx, rule)
+			require.NoError(t, err)
+			assert.Equal(t, product.Created, change)
+		}
+
+		rules, err := repo.GetAll(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []discount.Rule{
+			{Kind: discount.RuleCategory, Target: "boots", Percentage: 30, Position: 1},
+			{Kind: discount.RuleSKU, Target: "000003", Percentage: 15, Position: 2},
+		}, rules)
+	})
+
+	t.Run("upserts by kind and target", func(t *testing.T) {
+		change, err := repo.Upsert(ctx, discount.Rule{Kind: discount.RuleCategory, Target: "boots", Percentage: 30, Position: 1})
+		require.NoError(t, err)
+		assert.Equal(t, product.Unchanged, change)
+
+		change, err = repo.Upsert(ctx, discount.Rule{Kind: discount.RuleCategory, Target: "boots", Percentage: 40, Position: 1})
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		rules, err := repo.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, 40, rules[0].Percentage)
+	})
+
+	t.Run("keeps rules for the same target apart per audience", func(t *testing.T) {
+		vip := discount.Rule{
+			Kind:       discount.RuleCategory,
+			Target:     "boots",
+			Percentage: 45,
+			Audience:   discount.Audience{Segments: []string{"vip"}, Markets: []string{"de", "at"}},
+			Position:   3,
+		}
+		change, err := repo.Upsert(ctx, vip)
+		require.NoError(t, err)
+		assert.Equal(t, product.Created, change)
+
+		rules, err := repo.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, rules, 3)
+		assert.Equal(t, 40, rules[0].Percentage)
+		assert.Equal(t, vip, rules[2])
+	})
+
+	t.Run("stores and updates the condition of expression rules", func(t *testing.T) {
+		premium := discount.Rule{Kind: discount.RuleExpression, Target: "premium", Expression: `price > 100`, Percentage: 10, Position: 4}
+		change, err := repo.Upsert(ctx, premium)
+		require.NoError(t, err)
+		assert.Equal(t, product.Created, change)
+
+		premium.Expression = `price > 200`
+		change, err = repo.Upsert(ctx, premium)
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		rules, err := repo.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, rules, 4)
+		assert.Equal(t, premium, rules[3])
+	})
+}
@@ -1,23 +1,43 @@
 package persistence
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
 const (
-	relationVariants = "Variants"
+	relationVariants = "Variants.StockLevels"
 	relationCategory = "Category"
 )
 
+// onSaleCondition matches products hit by the categories and SKUs of a
+// product.SaleCriteria; see onSaleClause for the conditions.
+// Arguments: category codes, SKUs, SKUs.
+const onSaleCondition = `products.category_id IN (SELECT id FROM categories WHERE code IN ?)
+	OR products.code IN ?
+	OR EXISTS (SELECT 1 FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.sku IN ?)`
+
+// inStockCondition matches products with at least one variant in stock.
+const inStockCondition = `EXISTS (SELECT 1 FROM product_variants
+	JOIN stock_levels ON stock_levels.variant_id = product_variants.id
+	WHERE product_variants.product_id = products.id AND stock_levels.quantity > 0)`
+
 type productModel struct {
 	ID         uint           `gorm:"primaryKey"`
 	Code       string         `gorm:"uniqueIndex;not null"`
 	Price      string         `gorm:"type:decimal(10,2);not null"`
+	CostPrice  *string        `gorm:"type:decimal(10,2)"`
 	CategoryID *uint          `gorm:"index"`
 	Category   *categoryModel `gorm:"foreignKey:CategoryID"`
 	Variants   []variantModel `gorm:"foreignKey:ProductID"`
+	UpdatedAt  time.Time
 }
 
 func (productModel) TableName() string {
@@ -25,9 +45,10 @@ func (productModel) TableName() string {
 }
 
 type categoryModel struct {
-	ID   uint   `gorm:"primaryKey"`
-	Code string `gorm:"uniqueIndex;not null;size:32"`
-	Name string `gorm:"not null;size:256"`
+	ID        uint   `gorm:"primaryKey"`
+	Code      string `gorm:"uniqueIndex;not null;size:32"`
+	Name      string `gorm:"not null;size:256"`
+	UpdatedAt time.Time
 }
 
 func (categoryModel) TableName() string {
@@ -35,11 +56,13 @@ func (categoryModel) TableName() string {
 }
 
 type variantModel struct {
-	ID        uint    `gorm:"primaryKey"`
-	ProductID uint    `gorm:"not null"`
-	Name      string  `gorm:"not null"`
-	SKU       string  `gorm:"uniqueIndex;not null"`
-	Price     *string `gorm:"type:decimal(10,2)"`
+	ID          uint              `gorm:"primaryKey"`
+	ProductID   uint              `gorm:"not null"`
+	Name        string            `gorm:"not null"`
+	SKU         string            `gorm:"uniqueIndex;not null"`
+	Price       *string           `gorm:"type:decimal(10,2)"`
+	StockLevels []stockLevelModel `gorm:"foreignKey:VariantID"`
+	UpdatedAt   time.Time
 }
 
 func (variantModel) TableName() string {
@@ -57,10 +80,10 @@ func NewProductRepository(db *gorm.DB) *ProductRepository {
 }
 
 // GetAll retrieves all products with their relations.
-func (r *ProductRepository) GetAll() ([]product.Product, error) {
+func (r *ProductRepository) GetAll(ctx context.Context) ([]product.Product, error) {
 	var models []productModel
 
-	err := r.db.
+	err := conn(ctx, r.db).
 		Preload(relationVariants).
 		Preload(relationCategory).
 		Find(&models).Error
@@ -72,39 +95,95 @@ func (r *ProductRepository) GetAll() ([]product.Product, error) {
 	return toDomainProducts(models), nil
 }
 
-// GetByCode retrieves a product by code with all relations.
-func (r *ProductRepository) GetByCode(code string) (*product.Product, error) {
+// GetByCode retrieves a product by code with the selected relations.
+func (r *ProductRepository) GetByCode(ctx context.Context, code string, relations product.Relations) (*product.Product, error) {
 	var model productModel
 
-	err := r.db.
-		Preload(relationVariants).
-		Preload(relationCategory).
+	err := preload(conn(ctx, r.db), relations).
 		Where("code = ?", code).
 		First(&model).Error
 
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	p := toDomainProduct(model)
+	return &p, nil
+}
+
+// GetByCodes retrieves the products matching any of the codes with all relations.
+// Codes without a matching product are ignored.
+func (r *ProductRepository) GetByCodes(ctx context.Context, codes []string) ([]product.Product, error) {
+	var models []productModel
+
+	err := conn(ctx, r.db).
+		Preload(relationVariants).
+		Preload(relationCategory).
+		Where("code IN ?", codes).
+		Find(&models).Error
+
 	if err != nil {
 		return nil, err
 	}
 
+	return toDomainProducts(models), nil
+}
+
+// GetByVariantSKU retrieves the product owning the variant with the given SKU,
+// with its category and all of its variants.
+func (r *ProductRepository) GetByVariantSKU(ctx context.Context, sku string) (*product.Product, error) {
+	var model productModel
+	db := conn(ctx, r.db)
+
+	err := db.
+		Preload(relationVariants).
+		Preload(relationCategory).
+		Where("id = (?)", db.Model(&variantModel{}).Select("product_id").Where("sku = ?", sku)).
+		First(&model).Error
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+
 	p := toDomainProduct(model)
 	return &p, nil
 }
 
+// GetByVariantSKUs retrieves the products owning any of the variants with the
+// given SKUs, with their category and all of their variants. SKUs without a
+// matching variant are ignored.
+func (r *ProductRepository) GetByVariantSKUs(ctx context.Context, skus []string) ([]product.Product, error) {
+	var models []productModel
+	db := conn(ctx, r.db)
+
+	err := db.
+		Preload(relationVariants).
+		Preload(relationCategory).
+		Where("id IN (?)", db.Model(&variantModel{}).Select("product_id").Where("sku IN ?", skus)).
+		Find(&models).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainProducts(models), nil
+}
+
 // GetFiltered retrieves products with pagination and filtering applied.
 // Returns the filtered products and the total count of products matching the filters.
-func (r *ProductRepository) GetFiltered(offset, limit int, filters product.Filter) ([]product.Product, int64, error) {
+// Only the selected relations are preloaded.
+func (r *ProductRepository) GetFiltered(ctx context.Context, offset, limit int, filters product.Filter, relations product.Relations) ([]product.Product, int64, error) {
 	var models []productModel
 	var total int64
+	db := conn(ctx, r.db)
 
-	query := r.applyFilters(r.db.Model(&productModel{}), filters)
+	query := r.applyFilters(db.Model(&productModel{}), filters)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	query = r.applyFilters(r.db, filters).
-		Preload(relationVariants).
-		Preload(relationCategory).
+	query = preload(r.applyFilters(db, filters), relations).
 		Offset(offset).
 		Limit(limit)
 
@@ -115,6 +194,381 @@ func (r *ProductRepository) GetFiltered(offset, limit int, filters product.Filte
 	return toDomainProducts(models), total, nil
 }
 
+// Stream calls fn with consecutive batches of at most batchSize products matching
+// the filters, ordered by id, with all relations. Batches are read with keyset
+// pagination, so memory use does not grow with the catalog and rows written
+// while streaming are neither skipped nor repeated.
+func (r *ProductRepository) Stream(ctx context.Context, filters product.Filter, batchSize int, fn func([]product.Product) error) error {
+	db := conn(ctx, r.db)
+	var lastID uint
+
+	for {
+		var models []productModel
+		err := preload(r.applyFilters(db, filters), product.AllRelations()).
+			Where("products.id > ?", lastID).
+			Order("products.id").
+			Limit(batchSize).
+			Find(&models).Error
+		if err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+
+		if err := fn(toDomainProducts(models)); err != nil {
+			return err
+		}
+		if len(models) < batchSize {
+			return nil
+		}
+		lastID = models[len(models)-1].ID
+	}
+}
+
+// GetFacets computes the requested facet counts over the products matching the filters.
+// Each facet ignores its own filter, so a selected category still shows counts for its siblings.
+func (r *ProductRepository) GetFacets(ctx context.Context, filters product.Filter, req product.FacetRequest, sale product.SaleCriteria) (product.Facets, error) {
+	var facets product.Facets
+	db := conn(ctx, r.db)
+
+	if req.Category {
+		counts, err := r.categoryFacet(db, filters)
+		if err != nil {
+			return product.Facets{}, err
+		}
+		facets.Categories = counts
+	}
+
+	if req.Price {
+		counts, err := r.priceFacet(db, filters, product.DefaultPriceBuckets())
+		if err != nil {
+			return product.Facets{}, err
+		}
+		facets.PriceBuckets = counts
+	}
+
+	if req.OnSale {
+		counts, err := r.onSaleFacet(db, filters, sale)
+		if err != nil {
+			return product.Facets{}, err
+		}
+		facets.OnSale = counts
+	}
+
+	return facets, nil
+}
+
+func (r *ProductRepository) categoryFacet(db *gorm.DB, filters product.Filter) ([]product.CategoryCount, error) {
+	var rows []struct {
+		Code  string
+		Count int64
+	}
+
+	filters.Category = ""
+	err := r.applyFilters(db.Model(&productModel{}), filters).
+		Joins("JOIN categories AS facet_categories ON facet_categories.id = products.category_id").
+		Select("facet_categories.code AS code, COUNT(*) AS count").
+		Group("facet_categories.code").
+		Order("facet_categories.code").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]product.CategoryCount, len(rows))
+	for i, row := range rows {
+		counts[i] = product.CategoryCount{Code: row.Code, Count: row.Count}
+	}
+	return counts, nil
+}
+
+func (r *ProductRepository) priceFacet(db *gorm.DB, filters product.Filter, buckets []product.PriceBucket) ([]product.PriceBucketCount, error) {
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+
+	var cases strings.Builder
+	var args []any
+	cases.WriteString("CASE")
+	for i, b := range buckets {
+		if b.Max != nil {
+			fmt.Fprintf(&cases, " WHEN products.price >= ? AND products.price < ? THEN %d", i)
+			args = append(args, b.Min, *b.Max)
+		} else {
+			fmt.Fprintf(&cases, " WHEN products.price >= ? THEN %d", i)
+			args = append(args, b.Min)
+		}
+	}
+	cases.WriteString(" ELSE -1 END")
+
+	filters.PriceLessThan = nil
+	err := r.applyFilters(db.Model(&productModel{}), filters).
+		Select(cases.String()+" AS bucket, COUNT(*) AS count", args...).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]product.PriceBucketCount, len(buckets))
+	for i, b := range buckets {
+		counts[i] = product.PriceBucketCount{Bucket: b}
+	}
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket < len(counts) {
+			counts[row.Bucket].Count = row.Count
+		}
+	}
+	return counts, nil
+}
+
+func (r *ProductRepository) onSaleFacet(db *gorm.DB, filters product.Filter, sale product.SaleCriteria) (*product.OnSaleCount, error) {
+	var total, onSale int64
+
+	if err := r.applyFilters(db.Model(&productModel{}), filters).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if !sale.IsEmpty() {
+		condition, args, err := onSaleClause(sale)
+		if err != nil {
+			return nil, err
+		}
+		err = r.applyFilters(db.Model(&productModel{}), filters).
+			Where(condition, args...).
+			Count(&onSale).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &product.OnSaleCount{OnSale: onSale, NotOnSale: total - onSale}, nil
+}
+
+// conditionColumns are the product columns product.Condition fields compare.
+var conditionColumns = map[string]string{
+	"category": "COALESCE((SELECT code FROM categories WHERE categories.id = products.category_id), '')",
+	"code":     "products.code",
+	"price":    "products.price",
+}
+
+// conditionOperators are the SQL operators of the product.Condition comparisons.
+var conditionOperators = map[string]string{
+	"==": "=", "!=": "<>", "<": "<", "<=": "<=", ">": ">", ">=": ">=",
+	"in": "IN", "startsWith": "LIKE", "endsWith": "LIKE", "contains": "LIKE",
+}
+
+// likeEscaper escapes the LIKE wildcards of a pattern, with the default escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// onSaleClause returns the condition matching the products hit by sale, with its arguments.
+func onSaleClause(sale product.SaleCriteria) (string, []any, error) {
+	clause := "(" + onSaleCondition
+	args := []any{sale.CategoryCodes, sale.SKUs, sale.SKUs}
+	for _, c := range sale.Conditions {
+		condition, conditionArgs, err := conditionSQL(c)
+		if err != nil {
+			return "", nil, err
+		}
+		clause += "\n\tOR " + condition
+		args = append(args, conditionArgs...)
+	}
+	return clause + ")", args, nil
+}
+
+// conditionSQL translates a product.Condition into a condition over products.
+func conditionSQL(c product.Condition) (string, []any, error) {
+	switch c.Op {
+	case "and", "or", "not":
+		if len(c.Conditions) == 0 || (c.Op == "not" && len(c.Conditions) != 1) {
+			return "", nil, fmt.Errorf("condition %s with %d operands", c.Op, len(c.Conditions))
+		}
+		parts := make([]string, len(c.Conditions))
+		var args []any
+		for i, operand := range c.Conditions {
+			part, operandArgs, err := conditionSQL(operand)
+			if err != nil {
+				return "", nil, err
+			}
+			parts[i] = part
+			args = append(args, operandArgs...)
+		}
+		if c.Op == "not" {
+			return "NOT " + parts[0], args, nil
+		}
+		return "(" + strings.Join(parts, " "+strings.ToUpper(c.Op)+" ") + ")", args, nil
+	}
+
+	column, ok := conditionColumns[c.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("condition on unknown field %q", c.Field)
+	}
+	operator, ok := conditionOperators[c.Op]
+	if !ok || (operator == "LIKE" && c.Field == "price") {
+		return "", nil, fmt.Errorf("condition with unknown operator %q for %s", c.Op, c.Field)
+	}
+
+	var values []any
+	if c.Field == "price" {
+		for _, n := range c.Numbers {
+			values = append(values, n)
+		}
+	} else {
+		for _, v := range c.Values {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return "", nil, fmt.Errorf("condition %s %s without values", c.Field, c.Op)
+	}
+
+	var arg any
+	switch c.Op {
+	case "in":
+		arg = values
+	case "startsWith":
+		arg = likeEscaper.Replace(c.Values[0]) + "%"
+	case "endsWith":
+		arg = "%" + likeEscaper.Replace(c.Values[0])
+	case "contains":
+		arg = "%" + likeEscaper.Replace(c.Values[0]) + "%"
+	default:
+		arg = values[0]
+	}
+	return fmt.Sprintf("(%s %s ?)", column, operator), []any{arg}, nil
+}
+
+// Upsert creates the product or updates the existing one with the same code,
+// together with its variants matched by SKU, in one transaction. The category is
+// looked up by code. A zero cost price keeps the stored one. Variants with a
+// zero price inherit the product price, and stored variants missing from p are kept.
+func (r *ProductRepository) Upsert(ctx context.Context, p product.Product) (product.Change, error) {
+	change := product.Unchanged
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var categoryID *uint
+		if p.Category != nil {
+			var category categoryModel
+			if err := tx.Where("code = ?", p.Category.Code).Take(&category).Error; err != nil {
+				return fmt.Errorf("category %s: %w", p.Category.Code, translateError(err))
+			}
+			categoryID = &category.ID
+		}
+
+		var model productModel
+		err := tx.Where("code = ?", p.Code).Take(&model).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			model = productModel{Code: p.Code, Price: p.Price.StringFixed(2), CostPrice: costPrice(p), CategoryID: categoryID}
+			if err := tx.Create(&model).Error; err != nil {
+				return err
+			}
+			change = product.Created
+		case err != nil:
+			return err
+		case !samePrice(&model.Price, p.Price) || !sameID(model.CategoryID, categoryID) ||
+			(!p.CostPrice.IsZero() && !samePrice(model.CostPrice, p.CostPrice)):
+			model.Price = p.Price.StringFixed(2)
+			if !p.CostPrice.IsZero() {
+				model.CostPrice = costPrice(p)
+			}
+			model.CategoryID = categoryID
+			if err := tx.Save(&model).Error; err != nil {
+				return err
+			}
+			change = product.Updated
+		}
+
+		for _, v := range p.Variants {
+			variantChange, err := upsertVariant(tx, model.ID, v)
+			if err != nil {
+				return fmt.Errorf("variant %s: %w", v.SKU, err)
+			}
+			if variantChange != product.Unchanged && change == product.Unchanged {
+				change = product.Updated
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return product.Unchanged, err
+	}
+	return change, nil
+}
+
+// UpsertVariant creates the variant or updates the one with the same SKU under
+// the product with the given code. A zero price inherits the product price.
+func (r *ProductRepository) UpsertVariant(ctx context.Context, productCode string, v product.Variant) (product.Change, error) {
+	db := conn(ctx, r.db)
+
+	var model productModel
+	if err := db.Select("id").Where("code = ?", productCode).Take(&model).Error; err != nil {
+		return product.Unchanged, fmt.Errorf("product %s: %w", productCode, translateError(err))
+	}
+	return upsertVariant(db, model.ID, v)
+}
+
+// upsertVariant creates the variant or updates the one with the same SKU,
+// moving it to productID if it belonged to another product.
+func upsertVariant(tx *gorm.DB, productID uint, v product.Variant) (product.Change, error) {
+	var price *string
+	if !v.Price.IsZero() {
+		fixed := v.Price.StringFixed(2)
+		price = &fixed
+	}
+
+	var model variantModel
+	err := tx.Where("sku = ?", v.SKU).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		model = variantModel{ProductID: productID, Name: v.Name, SKU: v.SKU, Price: price}
+		if err := tx.Create(&model).Error; err != nil {
+			return product.Unchanged, err
+		}
+		return product.Created, nil
+	}
+	if err != nil {
+		return product.Unchanged, err
+	}
+
+	if model.ProductID == productID && model.Name == v.Name && samePrice(model.Price, v.Price) {
+		return product.Unchanged, nil
+	}
+	model.ProductID = productID
+	model.Name = v.Name
+	model.Price = price
+	if err := tx.Save(&model).Error; err != nil {
+		return product.Unchanged, err
+	}
+	return product.Updated, nil
+}
+
+// costPrice returns the stored form of the cost price of p, NULL when zero.
+func costPrice(p product.Product) *string {
+	if p.CostPrice.IsZero() {
+		return nil
+	}
+	fixed := p.CostPrice.StringFixed(2)
+	return &fixed
+}
+
+// samePrice reports whether a stored price equals price, a NULL price matching zero.
+func samePrice(stored *string, price decimal.Decimal) bool {
+	if stored == nil {
+		return price.IsZero()
+	}
+	current, err := decimal.NewFromString(*stored)
+	return err == nil && current.Equal(price)
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (r *ProductRepository) applyFilters(query *gorm.DB, filters product.Filter) *gorm.DB {
 	if filters.Category != "" {
 		query = query.
@@ -126,6 +580,28 @@ func (r *ProductRepository) applyFilters(query *gorm.DB, filters product.Filter)
 		query = query.Where("products.price < ?", filters.PriceLessThan)
 	}
 
+	if filters.InStock {
+		query = query.Where(inStockCondition)
+	}
+
+	return query
+}
+
+// translateError maps GORM errors to domain errors.
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return product.ErrNotFound
+	}
+	return err
+}
+
+func preload(query *gorm.DB, relations product.Relations) *gorm.DB {
+	if relations.Variants {
+		query = query.Preload(relationVariants)
+	}
+	if relations.Category {
+		query = query.Preload(relationCategory)
+	}
 	return query
 }
 
@@ -142,15 +618,20 @@ func toDomainProduct(m productModel) product.Product {
 		ID:         m.ID,
 		Code:       m.Code,
 		CategoryID: m.CategoryID,
+		UpdatedAt:  m.UpdatedAt,
 	}
 
 	p.Price, _ = decimal.NewFromString(m.Price)
+	if m.CostPrice != nil {
+		p.CostPrice, _ = decimal.NewFromString(*m.CostPrice)
+	}
 
 	if m.Category != nil {
 		p.Category = &product.Category{
-			ID:   m.Category.ID,
-			Code: m.Category.Code,
-			Name: m.Category.Name,
+			ID:        m.Category.ID,
+			Code:      m.Category.Code,
+			Name:      m.Category.Name,
+			UpdatedAt: m.Category.UpdatedAt,
 		}
 	}
 
@@ -169,6 +650,14 @@ func toDomainProduct(m productModel) product.Product {
 				Name:      v.Name,
 				SKU:       v.SKU,
 				Price:     price,
+				UpdatedAt: v.UpdatedAt,
+			}
+			// Stock changes count as variant changes for Last-Modified.
+			for _, level := range v.StockLevels {
+				p.Variants[i].Quantity += level.Quantity
+				if level.UpdatedAt.After(p.Variants[i].UpdatedAt) {
+					p.Variants[i].UpdatedAt = level.UpdatedAt
+				}
 			}
 		}
 	}
@@ -5,10 +5,14 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/mytheresa/go-hiring-challenge/internal/application/seed"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/discount"
 	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/mytheresa/go-hiring-challenge/internal/infrastructure/fixture"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,82 +51,21 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(pgdriver.Open(connStr), &gorm.Config{})
 	require.NoError(t, err, "Failed to connect to PostgreSQL container")
 
-	err = db.AutoMigrate(&productModel{}, &categoryModel{}, &variantModel{})
+	err = db.AutoMigrate(&productModel{}, &categoryModel{}, &variantModel{}, &stockLevelModel{}, &reservationModel{}, &reservationItemModel{}, &discountRuleModel{}, &basketRuleModel{}, &couponModel{}, &couponRedemptionModel{}, &discountCapModel{})
 	require.NoError(t, err, "Failed to migrate database schema")
 
 	return db
 }
 
+// seedTestData loads the development catalog, fixtures/catalog.yaml, through
+// the repositories, the way the seed command does.
 func seedTestData(t *testing.T, db *gorm.DB) {
-	categories := []categoryModel{
-		{ID: 1, Code: "clothing", Name: "Clothing"},
-		{ID: 2, Code: "shoes", Name: "Shoes"},
-		{ID: 3, Code: "accessories", Name: "Accessories"},
-	}
-	for _, cat := range categories {
-		require.NoError(t, db.Create(&cat).Error)
-	}
-
-	products := []productModel{
-		{
-			ID:         1,
-			Code:       "PROD001",
-			Price:      "89.99",
-			CategoryID: uintPtr(1),
-		},
-		{
-			ID:         2,
-			Code:       "PROD002",
-			Price:      "129.99",
-			CategoryID: uintPtr(2),
-		},
-		{
-			ID:         3,
-			Code:       "PROD003",
-			Price:      "49.99",
-			CategoryID: uintPtr(3),
-		},
-		{
-			ID:         4,
-			Code:       "PROD004",
-			Price:      "199.99",
-			CategoryID: uintPtr(1),
-		},
-		{
-			ID:    5,
-			Code:  "PROD005",
-			Price: "15.50",
-			// empty category
-		},
-	}
-	for _, prod := range products {
-		require.NoError(t, db.Create(&prod).Error)
-	}
-
-	priceSmall := "89.99"
-	variants := []variantModel{
-		{
-			ID:        1,
-			ProductID: 1,
-			Name:      "Small",
-			SKU:       "PROD001-S",
-			Price:     &priceSmall,
-		},
-		{
-			ID:        2,
-			ProductID: 1,
-			Name:      "Large",
-			SKU:       "PROD001-L",
-			Price:     nil,
-		},
-	}
-	for _, v := range variants {
-		require.NoError(t, db.Create(&v).Error)
-	}
-}
+	testFixture, err := fixture.Load("../../../fixtures/catalog.yaml")
+	require.NoError(t, err)
 
-func uintPtr(u uint) *uint {
-	return &u
+	service := seed.NewService(NewTransactor(db), NewCategoryRepository(db), NewProductRepository(db), NewDiscountRuleRepository(db), NewDiscountCapRepository(db), NewBasketRuleRepository(db), NewCouponRepository(db), NewStockRepository(db))
+	_, err = service.Seed(context.Background(), testFixture)
+	require.NoError(t, err)
 }
 
 func TestProductRepository_GetAll(t *testing.T) {
@@ -131,29 +74,41 @@ func TestProductRepository_GetAll(t *testing.T) {
 		seedTestData(t, db)
 		repo := NewProductRepository(db)
 
-		products, err := repo.GetAll()
+		products, err := repo.GetAll(context.Background())
 
 		require.NoError(t, err)
-		assert.Len(t, products, 5)
+		assert.Len(t, products, 9)
 
 		prod1 := findProductByCode(products, "PROD001")
 		require.NotNil(t, prod1)
 		assert.Equal(t, "PROD001", prod1.Code)
-		assert.Equal(t, "89.99", prod1.Price.String())
+		assert.Equal(t, "10.99", prod1.Price.String())
 		require.NotNil(t, prod1.Category)
 		assert.Equal(t, "clothing", prod1.Category.Code)
-		assert.Len(t, prod1.Variants, 2)
+		assert.Len(t, prod1.Variants, 3)
+	})
 
-		prod5 := findProductByCode(products, "PROD005")
-		require.NotNil(t, prod5)
-		assert.Nil(t, prod5.Category)
+	t.Run("returns products without a category", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+		_, err := repo.Upsert(context.Background(), product.Product{Code: "PROD010", Price: decimal.RequireFromString("15.50")})
+		require.NoError(t, err)
+
+		products, err := repo.GetAll(context.Background())
+
+		require.NoError(t, err)
+		assert.Len(t, products, 10)
+		prod10 := findProductByCode(products, "PROD010")
+		require.NotNil(t, prod10)
+		assert.Nil(t, prod10.Category)
 	})
 
 	t.Run("returns empty slice when no products exist", func(t *testing.T) {
 		db := setupTestDB(t)
 		repo := NewProductRepository(db)
 
-		products, err := repo.GetAll()
+		products, err := repo.GetAll(context.Background())
 
 		require.NoError(t, err)
 		assert.Empty(t, products)
@@ -171,11 +126,11 @@ func TestProductRepository_GetFiltered(t *testing.T) {
 			Category: "clothing",
 		}
 
-		products, total, err := repo.GetFiltered(0, 10, filters)
+		products, total, err := repo.GetFiltered(context.Background(), 0, 10, filters, product.AllRelations())
 
 		require.NoError(t, err)
-		assert.Equal(t, int64(2), total)
-		assert.Len(t, products, 2)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, products, 3)
 
 		for _, p := range products {
 			require.NotNil(t, p.Category)
@@ -188,12 +143,12 @@ func TestProductRepository_GetFiltered(t *testing.T) {
 		seedTestData(t, db)
 		repo := NewProductRepository(db)
 
-		maxPrice := decimal.NewFromFloat(100.0)
+		maxPrice := decimal.NewFromFloat(10.0)
 		filters := product.Filter{
 			PriceLessThan: &maxPrice,
 		}
 
-		products, total, err := repo.GetFiltered(0, 10, filters)
+		products, total, err := repo.GetFiltered(context.Background(), 0, 10, filters, product.AllRelations())
 
 		require.NoError(t, err)
 		assert.Equal(t, int64(3), total)
@@ -210,13 +165,13 @@ func TestProductRepository_GetFiltered(t *testing.T) {
 		seedTestData(t, db)
 		repo := NewProductRepository(db)
 
-		maxPrice := decimal.NewFromFloat(150.0)
+		maxPrice := decimal.NewFromFloat(15.0)
 		filters := product.Filter{
 			Category:      "clothing",
 			PriceLessThan: &maxPrice,
 		}
 
-		products, total, err := repo.GetFiltered(0, 10, filters)
+		products, total, err := repo.GetFiltered(context.Background(), 0, 10, filters, product.AllRelations())
 
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), total)
@@ -230,17 +185,17 @@ func TestProductRepository_GetFiltered(t *testing.T) {
 		repo := NewProductRepository(db)
 
 		//First page
-		products1, total1, err := repo.GetFiltered(0, 2, product.Filter{})
+		products1, total1, err := repo.GetFiltered(context.Background(), 0, 2, product.Filter{}, product.AllRelations())
 
 		require.NoError(t, err)
-		assert.Equal(t, int64(5), total1)
+		assert.Equal(t, int64(9), total1)
 		assert.Len(t, products1, 2)
 
 		//Second page
-		products2, total2, err := repo.GetFiltered(2, 2, product.Filter{})
+		products2, total2, err := repo.GetFiltered(context.Background(), 2, 2, product.Filter{}, product.AllRelations())
 
 		require.NoError(t, err)
-		assert.Equal(t, int64(5), total2)
+		assert.Equal(t, int64(9), total2)
 		assert.Len(t, products2, 2)
 
 		assert.NotEqual(t, products1[0].Code, products2[0].Code)
@@ -255,19 +210,40 @@ func TestProductRepository_GetFiltered(t *testing.T) {
 			Category: "nonexistent",
 		}
 
-		products, total, err := repo.GetFiltered(0, 10, filters)
+		products, total, err := repo.GetFiltered(context.Background(), 0, 10, filters, product.AllRelations())
 
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), total)
 		assert.Empty(t, products)
 	})
 
+	t.Run("returns only products with an available variant when in stock", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		products, total, err := repo.GetFiltered(context.Background(), 0, 10, product.Filter{InStock: true}, product.AllRelations())
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), total)
+		require.Len(t, products, 5)
+		for _, code := range []string{"PROD002", "PROD003", "PROD006", "PROD008"} {
+			assert.Nil(t, findProductByCode(products, code), code)
+		}
+		prod1 := findProductByCode(products, "PROD001")
+		require.NotNil(t, prod1)
+		assert.Equal(t, 3, findVariantBySKU(prod1.Variants, "SKU001A").Quantity)
+		prod5 := findProductByCode(products, "PROD005")
+		require.NotNil(t, prod5)
+		assert.False(t, findVariantBySKU(prod5.Variants, "SKU005F").Available())
+	})
+
 	t.Run("preloads category and variants", func(t *testing.T) {
 		db := setupTestDB(t)
 		seedTestData(t, db)
 		repo := NewProductRepository(db)
 
-		products, _, err := repo.GetFiltered(0, 1, product.Filter{Category: "clothing"})
+		products, _, err := repo.GetFiltered(context.Background(), 0, 1, product.Filter{Category: "clothing"}, product.AllRelations())
 
 		require.NoError(t, err)
 		require.Len(t, products, 1)
@@ -279,24 +255,52 @@ func TestProductRepository_GetFiltered(t *testing.T) {
 	})
 }
 
+func TestProductRepository_Relations(t *testing.T) {
+	t.Run("skips relations that are not selected", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		products, _, err := repo.GetFiltered(context.Background(), 0, 1, product.Filter{Category: "clothing"}, product.Relations{})
+
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.Nil(t, products[0].Category)
+		assert.Empty(t, products[0].Variants)
+	})
+
+	t.Run("loads only the selected relation", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		prod, err := repo.GetByCode(context.Background(), "PROD001", product.Relations{Category: true})
+
+		require.NoError(t, err)
+		require.NotNil(t, prod.Category)
+		assert.Equal(t, "clothing", prod.Category.Code)
+		assert.Empty(t, prod.Variants)
+	})
+}
+
 func TestProductRepository_GetByCode(t *testing.T) {
 	t.Run("returns product by code with relations", func(t *testing.T) {
 		db := setupTestDB(t)
 		seedTestData(t, db)
 		repo := NewProductRepository(db)
 
-		prod, err := repo.GetByCode("PROD001")
+		prod, err := repo.GetByCode(context.Background(), "PROD001", product.AllRelations())
 
 		require.NoError(t, err)
 		require.NotNil(t, prod)
 		assert.Equal(t, "PROD001", prod.Code)
-		assert.Equal(t, "89.99", prod.Price.String())
+		assert.Equal(t, "10.99", prod.Price.String())
 
 		require.NotNil(t, prod.Category)
 		assert.Equal(t, "clothing", prod.Category.Code)
 
-		assert.Len(t, prod.Variants, 2)
-		assert.Equal(t, "PROD001-S", prod.Variants[0].SKU)
+		assert.Len(t, prod.Variants, 3)
+		assert.Equal(t, "SKU001A", prod.Variants[0].SKU)
 	})
 
 	t.Run("returns error when product not found", func(t *testing.T) {
@@ -304,20 +308,173 @@ func TestProductRepository_GetByCode(t *testing.T) {
 		seedTestData(t, db)
 		repo := NewProductRepository(db)
 
-		prod, err := repo.GetByCode("NONEXISTENT")
+		prod, err := repo.GetByCode(context.Background(), "NONEXISTENT", product.AllRelations())
 
 		assert.Error(t, err)
 		assert.Nil(t, prod)
 	})
 }
 
+func TestProductRepository_GetByCodes(t *testing.T) {
+	t.Run("returns matching products with relations and ignores unknown codes", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		products, err := repo.GetByCodes(context.Background(), []string{"PROD001", "PROD003", "NONEXISTENT"})
+
+		require.NoError(t, err)
+		assert.Len(t, products, 2)
+
+		prod1 := findProductByCode(products, "PROD001")
+		require.NotNil(t, prod1)
+		require.NotNil(t, prod1.Category)
+		assert.Len(t, prod1.Variants, 3)
+		assert.NotNil(t, findProductByCode(products, "PROD003"))
+	})
+}
+
+func TestProductRepository_GetByVariantSKU(t *testing.T) {
+	t.Run("returns parent product with category and all variants", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		prod, err := repo.GetByVariantSKU(context.Background(), "SKU001B")
+
+		require.NoError(t, err)
+		assert.Equal(t, "PROD001", prod.Code)
+		require.NotNil(t, prod.Category)
+		assert.Equal(t, "clothing", prod.Category.Code)
+		assert.Len(t, prod.Variants, 3)
+	})
+
+	t.Run("returns error when sku does not exist", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		prod, err := repo.GetByVariantSKU(context.Background(), "NONEXISTENT")
+
+		assert.Error(t, err)
+		assert.Nil(t, prod)
+	})
+}
+
+func TestProductRepository_GetByVariantSKUs(t *testing.T) {
+	t.Run("returns the products owning the variants once", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		products, err := repo.GetByVariantSKUs(context.Background(), []string{"SKU001A", "SKU001B", "NOPE"})
+
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.Equal(t, "PROD001", products[0].Code)
+		assert.NotNil(t, products[0].Category)
+		assert.Len(t, products[0].Variants, 3)
+	})
+}
+
+func TestProductRepository_GetFacets(t *testing.T) {
+	t.Run("category facet ignores the category filter", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		facets, err := repo.GetFacets(context.Background(), product.Filter{Category: "clothing"}, product.FacetRequest{Category: true}, product.SaleCriteria{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []product.CategoryCount{
+			{Code: "accessories", Count: 3},
+			{Code: "boots", Count: 1},
+			{Code: "clothing", Count: 3},
+			{Code: "shoes", Count: 2},
+		}, facets.Categories)
+		assert.Nil(t, facets.PriceBuckets)
+		assert.Nil(t, facets.OnSale)
+	})
+
+	t.Run("price facet ignores the price filter but keeps the category filter", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		maxPrice := decimal.NewFromFloat(10.0)
+		facets, err := repo.GetFacets(context.Background(), product.Filter{PriceLessThan: &maxPrice}, product.FacetRequest{Price: true}, product.SaleCriteria{})
+
+		require.NoError(t, err)
+		require.Len(t, facets.PriceBuckets, len(product.DefaultPriceBuckets()))
+		counts := make([]int64, len(facets.PriceBuckets))
+		for i, b := range facets.PriceBuckets {
+			counts[i] = b.Count
+		}
+		assert.Equal(t, []int64{8, 0, 1, 0, 0}, counts)
+
+		facets, err = repo.GetFacets(context.Background(), product.Filter{Category: "clothing"}, product.FacetRequest{Price: true}, product.SaleCriteria{})
+
+		require.NoError(t, err)
+		counts = make([]int64, len(facets.PriceBuckets))
+		for i, b := range facets.PriceBuckets {
+			counts[i] = b.Count
+		}
+		assert.Equal(t, []int64{3, 0, 0, 0, 0}, counts)
+	})
+
+	t.Run("on sale facet matches categories, product codes and variant SKUs", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		sale := product.SaleCriteria{CategoryCodes: []string{"shoes"}, SKUs: []string{"SKU001A", "PROD005"}}
+		facets, err := repo.GetFacets(context.Background(), product.Filter{}, product.FacetRequest{OnSale: true}, sale)
+
+		require.NoError(t, err)
+		require.NotNil(t, facets.OnSale)
+		assert.Equal(t, int64(4), facets.OnSale.OnSale)
+		assert.Equal(t, int64(5), facets.OnSale.NotOnSale)
+	})
+
+	t.Run("on sale facet matches the conditions of expression rules", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		sale := product.SaleCriteria{Conditions: []product.Condition{
+			saleCondition(t, `category == "accessories" and price > 20`),
+			saleCondition(t, `code endsWith "9" and not category in ["shoes", "clothing"]`),
+			saleCondition(t, `code contains "_" or price in [1, 2.5]`),
+		}}
+		facets, err := repo.GetFacets(context.Background(), product.Filter{}, product.FacetRequest{OnSale: true}, sale)
+
+		require.NoError(t, err)
+		require.NotNil(t, facets.OnSale)
+		assert.Equal(t, int64(2), facets.OnSale.OnSale)
+		assert.Equal(t, int64(7), facets.OnSale.NotOnSale)
+	})
+
+	t.Run("on sale facet is zero without sale criteria", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		facets, err := repo.GetFacets(context.Background(), product.Filter{}, product.FacetRequest{OnSale: true}, product.SaleCriteria{})
+
+		require.NoError(t, err)
+		require.NotNil(t, facets.OnSale)
+		assert.Equal(t, int64(0), facets.OnSale.OnSale)
+		assert.Equal(t, int64(9), facets.OnSale.NotOnSale)
+	})
+}
+
 func TestProductRepository_DomainMapping(t *testing.T) {
 	t.Run("correctly maps GORM model to domain entity", func(t *testing.T) {
 		db := setupTestDB(t)
 		seedTestData(t, db)
 		repo := NewProductRepository(db)
 
-		products, err := repo.GetAll()
+		products, err := repo.GetAll(context.Background())
 
 		require.NoError(t, err)
 		prod := findProductByCode(products, "PROD001")
@@ -327,6 +484,9 @@ func TestProductRepository_DomainMapping(t *testing.T) {
 		assert.IsType(t, decimal.Decimal{}, prod.Price)
 		assert.IsType(t, &product.Category{}, prod.Category)
 		assert.IsType(t, []product.Variant{}, prod.Variants)
+		assert.False(t, prod.UpdatedAt.IsZero())
+		assert.False(t, prod.Category.UpdatedAt.IsZero())
+		assert.False(t, prod.Variants[0].UpdatedAt.IsZero())
 	})
 
 	t.Run("variant without price inherits from product", func(t *testing.T) {
@@ -334,13 +494,13 @@ func TestProductRepository_DomainMapping(t *testing.T) {
 		seedTestData(t, db)
 		repo := NewProductRepository(db)
 
-		prod, err := repo.GetByCode("PROD001")
+		prod, err := repo.GetByCode(context.Background(), "PROD001", product.AllRelations())
 
 		require.NoError(t, err)
 
 		var variantWithoutPrice *product.Variant
 		for i, v := range prod.Variants {
-			if v.SKU == "PROD001-L" {
+			if v.SKU == "SKU001B" {
 				variantWithoutPrice = &prod.Variants[i]
 				break
 			}
@@ -352,6 +512,164 @@ func TestProductRepository_DomainMapping(t *testing.T) {
 	})
 }
 
+func TestProductRepository_Upsert(t *testing.T) {
+	t.Run("creates a product with its variants", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		change, err := repo.Upsert(context.Background(), product.Product{
+			Code:     "PROD010",
+			Price:    decimal.RequireFromString("20.00"),
+			Category: &product.Category{Code: "shoes"},
+			Variants: []product.Variant{
+				{SKU: "SKU010A", Name: "A"},
+				{SKU: "SKU010B", Name: "B", Price: decimal.RequireFromString("25.00")},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Created, change)
+
+		prod, err := repo.GetByCode(context.Background(), "PROD010", product.AllRelations())
+		require.NoError(t, err)
+		assert.Equal(t, "shoes", prod.Category.Code)
+		require.Len(t, prod.Variants, 2)
+		assert.Equal(t, "20", findVariantBySKU(prod.Variants, "SKU010A").Price.String())
+		assert.Equal(t, "25", findVariantBySKU(prod.Variants, "SKU010B").Price.String())
+	})
+
+	t.Run("leaves identical products unchanged", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		change, err := repo.Upsert(context.Background(), product.Product{
+			Code:     "PROD001",
+			Price:    decimal.RequireFromString("10.99"),
+			Category: &product.Category{Code: "clothing"},
+			Variants: []product.Variant{{SKU: "SKU001B", Name: "Variant B"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Unchanged, change)
+	})
+
+	t.Run("updates the product and its variants by SKU", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		change, err := repo.Upsert(context.Background(), product.Product{
+			Code:     "PROD001",
+			Price:    decimal.RequireFromString("9.99"),
+			Category: &product.Category{Code: "shoes"},
+			Variants: []product.Variant{{SKU: "SKU001B", Name: "Variant B", Price: decimal.RequireFromString("10.49")}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		prod, err := repo.GetByCode(context.Background(), "PROD001", product.AllRelations())
+		require.NoError(t, err)
+		assert.Equal(t, "9.99", prod.Price.String())
+		assert.Equal(t, "shoes", prod.Category.Code)
+		require.Len(t, prod.Variants, 3, "variants missing from the upsert are kept")
+		assert.Equal(t, "10.49", findVariantBySKU(prod.Variants, "SKU001B").Price.String())
+	})
+
+	t.Run("keeps the cost price unless one is given", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+		ctx := context.Background()
+		costed := product.Product{
+			Code:      "PROD001",
+			Price:     decimal.RequireFromString("10.99"),
+			CostPrice: decimal.RequireFromString("5.50"),
+			Category:  &product.Category{Code: "clothing"},
+		}
+
+		change, err := repo.Upsert(ctx, costed)
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		costed.CostPrice = decimal.Zero
+		change, err = repo.Upsert(ctx, costed)
+		require.NoError(t, err)
+		assert.Equal(t, product.Unchanged, change)
+
+		prod, err := repo.GetByCode(ctx, "PROD001", product.Relations{})
+		require.NoError(t, err)
+		assert.Equal(t, "5.5", prod.CostPrice.String())
+	})
+
+	t.Run("fails on unknown categories", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		_, err := repo.Upsert(context.Background(), product.Product{
+			Code:     "PROD010",
+			Price:    decimal.RequireFromString("20.00"),
+			Category: &product.Category{Code: "hats"},
+		})
+
+		assert.ErrorIs(t, err, product.ErrNotFound)
+		_, err = repo.GetByCode(context.Background(), "PROD010", product.Relations{})
+		assert.ErrorIs(t, err, product.ErrNotFound)
+	})
+}
+
+func TestProductRepository_UpsertVariant(t *testing.T) {
+	db := setupTestDB(t)
+	seedTestData(t, db)
+	repo := NewProductRepository(db)
+	ctx := context.Background()
+
+	t.Run("creates a variant under the product", func(t *testing.T) {
+		change, err := repo.UpsertVariant(ctx, "PROD002", product.Variant{SKU: "PROD002-M", Name: "Medium"})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Created, change)
+		prod, err := repo.GetByVariantSKU(ctx, "PROD002-M")
+		require.NoError(t, err)
+		assert.Equal(t, "PROD002", prod.Code)
+	})
+
+	t.Run("moves a variant to another product", func(t *testing.T) {
+		change, err := repo.UpsertVariant(ctx, "PROD003", product.Variant{SKU: "PROD002-M", Name: "Medium"})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+		prod, err := repo.GetByVariantSKU(ctx, "PROD002-M")
+		require.NoError(t, err)
+		assert.Equal(t, "PROD003", prod.Code)
+	})
+
+	t.Run("fails on unknown products", func(t *testing.T) {
+		_, err := repo.UpsertVariant(ctx, "NONEXISTENT", product.Variant{SKU: "X-1", Name: "X"})
+
+		assert.ErrorIs(t, err, product.ErrNotFound)
+	})
+}
+
+func saleCondition(t *testing.T, source string) product.Condition {
+	t.Helper()
+	expression, err := discount.ParseExpression(source)
+	require.NoError(t, err)
+	return expression.Condition()
+}
+
+func findVariantBySKU(variants []product.Variant, sku string) *product.Variant {
+	for i, v := range variants {
+		if v.SKU == sku {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
 func findProductByCode(products []product.Product, code string) *product.Product {
 	for i, p := range products {
 		if p.Code == code {
@@ -360,3 +678,63 @@ func findProductByCode(products []product.Product, code string) *product.Product
 	}
 	return nil
 }
+
+func TestProductRepository_Stream(t *testing.T) {
+	t.Run("reads every product in batches ordered by id", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		var batches [][]product.Product
+		err := repo.Stream(context.Background(), product.Filter{}, 2, func(products []product.Product) error {
+			batches = append(batches, products)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Len(t, batches, 5)
+		assert.Len(t, batches[0], 2)
+		assert.Len(t, batches[4], 1)
+		var lastID uint
+		for _, batch := range batches {
+			for _, p := range batch {
+				assert.Greater(t, p.ID, lastID)
+				assert.NotNil(t, p.Category)
+				lastID = p.ID
+			}
+		}
+	})
+
+	t.Run("applies the filters", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+
+		var codes []string
+		err := repo.Stream(context.Background(), product.Filter{Category: "clothing"}, 10, func(products []product.Product) error {
+			for _, p := range products {
+				codes = append(codes, p.Code)
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, codes, 3)
+	})
+
+	t.Run("stops at the first callback error", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewProductRepository(db)
+		errStop := errors.New("stop")
+
+		calls := 0
+		err := repo.Stream(context.Background(), product.Filter{}, 2, func([]product.Product) error {
+			calls++
+			return errStop
+		})
+
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, calls)
+	})
+}
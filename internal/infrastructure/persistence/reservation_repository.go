@@ -0,0 +1,313 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reservationModel struct {
+	ID        uint   `gorm:"primaryKey"`
+	Status    string `gorm:"not null;size:16"`
+	ExpiresAt time.Time
+	Items     []reservationItemModel `gorm:"foreignKey:ReservationID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (reservationModel) TableName() string {
+	return "reservations"
+}
+
+type reservationItemModel struct {
+	ID            uint         `gorm:"primaryKey"`
+	ReservationID uint         `gorm:"not null"`
+	VariantID     uint         `gorm:"not null"`
+	Variant       variantModel `gorm:"foreignKey:VariantID"`
+	Warehouse     string       `gorm:"not null;size:32"`
+	Quantity      int          `gorm:"not null"`
+}
+
+func (reservationItemModel) TableName() string {
+	return "reservation_items"
+}
+
+// ReservationRepository implements the reservation repository using GORM.
+// Units are taken out of stock_levels when reserved and put back when a
+// reservation is canceled or expires, so stock always shows what can be sold.
+// StockRepository.Replace subtracts the units still held, to keep that true.
+type ReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewReservationRepository creates a new GORM reservation repository.
+func NewReservationRepository(db *gorm.DB) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// Create reserves the lines in one transaction: either every line is reserved
+// or nothing is. The variant rows are locked in id order, the same lock the stock
+// writes take, so concurrent reservations of the same variant run one at a time
+// and cannot oversell.
+func (r *ReservationRepository) Create(ctx context.Context, lines []inventory.Line, expiresAt time.Time) (inventory.Reservation, error) {
+	model := reservationModel{Status: string(inventory.StatusPending), ExpiresAt: expiresAt}
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		skus := make([]string, len(lines))
+		for i, l := range lines {
+			skus[i] = l.SKU
+		}
+		variants, err := lockVariants(tx.Where("sku IN ?", skus))
+		if err != nil {
+			return err
+		}
+		ids := make(map[string]uint, len(variants))
+		for _, v := range variants {
+			ids[v.SKU] = v.ID
+		}
+
+		for _, l := range lines {
+			variantID, ok := ids[l.SKU]
+			if !ok {
+				return fmt.Errorf("variant %s: %w", l.SKU, product.ErrNotFound)
+			}
+
+			var levels []stockLevelModel
+			if err := tx.Where("variant_id = ?", variantID).Find(&levels).Error; err != nil {
+				return err
+			}
+			taken, err := inventory.Allocate(l.SKU, toDomainStock(l.SKU, levels).Levels, l.Quantity)
+			if err != nil {
+				return err
+			}
+
+			for _, t := range taken {
+				result := tx.Model(&stockLevelModel{}).
+					Where("variant_id = ? AND warehouse = ? AND quantity >= ?", variantID, t.Warehouse, t.Quantity).
+					Update("quantity", gorm.Expr("quantity - ?", t.Quantity))
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					return fmt.Errorf("%w: %s in warehouse %s", inventory.ErrInsufficientStock, l.SKU, t.Warehouse)
+				}
+				model.Items = append(model.Items, reservationItemModel{
+					VariantID: variantID,
+					Variant:   variantModel{ID: variantID, SKU: l.SKU},
+					Warehouse: t.Warehouse,
+					Quantity:  t.Quantity,
+				})
+			}
+		}
+
+		items := model.Items
+		if err := tx.Omit("Items").Create(&model).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].ReservationID = model.ID
+		}
+		return tx.Omit("Variant").Create(&items).Error
+	})
+	if err != nil {
+		return inventory.Reservation{}, err
+	}
+	return toDomainReservation(model), nil
+}
+
+// Get returns a reservation with the units it holds per variant and warehouse.
+func (r *ReservationRepository) Get(ctx context.Context, id uint) (inventory.Reservation, error) {
+	model, err := findReservation(conn(ctx, r.db), id, false)
+	if err != nil {
+		return inventory.Reservation{}, err
+	}
+	return toDomainReservation(model), nil
+}
+
+// Confirm marks a pending reservation as confirmed: its units are sold and
+// stay out of stock.
+func (r *ReservationRepository) Confirm(ctx context.Context, id uint, now time.Time) (inventory.Reservation, error) {
+	return r.close(ctx, id, now, inventory.StatusConfirmed)
+}
+
+// Cancel releases the units of a pending reservation back to stock.
+func (r *ReservationRepository) Cancel(ctx context.Context, id uint, now time.Time) (inventory.Reservation, error) {
+	return r.close(ctx, id, now, inventory.StatusCanceled)
+}
+
+// ReleaseExpired puts the units of every pending reservation expired at now
+// back to stock and marks them expired. Reservations locked by another
+// transaction are skipped and released on a later run, so several replicas can
+// reap at once. The variants of all the selected reservations are locked
+// together, in id order, before any is released. It returns how many
+// reservations were released.
+func (r *ReservationRepository) ReleaseExpired(ctx context.Context, now time.Time) (int, error) {
+	released := 0
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var models []reservationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at <= ?", string(inventory.StatusPending), now).
+			Order("id").
+			Preload("Items").
+			Find(&models).Error
+		if err != nil {
+			return err
+		}
+
+		if err := lockItemVariants(tx, models...); err != nil {
+			return err
+		}
+		for i := range models {
+			if err := release(tx, &models[i], inventory.StatusExpired); err != nil {
+				return err
+			}
+		}
+		released = len(models)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return released, nil
+}
+
+// close moves a pending reservation to status, putting its units back to stock
+// unless it is confirmed. Expired reservations are left to ReleaseExpired.
+func (r *ReservationRepository) close(ctx context.Context, id uint, now time.Time, status inventory.Status) (inventory.Reservation, error) {
+	var model reservationModel
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		model, err = findReservation(tx, id, true)
+		if err != nil {
+			return err
+		}
+		if model.Status != string(inventory.StatusPending) {
+			return fmt.Errorf("%w: reservation %d is %s", inventory.ErrReservationClosed, id, model.Status)
+		}
+		if !now.Before(model.ExpiresAt) {
+			return fmt.Errorf("%w: reservation %d has expired", inventory.ErrReservationClosed, id)
+		}
+
+		if status == inventory.StatusConfirmed {
+			model.Status = string(status)
+			return tx.Model(&model).Update("status", model.Status).Error
+		}
+		if err := lockItemVariants(tx, model); err != nil {
+			return err
+		}
+		return release(tx, &model, status)
+	})
+	if err != nil {
+		return inventory.Reservation{}, err
+	}
+	return toDomainReservation(model), nil
+}
+
+// release puts the units of a reservation back into their warehouses and sets
+// its status. Levels removed since the reservation was made are recreated.
+// The caller holds the locks of its variants, taken with lockItemVariants.
+func release(tx *gorm.DB, model *reservationModel, status inventory.Status) error {
+	for _, item := range model.Items {
+		level := stockLevelModel{VariantID: item.VariantID, Warehouse: item.Warehouse, Quantity: item.Quantity}
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "variant_id"}, {Name: "warehouse"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"quantity":   gorm.Expr("stock_levels.quantity + EXCLUDED.quantity"),
+				"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+			}),
+		}).Create(&level).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	model.Status = string(status)
+	return tx.Model(model).Update("status", model.Status).Error
+}
+
+// lockItemVariants locks the variants held by the reservations, all at once.
+func lockItemVariants(tx *gorm.DB, models ...reservationModel) error {
+	var ids []uint
+	for _, model := range models {
+		for _, item := range model.Items {
+			ids = append(ids, item.VariantID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := lockVariants(tx.Where("id IN ?", ids))
+	return err
+}
+
+// lockVariants locks the variant rows matched by query in id order, so that
+// transactions locking several variants cannot deadlock each other.
+func lockVariants(query *gorm.DB) ([]variantModel, error) {
+	var variants []variantModel
+	err := query.Select("id", "sku").
+		Order("id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Find(&variants).Error
+	return variants, err
+}
+
+// findReservation loads a reservation with its items and their SKUs, optionally locking its row.
+func findReservation(db *gorm.DB, id uint, lock bool) (reservationModel, error) {
+	query := db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("Items.Variant", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "sku")
+	})
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var model reservationModel
+	if err := query.Take(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model, fmt.Errorf("reservation %d: %w", id, inventory.ErrReservationNotFound)
+		}
+		return model, err
+	}
+	return model, nil
+}
+
+// toDomainReservation groups the items of a reservation into one line per SKU,
+// in the order the SKUs were reserved.
+func toDomainReservation(model reservationModel) inventory.Reservation {
+	reservation := inventory.Reservation{
+		ID:        model.ID,
+		Status:    inventory.Status(model.Status),
+		ExpiresAt: model.ExpiresAt,
+		CreatedAt: model.CreatedAt,
+		Lines:     []inventory.Line{},
+	}
+
+	index := make(map[string]int)
+	for _, item := range model.Items {
+		sku := item.Variant.SKU
+		i, ok := index[sku]
+		if !ok {
+			i = len(reservation.Lines)
+			index[sku] = i
+			reservation.Lines = append(reservation.Lines, inventory.Line{SKU: sku})
+		}
+		line := &reservation.Lines[i]
+		line.Quantity += item.Quantity
+		line.Levels = append(line.Levels, inventory.Level{Warehouse: item.Warehouse, Quantity: item.Quantity})
+	}
+	for i := range reservation.Lines {
+		levels := reservation.Lines[i].Levels
+		sort.Slice(levels, func(a, b int) bool { return levels[a].Warehouse < levels[b].Warehouse })
+	}
+	return reservation
+}
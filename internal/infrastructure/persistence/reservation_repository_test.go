@@ -0,0 +1,196 @@
+//go:build integration
+// +build integration
+
+package persistence
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	expiresAt := now.Add(15 * time.Minute)
+
+	quantity := func(t *testing.T, repo *StockRepository, sku string) int {
+		t.Helper()
+		stock, err := repo.GetBySKU(ctx, sku)
+		require.NoError(t, err)
+		return stock.Quantity()
+	}
+
+	t.Run("takes reserved units out of stock and puts them back on cancel", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewReservationRepository(db)
+		stock := NewStockRepository(db)
+
+		res, err := repo.Create(ctx, []inventory.Line{{SKU: "SKU001A", Quantity: 2}}, expiresAt)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.StatusPending, res.Status)
+		assert.Equal(t, []inventory.Line{{SKU: "SKU001A", Quantity: 2, Levels: []inventory.Level{
+			{Warehouse: inventory.DefaultWarehouse, Quantity: 2},
+		}}}, res.Lines)
+		assert.Equal(t, 1, quantity(t, stock, "SKU001A"))
+
+		canceled, err := repo.Cancel(ctx, res.ID, now)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.StatusCanceled, canceled.Status)
+		assert.Equal(t, 3, quantity(t, stock, "SKU001A"))
+
+		_, err = repo.Confirm(ctx, res.ID, now)
+		assert.ErrorIs(t, err, inventory.ErrReservationClosed)
+	})
+
+	t.Run("keeps reserved units out of stock replaced while they are held", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewReservationRepository(db)
+		stock := NewStockRepository(db)
+
+		res, err := repo.Create(ctx, []inventory.Line{{SKU: "SKU001A", Quantity: 2}}, expiresAt)
+		require.NoError(t, err)
+
+		_, err = stock.Replace(ctx, inventory.Stock{SKU: "SKU001A", Levels: []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: 5}}})
+		require.NoError(t, err)
+		assert.Equal(t, 3, quantity(t, stock, "SKU001A"))
+
+		_, err = stock.Replace(ctx, inventory.Stock{SKU: "SKU001A", Levels: []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: 1}}})
+		assert.ErrorIs(t, err, inventory.ErrReservedStock)
+		_, err = stock.Replace(ctx, inventory.Stock{SKU: "SKU001A", Levels: []inventory.Level{{Warehouse: "berlin", Quantity: 5}}})
+		assert.ErrorIs(t, err, inventory.ErrReservedStock)
+		assert.Equal(t, 3, quantity(t, stock, "SKU001A"))
+
+		_, err = repo.Cancel(ctx, res.ID, now)
+		require.NoError(t, err)
+		assert.Equal(t, 5, quantity(t, stock, "SKU001A"))
+	})
+
+	t.Run("reserves nothing when any line is short", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewReservationRepository(db)
+		stock := NewStockRepository(db)
+
+		_, err := repo.Create(ctx, []inventory.Line{{SKU: "SKU001A", Quantity: 1}, {SKU: "SKU002A", Quantity: 1}}, expiresAt)
+		assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+
+		_, err = repo.Create(ctx, []inventory.Line{{SKU: "SKU001A", Quantity: 1}, {SKU: "NOPE", Quantity: 1}}, expiresAt)
+		assert.ErrorIs(t, err, product.ErrNotFound)
+
+		assert.Equal(t, 3, quantity(t, stock, "SKU001A"))
+	})
+
+	t.Run("keeps confirmed units out of stock", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewReservationRepository(db)
+
+		res, err := repo.Create(ctx, []inventory.Line{{SKU: "SKU001A", Quantity: 3}}, expiresAt)
+		require.NoError(t, err)
+		confirmed, err := repo.Confirm(ctx, res.ID, now)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.StatusConfirmed, confirmed.Status)
+
+		stored, err := repo.Get(ctx, res.ID)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.StatusConfirmed, stored.Status)
+		assert.Equal(t, 0, quantity(t, NewStockRepository(db), "SKU001A"))
+
+		released, err := repo.ReleaseExpired(ctx, expiresAt.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, released)
+	})
+
+	t.Run("releases expired reservations", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewReservationRepository(db)
+
+		res, err := repo.Create(ctx, []inventory.Line{{SKU: "SKU001A", Quantity: 3}}, expiresAt)
+		require.NoError(t, err)
+
+		released, err := repo.ReleaseExpired(ctx, now)
+		require.NoError(t, err)
+		assert.Zero(t, released)
+
+		_, err = repo.Confirm(ctx, res.ID, expiresAt)
+		assert.ErrorIs(t, err, inventory.ErrReservationClosed)
+
+		released, err = repo.ReleaseExpired(ctx, expiresAt)
+		require.NoError(t, err)
+		assert.Equal(t, 1, released)
+
+		stored, err := repo.Get(ctx, res.ID)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.StatusExpired, stored.Status)
+		assert.Equal(t, 3, quantity(t, NewStockRepository(db), "SKU001A"))
+	})
+
+	t.Run("releases expired reservations of several variants together", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewReservationRepository(db)
+		stock := NewStockRepository(db)
+
+		_, err := repo.Create(ctx, []inventory.Line{{SKU: "SKU001B", Quantity: 4}}, expiresAt)
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, []inventory.Line{{SKU: "SKU001A", Quantity: 1}, {SKU: "SKU001C", Quantity: 2}}, expiresAt)
+		require.NoError(t, err)
+
+		released, err := repo.ReleaseExpired(ctx, expiresAt)
+		require.NoError(t, err)
+		assert.Equal(t, 2, released)
+		assert.Equal(t, 3, quantity(t, stock, "SKU001A"))
+		assert.Equal(t, 10, quantity(t, stock, "SKU001B"))
+		assert.Equal(t, 17, quantity(t, stock, "SKU001C"))
+	})
+
+	t.Run("does not oversell under concurrent reservations", func(t *testing.T) {
+		db := setupTestDB(t)
+		seedTestData(t, db)
+		repo := NewReservationRepository(db)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		succeeded, short := 0, 0
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.Create(ctx, []inventory.Line{{SKU: "SKU001A", Quantity: 1}}, expiresAt)
+				mu.Lock()
+				defer mu.Unlock()
+				if err == nil {
+					succeeded++
+				} else if assert.ErrorIs(t, err, inventory.ErrInsufficientStock) {
+					short++
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 3, succeeded)
+		assert.Equal(t, 7, short)
+		assert.Equal(t, 0, quantity(t, NewStockRepository(db), "SKU001A"))
+	})
+
+	t.Run("returns not found for unknown reservations", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := NewReservationRepository(db)
+
+		_, err := repo.Get(ctx, 42)
+		assert.ErrorIs(t, err, inventory.ErrReservationNotFound)
+
+		_, err = repo.Cancel(ctx, 42, now)
+		assert.ErrorIs(t, err, inventory.ErrReservationNotFound)
+	})
+}
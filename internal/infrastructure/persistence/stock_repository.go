@@ -0,0 +1,188 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockLevelModel struct {
+	ID        uint   `gorm:"primaryKey"`
+	VariantID uint   `gorm:"not null;uniqueIndex:idx_stock_levels_variant_warehouse"`
+	Warehouse string `gorm:"not null;size:32;uniqueIndex:idx_stock_levels_variant_warehouse"`
+	Quantity  int    `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+func (stockLevelModel) TableName() string {
+	return "stock_levels"
+}
+
+// StockRepository implements the stock repository using GORM.
+type StockRepository struct {
+	db *gorm.DB
+}
+
+// NewStockRepository creates a new GORM stock repository.
+func NewStockRepository(db *gorm.DB) *StockRepository {
+	return &StockRepository{db: db}
+}
+
+// GetBySKU returns the stock of a variant, with levels ordered by warehouse.
+// A variant without levels has no stock.
+func (r *StockRepository) GetBySKU(ctx context.Context, sku string) (inventory.Stock, error) {
+	db := conn(ctx, r.db)
+
+	variantID, err := findVariantID(db, sku, false)
+	if err != nil {
+		return inventory.Stock{}, err
+	}
+
+	var models []stockLevelModel
+	if err := db.Where("variant_id = ?", variantID).Order("warehouse").Find(&models).Error; err != nil {
+		return inventory.Stock{}, err
+	}
+	return toDomainStock(sku, models), nil
+}
+
+// Replace stores stock as the complete set of on-hand levels of its variant:
+// warehouses missing from it are removed. On-hand units include the ones held
+// by pending reservations, which are subtracted before storing, since stored
+// levels only count units that can still be sold. A level below the units
+// held in its warehouse returns inventory.ErrReservedStock. The variant row is
+// locked while levels change, the same lock reservations take.
+func (r *StockRepository) Replace(ctx context.Context, stock inventory.Stock) (product.Change, error) {
+	change := product.Unchanged
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		variantID, err := findVariantID(tx, stock.SKU, true)
+		if err != nil {
+			return err
+		}
+
+		held, err := heldLevels(tx, variantID)
+		if err != nil {
+			return err
+		}
+		available := make([]inventory.Level, len(stock.Levels))
+		for i, l := range stock.Levels {
+			if l.Quantity < held[l.Warehouse] {
+				return fmt.Errorf("%w: %s has %d units reserved in warehouse %s, %d on hand", inventory.ErrReservedStock, stock.SKU, held[l.Warehouse], l.Warehouse, l.Quantity)
+			}
+			available[i] = inventory.Level{Warehouse: l.Warehouse, Quantity: l.Quantity - held[l.Warehouse]}
+			delete(held, l.Warehouse)
+		}
+		if len(held) > 0 {
+			warehouse := slices.Min(slices.Collect(maps.Keys(held)))
+			return fmt.Errorf("%w: %s has %d units reserved in warehouse %s, which is left out", inventory.ErrReservedStock, stock.SKU, held[warehouse], warehouse)
+		}
+
+		var current []stockLevelModel
+		if err := tx.Where("variant_id = ?", variantID).Find(&current).Error; err != nil {
+			return err
+		}
+		if sameLevels(current, available) {
+			return nil
+		}
+
+		warehouses := make([]string, len(available))
+		for i, l := range available {
+			warehouses[i] = l.Warehouse
+		}
+		remove := tx.Where("variant_id = ?", variantID)
+		if len(warehouses) > 0 {
+			remove = remove.Where("warehouse NOT IN ?", warehouses)
+		}
+		if err := remove.Delete(&stockLevelModel{}).Error; err != nil {
+			return err
+		}
+
+		for _, l := range available {
+			model := stockLevelModel{VariantID: variantID, Warehouse: l.Warehouse, Quantity: l.Quantity}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "variant_id"}, {Name: "warehouse"}},
+				DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+			}).Create(&model).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		change = product.Updated
+		if len(current) == 0 {
+			change = product.Created
+		}
+		return nil
+	})
+	if err != nil {
+		return product.Unchanged, err
+	}
+	return change, nil
+}
+
+// heldLevels returns the units of a variant held by pending reservations, per
+// warehouse. Expired reservations hold their units until they are released.
+func heldLevels(db *gorm.DB, variantID uint) (map[string]int, error) {
+	var rows []inventory.Level
+	err := db.Model(&reservationItemModel{}).
+		Select("reservation_items.warehouse AS warehouse, SUM(reservation_items.quantity) AS quantity").
+		Joins("JOIN reservations ON reservations.id = reservation_items.reservation_id").
+		Where("reservation_items.variant_id = ? AND reservations.status = ?", variantID, string(inventory.StatusPending)).
+		Group("reservation_items.warehouse").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	held := make(map[string]int, len(rows))
+	for _, l := range rows {
+		held[l.Warehouse] = l.Quantity
+	}
+	return held, nil
+}
+
+// findVariantID returns the id of the variant with the SKU, optionally locking its row.
+func findVariantID(db *gorm.DB, sku string, lock bool) (uint, error) {
+	query := db.Select("id").Where("sku = ?", sku)
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var variant variantModel
+	if err := query.Take(&variant).Error; err != nil {
+		return 0, fmt.Errorf("variant %s: %w", sku, translateError(err))
+	}
+	return variant.ID, nil
+}
+
+// sameLevels reports whether the stored levels hold exactly the given quantities.
+func sameLevels(stored []stockLevelModel, levels []inventory.Level) bool {
+	if len(stored) != len(levels) {
+		return false
+	}
+	quantities := make(map[string]int, len(stored))
+	for _, m := range stored {
+		quantities[m.Warehouse] = m.Quantity
+	}
+	for _, l := range levels {
+		if q, ok := quantities[l.Warehouse]; !ok || q != l.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+func toDomainStock(sku string, models []stockLevelModel) inventory.Stock {
+	stock := inventory.Stock{SKU: sku, Levels: make([]inventory.Level, len(models))}
+	for i, m := range models {
+		stock.Levels[i] = inventory.Level{Warehouse: m.Warehouse, Quantity: m.Quantity}
+	}
+	return stock
+}
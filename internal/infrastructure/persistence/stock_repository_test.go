@@ -0,0 +1,72 @@
+//go:build integration
+// +build integration
+
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/inventory"
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockRepository(t *testing.T) {
+	db := setupTestDB(t)
+	seedTestData(t, db)
+	repo := NewStockRepository(db)
+	ctx := context.Background()
+
+	t.Run("returns the seeded stock", func(t *testing.T) {
+		stock, err := repo.GetBySKU(ctx, "SKU001A")
+
+		require.NoError(t, err)
+		assert.Equal(t, []inventory.Level{{Warehouse: inventory.DefaultWarehouse, Quantity: 3}}, stock.Levels)
+	})
+
+	t.Run("replaces the levels of a variant", func(t *testing.T) {
+		change, err := repo.Replace(ctx, inventory.Stock{SKU: "SKU001A", Levels: []inventory.Level{
+			{Warehouse: "madrid", Quantity: 1},
+			{Warehouse: "berlin", Quantity: 4},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, product.Updated, change)
+
+		stock, err := repo.GetBySKU(ctx, "SKU001A")
+		require.NoError(t, err)
+		assert.Equal(t, []inventory.Level{{Warehouse: "berlin", Quantity: 4}, {Warehouse: "madrid", Quantity: 1}}, stock.Levels)
+
+		p, err := NewProductRepository(db).GetByVariantSKU(ctx, "SKU001A")
+		require.NoError(t, err)
+		assert.Equal(t, 5, findVariantBySKU(p.Variants, "SKU001A").Quantity)
+	})
+
+	t.Run("leaves identical levels unchanged", func(t *testing.T) {
+		change, err := repo.Replace(ctx, inventory.Stock{SKU: "SKU001A", Levels: []inventory.Level{
+			{Warehouse: "berlin", Quantity: 4},
+			{Warehouse: "madrid", Quantity: 1},
+		}})
+
+		require.NoError(t, err)
+		assert.Equal(t, product.Unchanged, change)
+	})
+
+	t.Run("clears the levels of a variant", func(t *testing.T) {
+		_, err := repo.Replace(ctx, inventory.Stock{SKU: "SKU001A"})
+		require.NoError(t, err)
+
+		stock, err := repo.GetBySKU(ctx, "SKU001A")
+		require.NoError(t, err)
+		assert.Empty(t, stock.Levels)
+	})
+
+	t.Run("returns not found for unknown variants", func(t *testing.T) {
+		_, err := repo.GetBySKU(ctx, "NOPE")
+		assert.ErrorIs(t, err, product.ErrNotFound)
+
+		_, err = repo.Replace(ctx, inventory.Stock{SKU: "NOPE"})
+		assert.ErrorIs(t, err, product.ErrNotFound)
+	})
+}
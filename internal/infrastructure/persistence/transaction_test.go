@@ -0,0 +1,50 @@
+//go:build integration
+// +build integration
+
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/domain/product"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactor_InTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	seedTestData(t, db)
+	repo := NewProductRepository(db)
+	transactor := NewTransactor(db)
+	ctx := context.Background()
+
+	t.Run("commits repository writes", func(t *testing.T) {
+		err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+			_, err := repo.Upsert(ctx, product.Product{Code: "PROD010", Price: decimal.RequireFromString("20.00")})
+			return err
+		})
+
+		require.NoError(t, err)
+		_, err = repo.GetByCode(ctx, "PROD010", product.Relations{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("rolls back repository writes when the function fails", func(t *testing.T) {
+		err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+			if _, err := repo.Upsert(ctx, product.Product{Code: "PROD011", Price: decimal.RequireFromString("20.00")}); err != nil {
+				return err
+			}
+			if _, err := repo.UpsertVariant(ctx, "PROD011", product.Variant{SKU: "SKU011A", Name: "A"}); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+
+		assert.EqualError(t, err, "abort")
+		_, err = repo.GetByCode(ctx, "PROD011", product.Relations{})
+		assert.ErrorIs(t, err, product.ErrNotFound)
+	})
+}
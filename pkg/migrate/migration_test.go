@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("pairs up and down files sorted by version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"010_add_index.up.sql":      {Data: []byte("CREATE INDEX idx ON t(a);")},
+			"002_create_table.up.sql":   {Data: []byte("CREATE TABLE t (a INT);")},
+			"002_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+			"010_add_index.down.sql":    {Data: []byte("DROP INDEX idx;")},
+			"nested/001_ignored.up.sql": {Data: []byte("SELECT 1;")},
+		}
+
+		migrations, err := Load(fsys)
+
+		require.NoError(t, err)
+		require.Len(t, migrations, 2)
+		assert.Equal(t, int64(2), migrations[0].Version)
+		assert.Equal(t, "create_table", migrations[0].Name)
+		assert.Equal(t, "CREATE TABLE t (a INT);", migrations[0].Up)
+		assert.Equal(t, "DROP TABLE t;", migrations[0].Down)
+		assert.Equal(t, int64(10), migrations[1].Version)
+		assert.Equal(t, "add_index", migrations[1].Name)
+	})
+
+	t.Run("checksums the up file", func(t *testing.T) {
+		first, err := Load(fstest.MapFS{"001_a.up.sql": {Data: []byte("SELECT 1;")}})
+		require.NoError(t, err)
+		second, err := Load(fstest.MapFS{"001_a.up.sql": {Data: []byte("SELECT 2;")}})
+		require.NoError(t, err)
+
+		assert.Len(t, first[0].Checksum, 64)
+		assert.NotEqual(t, first[0].Checksum, second[0].Checksum)
+	})
+
+	t.Run("allows migrations without a down file", func(t *testing.T) {
+		migrations, err := Load(fstest.MapFS{"001_a.up.sql": {Data: []byte("SELECT 1;")}})
+
+		require.NoError(t, err)
+		assert.Empty(t, migrations[0].Down)
+	})
+
+	t.Run("rejects invalid sets", func(t *testing.T) {
+		tests := map[string]fstest.MapFS{
+			"bad name":        {"create_table.sql": {Data: []byte("SELECT 1;")}},
+			"zero version":    {"000_a.up.sql": {Data: []byte("SELECT 1;")}},
+			"missing up file": {"001_a.down.sql": {Data: []byte("SELECT 1;")}},
+			"name conflict": {
+				"001_a.up.sql":   {Data: []byte("SELECT 1;")},
+				"001_b.down.sql": {Data: []byte("SELECT 1;")},
+			},
+		}
+		for name, fsys := range tests {
+			_, err := Load(fsys)
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("loads the repository migrations", func(t *testing.T) {
+		migrations, err := Load(os.DirFS("../../sql/migrations"))
+
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations)
+		for _, m := range migrations {
+			assert.NotEmpty(t, m.Down, "%d_%s has no down file", m.Version, m.Name)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockKey identifies the advisory lock that serializes migration steps
+// between processes migrating the same database.
+const lockKey = 4_271_994
+
+var (
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	ErrMissingMigration = errors.New("applied migration has no file")
+	ErrIrreversible     = errors.New("migration has no down file")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+)
+
+// Direction tells whether a step applied or reverted a migration.
+type Direction string
+
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+// Step is a migration applied or reverted by the Migrator.
+type Step struct {
+	Migration
+	Direction Direction
+}
+
+// State is the status of one migration, known from its files, the
+// schema_migrations table or both.
+type State struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the applied checksum differs from the file.
+	Modified bool
+	// Missing is set when an applied version has no file anymore.
+	Missing bool
+}
+
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies and reverts migrations, recording them in schema_migrations.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New creates a migrator for the given migrations, sorted by version as Load returns them.
+func New(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Status reports every known migration, whether or not it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]State, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]State, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		state := State{Migration: migration}
+		if a, ok := applied[migration.Version]; ok {
+			state.Applied = true
+			state.AppliedAt = a.AppliedAt
+			state.Modified = a.Checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		states = append(states, state)
+	}
+	for _, a := range applied {
+		states = append(states, State{
+			Migration: Migration{Version: a.Version, Name: a.Name, Checksum: a.Checksum},
+			Applied:   true,
+			AppliedAt: a.AppliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Version < states[j].Version
+	})
+
+	return states, nil
+}
+
+// Pending returns the migrations that are not applied yet. It fails when an
+// applied migration was modified or removed.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	states, err := m.verifiedStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, state := range states {
+		if !state.Applied {
+			pending = append(pending, state.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) ([]Step, error) {
+	var latest int64
+	if len(m.migrations) > 0 {
+		latest = m.migrations[len(m.migrations)-1].Version
+	}
+	return m.Goto(ctx, latest)
+}
+
+// Down reverts the latest applied migration. It does nothing when none is applied.
+func (m *Migrator) Down(ctx context.Context) ([]Step, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+	states, err := m.verifiedStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []int64
+	for _, state := range states {
+		if state.Applied {
+			applied = append(applied, state.Version)
+		}
+	}
+	if len(applied) == 0 {
+		return nil, nil
+	}
+
+	var target int64
+	if len(applied) > 1 {
+		target = applied[len(applied)-2]
+	}
+	return m.Goto(ctx, target)
+}
+
+// Goto applies the pending migrations up to version and reverts the applied
+// ones above it, so that exactly the migrations up to version are applied.
+// Version 0 reverts every migration.
+func (m *Migrator) Goto(ctx context.Context, version int64) ([]Step, error) {
+	if version != 0 && m.find(version) == nil {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	states, err := m.verifiedStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []Step
+	for i := len(states) - 1; i >= 0; i-- {
+		if state := states[i]; state.Applied && state.Version > version {
+			if state.Down == "" {
+				return nil, fmt.Errorf("%w: %d_%s", ErrIrreversible, state.Version, state.Name)
+			}
+			steps = append(steps, Step{Migration: state.Migration, Direction: DirectionDown})
+		}
+	}
+	for _, state := range states {
+		if !state.Applied && state.Version <= version {
+			steps = append(steps, Step{Migration: state.Migration, Direction: DirectionUp})
+		}
+	}
+
+	done := make([]Step, 0, len(steps))
+	for _, step := range steps {
+		ran, err := m.run(ctx, step)
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s %s: %w", step.Version, step.Name, step.Direction, err)
+		}
+		if ran {
+			done = append(done, step)
+		}
+	}
+	return done, nil
+}
+
+// run executes one step and records it in a single transaction. Steps that
+// another process completed in the meantime are skipped.
+func (m *Migrator) run(ctx context.Context, step Step) (bool, error) {
+	ran := false
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Raw("SELECT COUNT(*) FROM schema_migrations WHERE version = ?", step.Version).Scan(&count).Error; err != nil {
+			return err
+		}
+		if (step.Direction == DirectionUp) == (count > 0) {
+			return nil
+		}
+
+		if step.Direction == DirectionUp {
+			if err := tx.Exec(step.Up).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+				step.Version, step.Name, step.Checksum).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Exec(step.Down).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", step.Version).Error; err != nil {
+				return err
+			}
+		}
+
+		ran = true
+		return nil
+	})
+	return ran, err
+}
+
+// verifiedStatus returns the status and fails when applied migrations do not match their files.
+func (m *Migrator) verifiedStatus(ctx context.Context) ([]State, error) {
+	states, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, state := range states {
+		if state.Missing {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMissingMigration, state.Version, state.Name)
+		}
+		if state.Modified {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, state.Version, state.Name)
+		}
+	}
+	return states, nil
+}
+
+// createTable creates schema_migrations unless it exists. Only the commands
+// that change the schema call it, so reading the status needs no DDL rights.
+func (m *Migrator) createTable(ctx context.Context) error {
+	err := m.db.WithContext(ctx).Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(256) NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+	)`).Error
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// applied returns the recorded migrations by version. A database without
+// schema_migrations has none applied.
+func (m *Migrator) applied(ctx context.Context) (map[int64]appliedMigration, error) {
+	db := m.db.WithContext(ctx)
+	var exists bool
+	if err := db.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error; err != nil {
+		return nil, fmt.Errorf("reading schema_migrations: %w", err)
+	}
+	if !exists {
+		return map[int64]appliedMigration{}, nil
+	}
+
+	var rows []appliedMigration
+	if err := db.Raw("SELECT version, name, checksum, applied_at FROM schema_migrations").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("reading schema_migrations: %w", err)
+	}
+
+	applied := make(map[int64]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
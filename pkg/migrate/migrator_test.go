@@ -0,0 +1,210 @@
+//go:build integration
+// +build integration
+
+package migrate
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an empty postgres testcontainer for IT
+func setupTestDB(t *testing.T) *gorm.DB {
+	ctx := context.Background()
+
+	postgresContainer, err := postgres.Run(ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("testdb"),
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpass"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60*time.Second)),
+	)
+	require.NoError(t, err, "Failed to start PostgreSQL container")
+
+	t.Cleanup(func() {
+		if err := testcontainers.TerminateContainer(postgresContainer); err != nil {
+			t.Logf("failed to terminate container: %s", err)
+		}
+	})
+
+	connStr, err := postgresContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err, "Failed to get connection string")
+
+	db, err := gorm.Open(pgdriver.Open(connStr), &gorm.Config{})
+	require.NoError(t, err, "Failed to connect to PostgreSQL container")
+
+	return db
+}
+
+func loadRepositoryMigrations(t *testing.T) []Migration {
+	migrations, err := Load(os.DirFS("../../sql/migrations"))
+	require.NoError(t, err)
+	return migrations
+}
+
+func tableExists(t *testing.T, db *gorm.DB, table string) bool {
+	var exists bool
+	require.NoError(t, db.Raw("SELECT to_regclass(?) IS NOT NULL", table).Scan(&exists).Error)
+	return exists
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("applies and reverts the repository migrations", func(t *testing.T) {
+		db := setupTestDB(t)
+		migrations := loadRepositoryMigrations(t)
+		migrator := New(db, migrations)
+
+		steps, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Len(t, steps, len(migrations))
+		assert.True(t, tableExists(t, db, "products"))
+		assert.True(t, tableExists(t, db, "categories"))
+
+		pending, err := migrator.Pending(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		steps, err = migrator.Goto(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, steps, len(migrations))
+		assert.Equal(t, DirectionDown, steps[0].Direction)
+		assert.Equal(t, migrations[len(migrations)-1].Version, steps[0].Version)
+		assert.False(t, tableExists(t, db, "products"))
+	})
+
+	t.Run("does nothing when up to date", func(t *testing.T) {
+		db := setupTestDB(t)
+		migrator := New(db, loadRepositoryMigrations(t))
+
+		_, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		steps, err := migrator.Up(ctx)
+
+		require.NoError(t, err)
+		assert.Empty(t, steps)
+	})
+
+	t.Run("reports status and reverts one migration at a time", func(t *testing.T) {
+		db := setupTestDB(t)
+		migrations := loadRepositoryMigrations(t)
+		migrator := New(db, migrations)
+
+		_, err := migrator.Goto(ctx, migrations[1].Version)
+		require.NoError(t, err)
+
+		states, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		require.Len(t, states, len(migrations))
+		assert.True(t, states[0].Applied)
+		assert.True(t, states[1].Applied)
+		assert.False(t, states[1].AppliedAt.IsZero())
+		assert.False(t, states[2].Applied)
+
+		steps, err := migrator.Down(ctx)
+		require.NoError(t, err)
+		require.Len(t, steps, 1)
+		assert.Equal(t, migrations[1].Version, steps[0].Version)
+
+		pending, err := migrator.Pending(ctx)
+		require.NoError(t, err)
+		assert.Len(t, pending, len(migrations)-1)
+	})
+
+	t.Run("reads the status without creating schema_migrations", func(t *testing.T) {
+		db := setupTestDB(t)
+		migrations := loadRepositoryMigrations(t)
+		migrator := New(db, migrations)
+
+		states, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		require.Len(t, states, len(migrations))
+		assert.False(t, states[0].Applied)
+
+		pending, err := migrator.Pending(ctx)
+		require.NoError(t, err)
+		assert.Len(t, pending, len(migrations))
+		assert.False(t, tableExists(t, db, "schema_migrations"))
+	})
+
+	t.Run("rolls back a failing migration", func(t *testing.T) {
+		db := setupTestDB(t)
+		migrator := New(db, []Migration{
+			{Version: 1, Name: "create_a", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;", Checksum: "1"},
+			{Version: 2, Name: "broken", Up: "CREATE TABLE b (id INT); SELECT * FROM missing;", Checksum: "2"},
+		})
+
+		steps, err := migrator.Up(ctx)
+
+		require.Error(t, err)
+		assert.Len(t, steps, 1)
+		assert.False(t, tableExists(t, db, "b"))
+		pending, err := migrator.Pending(ctx)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, int64(2), pending[0].Version)
+	})
+
+	t.Run("refuses to run when an applied migration was modified", func(t *testing.T) {
+		db := setupTestDB(t)
+		migrations := []Migration{{Version: 1, Name: "create_a", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;", Checksum: "1"}}
+		_, err := New(db, migrations).Up(ctx)
+		require.NoError(t, err)
+
+		migrations[0].Checksum = "changed"
+		migrator := New(db, migrations)
+
+		_, err = migrator.Up(ctx)
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+		_, err = migrator.Pending(ctx)
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+		states, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.True(t, states[0].Modified)
+	})
+
+	t.Run("refuses to run when an applied migration has no file", func(t *testing.T) {
+		db := setupTestDB(t)
+		_, err := New(db, []Migration{{Version: 1, Name: "create_a", Up: "CREATE TABLE a (id INT);", Checksum: "1"}}).Up(ctx)
+		require.NoError(t, err)
+
+		_, err = New(db, nil).Up(ctx)
+
+		assert.ErrorIs(t, err, ErrMissingMigration)
+	})
+
+	t.Run("refuses to revert a migration without down file", func(t *testing.T) {
+		db := setupTestDB(t)
+		migrator := New(db, []Migration{{Version: 1, Name: "create_a", Up: "CREATE TABLE a (id INT);", Checksum: "1"}})
+		_, err := migrator.Up(ctx)
+		require.NoError(t, err)
+
+		_, err = migrator.Down(ctx)
+
+		assert.ErrorIs(t, err, ErrIrreversible)
+		assert.True(t, tableExists(t, db, "a"))
+	})
+
+	t.Run("rejects unknown versions", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		_, err := New(db, loadRepositoryMigrations(t)).Goto(ctx, 999)
+
+		assert.ErrorIs(t, err, ErrUnknownVersion)
+	})
+}
@@ -4,4 +4,4 @@ CREATE TABLE IF NOT EXISTS categories (
     name VARCHAR(256) NOT NULL,
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
-    );
+);
@@ -0,0 +1,2 @@
+-- Products are upserted by code.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_code ON products(code);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS discount_rules (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('category', 'sku')),
+    target VARCHAR(32) NOT NULL,
+    percentage INTEGER NOT NULL CHECK (percentage BETWEEN 0 AND 100),
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (kind, target)
+);
+
+-- The rules the engine was built with before they were stored:
+-- 30% off boots category, 15% off SKU 000003.
+INSERT INTO discount_rules (kind, target, percentage, position) VALUES
+    ('category', 'boots', 30, 1),
+    ('sku', '000003', 15, 2)
+ON CONFLICT (kind, target) DO NOTHING;
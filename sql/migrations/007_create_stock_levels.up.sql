@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS stock_levels (
+    id SERIAL PRIMARY KEY,
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    warehouse VARCHAR(32) NOT NULL DEFAULT 'default',
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (variant_id, warehouse)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS basket_rules;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS basket_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('bundle', 'threshold', 'mix')),
+    -- Comma-separated category codes; empty matches every category.
+    categories VARCHAR(255) NOT NULL DEFAULT '',
+    quantity INTEGER NOT NULL DEFAULT 0,
+    free INTEGER NOT NULL DEFAULT 0,
+    min_spend DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    percentage INTEGER NOT NULL DEFAULT 0 CHECK (percentage BETWEEN 0 AND 100),
+    precedence VARCHAR(32) NOT NULL CHECK (precedence IN ('after_items', 'exclude_discounted')),
+    stop BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE basket_rules DROP COLUMN IF EXISTS coupon_only;
@@ -0,0 +1,3 @@
+-- Coupon-only rules are applied to quotes only when a linked coupon is supplied.
+ALTER TABLE basket_rules
+ADD COLUMN IF NOT EXISTS coupon_only BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS coupons (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    basket_rule_id INTEGER NOT NULL REFERENCES basket_rules(id) ON DELETE CASCADE,
+    -- NULL bounds leave the validity window open on that side.
+    starts_at TIMESTAMPTZ NULL,
+    ends_at TIMESTAMPTZ NULL,
+    -- 0 means unlimited.
+    max_redemptions INTEGER NOT NULL DEFAULT 0 CHECK (max_redemptions >= 0),
+    max_per_customer INTEGER NOT NULL DEFAULT 0 CHECK (max_per_customer >= 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at)
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id SERIAL PRIMARY KEY,
+    coupon_id INTEGER NOT NULL REFERENCES coupons(id) ON DELETE CASCADE,
+    customer_id VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Limits are checked by counting redemptions per coupon and per customer.
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_customer ON coupon_redemptions (coupon_id, customer_id);
@@ -0,0 +1,8 @@
+ALTER TABLE discount_rules DROP CONSTRAINT IF EXISTS discount_rules_kind_target_audience_key;
+DELETE FROM discount_rules WHERE segments <> '' OR markets <> '' OR channels <> '';
+ALTER TABLE discount_rules ADD CONSTRAINT discount_rules_kind_target_key UNIQUE (kind, target);
+
+ALTER TABLE discount_rules
+DROP COLUMN IF EXISTS segments,
+DROP COLUMN IF EXISTS markets,
+DROP COLUMN IF EXISTS channels;
@@ -0,0 +1,11 @@
+-- Comma-separated segments, markets and channels a rule is restricted to;
+-- empty lists match every customer.
+ALTER TABLE discount_rules
+ADD COLUMN IF NOT EXISTS segments VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS markets VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS channels VARCHAR(255) NOT NULL DEFAULT '';
+
+-- The same target may now be discounted differently for each audience.
+ALTER TABLE discount_rules DROP CONSTRAINT IF EXISTS discount_rules_kind_target_key;
+ALTER TABLE discount_rules
+ADD CONSTRAINT discount_rules_kind_target_audience_key UNIQUE (kind, target, segments, markets, channels);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS discount_caps;
+
+ALTER TABLE products DROP COLUMN IF EXISTS cost_price;
@@ -0,0 +1,12 @@
+-- What a product costs the business; NULL when unknown. Discounts never
+-- take a product below it.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cost_price DECIMAL(10, 2);
+
+-- Highest discount percentage the products of a category may get.
+CREATE TABLE IF NOT EXISTS discount_caps (
+    id SERIAL PRIMARY KEY,
+    category_code VARCHAR(32) NOT NULL UNIQUE,
+    percentage INTEGER NOT NULL CHECK (percentage BETWEEN 0 AND 100),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
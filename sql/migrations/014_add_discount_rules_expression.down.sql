@@ -0,0 +1,7 @@
+DELETE FROM discount_rules WHERE kind = 'expression';
+
+ALTER TABLE discount_rules DROP CONSTRAINT IF EXISTS discount_rules_kind_check;
+ALTER TABLE discount_rules
+ADD CONSTRAINT discount_rules_kind_check CHECK (kind IN ('category', 'sku'));
+
+ALTER TABLE discount_rules DROP COLUMN IF EXISTS expression;
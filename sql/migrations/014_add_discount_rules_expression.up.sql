@@ -0,0 +1,8 @@
+-- Expression rules name themselves by target and match the products their
+-- condition holds for; other kinds leave the expression empty.
+ALTER TABLE discount_rules
+ADD COLUMN IF NOT EXISTS expression TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE discount_rules DROP CONSTRAINT IF EXISTS discount_rules_kind_check;
+ALTER TABLE discount_rules
+ADD CONSTRAINT discount_rules_kind_check CHECK (kind IN ('category', 'sku', 'expression'));